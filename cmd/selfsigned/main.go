@@ -0,0 +1,38 @@
+// Command selfsigned writes a certificate and a key for local development
+// and tests, e.g.
+//
+//	go run ./cmd/selfsigned -hosts localhost,127.0.0.1
+//	TLS_CERT_FILE=cert.pem TLS_KEY_FILE=key.pem TLS_CLIENT_CA_FILE=cert.pem ...
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/project/library/internal/certs"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "comma separated names and IP addresses of the certificate")
+	certFile := flag.String("cert", "cert.pem", "certificate file to write")
+	keyFile := flag.String("key", "key.pem", "key file to write")
+	validFor := flag.Duration("valid-for", 365*24*time.Hour, "validity of the certificate")
+	flag.Parse()
+
+	certPEM, keyPEM, err := certs.SelfSigned(*validFor, strings.Split(*hosts, ",")...)
+
+	if err != nil {
+		log.Fatalf("can not generate certificate: %s", err)
+	}
+
+	if err = os.WriteFile(*certFile, certPEM, 0o600); err != nil {
+		log.Fatalf("can not write certificate: %s", err)
+	}
+
+	if err = os.WriteFile(*keyFile, keyPEM, 0o600); err != nil {
+		log.Fatalf("can not write key: %s", err)
+	}
+}
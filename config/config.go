@@ -1,18 +1,520 @@
 package config
 
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type (
 	Config struct {
 		GRPC
+		PG
+		Outbox
+		History
+		Loans
+		Covers
+		Idempotency
+		Auth
+		TLS
+		RateLimit
 	}
 
 	GRPC struct {
 		Port        string `env:"GRPC_PORT"`
 		GatewayPort string `env:"GRPC_GATEWAY_PORT"`
 	}
+
+	PG struct {
+		URL      string
+		Host     string `env:"POSTGRES_HOST"`
+		Port     string `env:"POSTGRES_PORT"`
+		DB       string `env:"POSTGRES_DB"`
+		User     string `env:"POSTGRES_USER"`
+		Password string `env:"POSTGRES_PASSWORD"`
+		MaxConn  string `env:"POSTGRES_MAX_CONN"`
+	}
+
+	Outbox struct {
+		Enabled         bool          `env:"OUTBOX_ENABLED"`
+		Workers         int           `env:"OUTBOX_WORKERS"`
+		BatchSize       int           `env:"OUTBOX_BATCH_SIZE"`
+		WaitTimeMS      time.Duration `env:"OUTBOX_WAIT_TIME_MS"`
+		InProgressTTLMS time.Duration `env:"OUTBOX_IN_PROGRESS_TTL_MS"`
+		AuthorSendURL   string        `env:"OUTBOX_AUTHOR_SEND_URL"`
+		BookSendURL     string        `env:"OUTBOX_BOOK_SEND_URL"`
+		PatronSendURL   string        `env:"OUTBOX_PATRON_SEND_URL"`
+		LoanSendURL     string        `env:"OUTBOX_LOAN_SEND_URL"`
+		HoldSendURL     string        `env:"OUTBOX_HOLD_SEND_URL"`
+		MergeSendURL    string        `env:"OUTBOX_AUTHOR_MERGE_SEND_URL"`
+	}
+
+	History struct {
+		// RetentionDays is how long superseded versions of authors and
+		// books are kept for point-in-time reads, 0 keeps them forever.
+		RetentionDays int `env:"HISTORY_RETENTION_DAYS"`
+	}
+
+	Loans struct {
+		// The loan periods of the membership types in days.
+		StandardPeriodDays int `env:"LOAN_PERIOD_DAYS_STANDARD"`
+		StudentPeriodDays  int `env:"LOAN_PERIOD_DAYS_STUDENT"`
+		SeniorPeriodDays   int `env:"LOAN_PERIOD_DAYS_SENIOR"`
+		StaffPeriodDays    int `env:"LOAN_PERIOD_DAYS_STAFF"`
+		MaxActive          int `env:"LOAN_MAX_ACTIVE"`
+		MaxRenewals        int `env:"LOAN_MAX_RENEWALS"`
+		// HoldPickupDays is how long a copy put aside for a hold waits.
+		HoldPickupDays int `env:"HOLD_PICKUP_DAYS"`
+		// Fines are in cents, a zero cap means no limit per loan.
+		FineDailyRate  int `env:"FINE_DAILY_RATE_CENTS"`
+		FineCap        int `env:"FINE_CAP_CENTS"`
+		FineMaxBalance int `env:"FINE_MAX_BALANCE_CENTS"`
+	}
+
+	Covers struct {
+		// Storage is where cover images are kept, fs or s3.
+		Storage string `env:"COVER_STORAGE"`
+		// Dir holds the covers of the fs storage, the gateway serves them
+		// under PublicURL.
+		Dir          string `env:"COVER_DIR"`
+		PublicURL    string `env:"COVER_PUBLIC_URL"`
+		MaxSizeBytes int    `env:"COVER_MAX_SIZE_BYTES"`
+		S3Endpoint   string `env:"COVER_S3_ENDPOINT"`
+		S3Region     string `env:"COVER_S3_REGION"`
+		S3Bucket     string `env:"COVER_S3_BUCKET"`
+		S3AccessKey  string `env:"COVER_S3_ACCESS_KEY_ID"`
+		S3SecretKey  string `env:"COVER_S3_SECRET_ACCESS_KEY"`
+	}
+
+	Idempotency struct {
+		// TTLHours is how long the responses to requests with an
+		// idempotency key are replayed, 0 ignores the keys.
+		TTLHours int `env:"IDEMPOTENCY_TTL_HOURS"`
+		// DeadlineSeconds bounds the requests made with a key.
+		DeadlineSeconds int `env:"IDEMPOTENCY_DEADLINE_SECONDS"`
+		// PendingSeconds is how long the key of a request that has not
+		// completed stays reserved before a retry takes it over. It is at
+		// least twice the deadline, so the first request is over by then.
+		PendingSeconds int `env:"IDEMPOTENCY_PENDING_SECONDS"`
+	}
+
+	Auth struct {
+		// Bearer tokens are verified with the key set read from JWKSFile or
+		// downloaded from JWKSURL, the callers without credentials are then
+		// granted the methods of the public role. Without either the
+		// endpoints are open to anonymous callers, except for the ApiKeys
+		// service. The callers sending an API key are held to its scopes in
+		// either case.
+		JWKSFile string `env:"AUTH_JWKS_FILE"`
+		JWKSURL  string `env:"AUTH_JWKS_URL"`
+		// Issuer and Audience are required of the tokens, iss and aud.
+		Issuer   string `env:"AUTH_ISSUER"`
+		Audience string `env:"AUTH_AUDIENCE"`
+		// RolesClaim names the claim listing the roles of the caller.
+		RolesClaim string `env:"AUTH_ROLES_CLAIM"`
+		// PolicyFile replaces the built-in policy granting the roles access
+		// to the methods.
+		PolicyFile string `env:"AUTH_POLICY_FILE"`
+	}
+
+	TLS struct {
+		// CertFile and KeyFile make the gRPC and gateway listeners serve
+		// TLS. Both files are reread when they change on disk.
+		CertFile string `env:"TLS_CERT_FILE"`
+		KeyFile  string `env:"TLS_KEY_FILE"`
+		// ClientCAFile makes the listeners require client certificates
+		// signed by one of its CAs.
+		ClientCAFile string `env:"TLS_CLIENT_CA_FILE"`
+		// MinVersion is set by TLS_MIN_VERSION as 1.2 or 1.3.
+		MinVersion uint16
+	}
+
+	// RateLimit throttles each client, known by its principal or its IP
+	// address.
+	RateLimit struct {
+		// Default is shared by the methods without a limit of their own,
+		// RATE_LIMIT_DEFAULT is rate:burst in requests per second.
+		Default Limit
+		// Methods are set by RATE_LIMIT_METHODS as comma separated
+		// Method=rate:burst pairs. The methods of the Library service may
+		// be given by their names alone.
+		Methods map[string]Limit
+		// AuthorBooksStreams caps the concurrent GetAuthorBooks streams of
+		// a client.
+		AuthorBooksStreams int `env:"RATE_LIMIT_AUTHOR_BOOKS_STREAMS"`
+	}
+
+	// Limit is a token bucket refilled at Rate tokens per second up to
+	// Burst. A zero Rate does not limit.
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+)
+
+const (
+	CoverStorageFS = "fs"
+	CoverStorageS3 = "s3"
 )
 
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 
+	cfg.GRPC.Port = os.Getenv("GRPC_PORT")
+	cfg.GRPC.GatewayPort = os.Getenv("GRPC_GATEWAY_PORT")
+
+	cfg.PG.Host = os.Getenv("POSTGRES_HOST")
+	cfg.PG.Port = os.Getenv("POSTGRES_PORT")
+	cfg.PG.DB = os.Getenv("POSTGRES_DB")
+	cfg.PG.User = os.Getenv("POSTGRES_USER")
+	cfg.PG.Password = os.Getenv("POSTGRES_PASSWORD")
+	cfg.PG.MaxConn = os.Getenv("POSTGRES_MAX_CONN")
+	cfg.PG.URL = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&pool_max_conns=%s",
+		url.QueryEscape(cfg.PG.User),
+		url.QueryEscape(cfg.PG.Password),
+		cfg.PG.Host,
+		cfg.PG.Port,
+		cfg.PG.DB,
+		cfg.PG.MaxConn,
+	)
+
+	if err := parseOutbox(&cfg.Outbox); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if cfg.History.RetentionDays, err = getIntOrDefault("HISTORY_RETENTION_DAYS", 0); err != nil {
+		return nil, err
+	}
+
+	if err = parseLoans(&cfg.Loans); err != nil {
+		return nil, err
+	}
+
+	if err = parseCovers(&cfg.Covers); err != nil {
+		return nil, err
+	}
+
+	if err = parseIdempotency(&cfg.Idempotency); err != nil {
+		return nil, err
+	}
+
+	if err = parseAuth(&cfg.Auth); err != nil {
+		return nil, err
+	}
+
+	if err = parseTLS(&cfg.TLS); err != nil {
+		return nil, err
+	}
+
+	if err = parseRateLimit(&cfg.RateLimit); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+func parseLoans(cfg *Loans) error {
+	settings := []struct {
+		target   *int
+		key      string
+		fallback int
+		minimum  int
+	}{
+		{&cfg.StandardPeriodDays, "LOAN_PERIOD_DAYS_STANDARD", 21, 1},
+		{&cfg.StudentPeriodDays, "LOAN_PERIOD_DAYS_STUDENT", 28, 1},
+		{&cfg.SeniorPeriodDays, "LOAN_PERIOD_DAYS_SENIOR", 28, 1},
+		{&cfg.StaffPeriodDays, "LOAN_PERIOD_DAYS_STAFF", 42, 1},
+		{&cfg.MaxActive, "LOAN_MAX_ACTIVE", 10, 0},
+		{&cfg.MaxRenewals, "LOAN_MAX_RENEWALS", 2, 0},
+		{&cfg.HoldPickupDays, "HOLD_PICKUP_DAYS", 7, 1},
+		{&cfg.FineDailyRate, "FINE_DAILY_RATE_CENTS", 25, 0},
+		{&cfg.FineCap, "FINE_CAP_CENTS", 1000, 0},
+		{&cfg.FineMaxBalance, "FINE_MAX_BALANCE_CENTS", 500, 0},
+	}
+
+	for _, setting := range settings {
+		value, err := getIntOrDefault(setting.key, setting.fallback)
+
+		if err != nil {
+			return err
+		}
+
+		if value < setting.minimum {
+			return fmt.Errorf("%s must be at least %d", setting.key, setting.minimum)
+		}
+
+		*setting.target = value
+	}
+
+	return nil
+}
+
+func parseCovers(cfg *Covers) error {
+	cfg.Storage = getStringOrDefault("COVER_STORAGE", CoverStorageFS)
+	cfg.Dir = getStringOrDefault("COVER_DIR", "covers")
+	cfg.PublicURL = os.Getenv("COVER_PUBLIC_URL")
+	cfg.S3Endpoint = os.Getenv("COVER_S3_ENDPOINT")
+	cfg.S3Region = getStringOrDefault("COVER_S3_REGION", "us-east-1")
+	cfg.S3Bucket = os.Getenv("COVER_S3_BUCKET")
+	cfg.S3AccessKey = os.Getenv("COVER_S3_ACCESS_KEY_ID")
+	cfg.S3SecretKey = os.Getenv("COVER_S3_SECRET_ACCESS_KEY")
+
+	var err error
+
+	if cfg.MaxSizeBytes, err = getIntOrDefault("COVER_MAX_SIZE_BYTES", 5<<20); err != nil {
+		return err
+	}
+
+	if cfg.MaxSizeBytes < 1 {
+		return errors.New("COVER_MAX_SIZE_BYTES must be at least 1")
+	}
+
+	switch cfg.Storage {
+	case CoverStorageFS:
+		if cfg.PublicURL == "" {
+			cfg.PublicURL = "/covers"
+		}
+	case CoverStorageS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return errors.New("COVER_S3_ENDPOINT and COVER_S3_BUCKET are required for the s3 cover storage")
+		}
+	default:
+		return fmt.Errorf("unknown COVER_STORAGE %q", cfg.Storage)
+	}
+
+	return nil
+}
+
+func parseIdempotency(cfg *Idempotency) error {
+	var err error
+
+	if cfg.TTLHours, err = getIntOrDefault("IDEMPOTENCY_TTL_HOURS", 24); err != nil {
+		return err
+	}
+
+	if cfg.DeadlineSeconds, err = getIntOrDefault("IDEMPOTENCY_DEADLINE_SECONDS", 30); err != nil {
+		return err
+	}
+
+	if cfg.PendingSeconds, err = getIntOrDefault("IDEMPOTENCY_PENDING_SECONDS", 600); err != nil {
+		return err
+	}
+
+	switch {
+	case cfg.DeadlineSeconds < 1:
+		return errors.New("IDEMPOTENCY_DEADLINE_SECONDS must be at least 1")
+	case cfg.PendingSeconds < 2*cfg.DeadlineSeconds:
+		return errors.New("IDEMPOTENCY_PENDING_SECONDS must be at least twice IDEMPOTENCY_DEADLINE_SECONDS")
+	default:
+		return nil
+	}
+}
+
+func parseAuth(cfg *Auth) error {
+	cfg.JWKSFile = os.Getenv("AUTH_JWKS_FILE")
+	cfg.JWKSURL = os.Getenv("AUTH_JWKS_URL")
+	cfg.Issuer = os.Getenv("AUTH_ISSUER")
+	cfg.Audience = os.Getenv("AUTH_AUDIENCE")
+	cfg.RolesClaim = getStringOrDefault("AUTH_ROLES_CLAIM", "roles")
+	cfg.PolicyFile = os.Getenv("AUTH_POLICY_FILE")
+
+	switch {
+	case !cfg.Enabled():
+		return nil
+	case cfg.JWKSFile != "" && cfg.JWKSURL != "":
+		return errors.New("only one of AUTH_JWKS_FILE and AUTH_JWKS_URL can be set")
+	case cfg.Issuer == "" || cfg.Audience == "":
+		return errors.New("AUTH_ISSUER and AUTH_AUDIENCE are required with a key set")
+	default:
+		return nil
+	}
+}
+
+// Enabled reports whether requests must carry a bearer token.
+func (a Auth) Enabled() bool {
+	return a.JWKSFile != "" || a.JWKSURL != ""
+}
+
+func parseTLS(cfg *TLS) error {
+	cfg.CertFile = os.Getenv("TLS_CERT_FILE")
+	cfg.KeyFile = os.Getenv("TLS_KEY_FILE")
+	cfg.ClientCAFile = os.Getenv("TLS_CLIENT_CA_FILE")
+
+	switch version := getStringOrDefault("TLS_MIN_VERSION", "1.2"); version {
+	case "1.2":
+		cfg.MinVersion = tls.VersionTLS12
+	case "1.3":
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return fmt.Errorf("unsupported TLS_MIN_VERSION %q, use 1.2 or 1.3", version)
+	}
+
+	switch {
+	case (cfg.CertFile == "") != (cfg.KeyFile == ""):
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	case cfg.ClientCAFile != "" && !cfg.Enabled():
+		return errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	default:
+		return nil
+	}
+}
+
+// Enabled reports whether the listeners serve TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+func parseRateLimit(cfg *RateLimit) error {
+	var err error
+
+	if cfg.Default, err = parseLimit(os.Getenv("RATE_LIMIT_DEFAULT")); err != nil {
+		return fmt.Errorf("can not parse RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	cfg.Methods = make(map[string]Limit)
+
+	for _, pair := range strings.Split(os.Getenv("RATE_LIMIT_METHODS"), ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		method, limit, found := strings.Cut(pair, "=")
+
+		if cfg.Methods[method], err = parseLimit(limit); err != nil || !found || method == "" {
+			return fmt.Errorf("can not parse RATE_LIMIT_METHODS entry %q: want Method=rate:burst", pair)
+		}
+	}
+
+	if cfg.AuthorBooksStreams, err = getIntOrDefault("RATE_LIMIT_AUTHOR_BOOKS_STREAMS", 0); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseLimit parses rate:burst, an empty value does not limit.
+func parseLimit(value string) (Limit, error) {
+	if value == "" {
+		return Limit{}, nil
+	}
+
+	rate, burst, found := strings.Cut(value, ":")
+
+	if !found {
+		return Limit{}, fmt.Errorf("%q is not rate:burst", value)
+	}
+
+	var (
+		limit Limit
+		err   error
+	)
+
+	if limit.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+		return Limit{}, err
+	}
+
+	if limit.Burst, err = strconv.Atoi(burst); err != nil {
+		return Limit{}, err
+	}
+
+	if math.IsNaN(limit.Rate) || limit.Rate < 0 || (limit.Rate > 0 && limit.Burst < 1) {
+		return Limit{}, fmt.Errorf("%q needs a positive rate and a burst of at least 1", value)
+	}
+
+	return limit, nil
+}
+
+func parseOutbox(cfg *Outbox) error {
+	var err error
+
+	if cfg.Enabled, err = getBool("OUTBOX_ENABLED", false); err != nil || !cfg.Enabled {
+		return err
+	}
+
+	if cfg.Workers, err = getInt("OUTBOX_WORKERS"); err != nil {
+		return err
+	}
+
+	if cfg.BatchSize, err = getInt("OUTBOX_BATCH_SIZE"); err != nil {
+		return err
+	}
+
+	if cfg.WaitTimeMS, err = getDuration("OUTBOX_WAIT_TIME_MS"); err != nil {
+		return err
+	}
+
+	if cfg.InProgressTTLMS, err = getDuration("OUTBOX_IN_PROGRESS_TTL_MS"); err != nil {
+		return err
+	}
+
+	cfg.AuthorSendURL = os.Getenv("OUTBOX_AUTHOR_SEND_URL")
+	cfg.BookSendURL = os.Getenv("OUTBOX_BOOK_SEND_URL")
+	cfg.PatronSendURL = os.Getenv("OUTBOX_PATRON_SEND_URL")
+	cfg.LoanSendURL = os.Getenv("OUTBOX_LOAN_SEND_URL")
+	cfg.HoldSendURL = os.Getenv("OUTBOX_HOLD_SEND_URL")
+	cfg.MergeSendURL = os.Getenv("OUTBOX_AUTHOR_MERGE_SEND_URL")
+
+	return nil
+}
+
+func getStringOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+
+	if value == "" {
+		return fallback, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+
+	if err != nil {
+		return false, fmt.Errorf("can not parse %s: %w", key, err)
+	}
+
+	return result, nil
+}
+
+func getInt(key string) (int, error) {
+	result, err := strconv.Atoi(os.Getenv(key))
+
+	if err != nil {
+		return 0, fmt.Errorf("can not parse %s: %w", key, err)
+	}
+
+	return result, nil
+}
+
+func getIntOrDefault(key string, fallback int) (int, error) {
+	if os.Getenv(key) == "" {
+		return fallback, nil
+	}
+
+	return getInt(key)
+}
+
+// getDuration parses a duration given as an integer number of nanoseconds,
+// the way time.Duration is printed by fmt.
+func getDuration(key string) (time.Duration, error) {
+	result, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("can not parse %s: %w", key, err)
+	}
+
+	return time.Duration(result), nil
+}
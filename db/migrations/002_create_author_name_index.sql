@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX author_name_idx ON author (name);
+
+-- +goose Down
+DROP INDEX author_name_idx;
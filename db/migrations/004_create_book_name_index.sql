@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX book_name_idx ON book (name);
+
+-- +goose Down
+DROP INDEX book_name_idx;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX author_book_book_id_idx ON author_book (book_id);
+
+-- +goose Down
+DROP INDEX author_book_book_id_idx;
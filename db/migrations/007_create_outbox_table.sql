@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TYPE outbox_status as ENUM ('CREATED', 'IN_PROGRESS', 'SUCCESS');
+
+CREATE TABLE outbox
+(
+    idempotency_key TEXT PRIMARY KEY,
+    data            JSONB                   NOT NULL,
+    status          outbox_status           NOT NULL,
+    kind            INT                     NOT NULL,
+    created_at      TIMESTAMP DEFAULT now() NOT NULL,
+    updated_at      TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX outbox_status_updated_at_idx ON outbox (status, updated_at);
+
+-- +goose Down
+DROP TABLE outbox;
+DROP TYPE outbox_status;
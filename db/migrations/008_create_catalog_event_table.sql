@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE catalog_event
+(
+    id         BIGSERIAL PRIMARY KEY,
+    kind       INT                     NOT NULL,
+    data       JSONB                   NOT NULL,
+    author_ids UUID[]                  NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+-- Wakes up WatchCatalog streams on every replica. Notifications are
+-- delivered on commit, so listeners never see uncommitted events.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_catalog_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('catalog_event', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_notify_catalog_event
+    AFTER INSERT
+    ON catalog_event
+    FOR EACH STATEMENT
+EXECUTE FUNCTION notify_catalog_event();
+
+-- +goose Down
+DROP TABLE catalog_event;
+DROP FUNCTION notify_catalog_event;
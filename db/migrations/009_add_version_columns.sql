@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE author
+    ADD COLUMN version BIGINT DEFAULT 1 NOT NULL;
+
+ALTER TABLE book
+    ADD COLUMN version BIGINT DEFAULT 1 NOT NULL;
+
+-- +goose Down
+ALTER TABLE book
+    DROP COLUMN version;
+
+ALTER TABLE author
+    DROP COLUMN version;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE audit_log
+(
+    id          BIGSERIAL PRIMARY KEY,
+    actor       TEXT                    NOT NULL,
+    operation   TEXT                    NOT NULL,
+    entity_kind TEXT                    NOT NULL,
+    entity_id   UUID                    NOT NULL,
+    before      JSONB,
+    after       JSONB                   NOT NULL,
+    created_at  TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_kind, entity_id, id);
+
+-- The log is append-only, even for direct database access.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION forbid_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_forbid_audit_log_change
+    BEFORE UPDATE OR DELETE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION forbid_audit_log_change();
+
+-- +goose Down
+DROP TABLE audit_log;
+DROP FUNCTION forbid_audit_log_change;
@@ -0,0 +1,120 @@
+-- +goose Up
+-- System-versioned history of authors and books. Every committed version of
+-- a row is kept with the period it was current in, the current version has
+-- valid_to = 'infinity'. Rows are stored as JSONB snapshots, so columns added
+-- later only need to be handled when reading.
+CREATE TABLE author_history
+(
+    id         UUID        NOT NULL,
+    version    BIGINT      NOT NULL,
+    data       JSONB       NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to   TIMESTAMPTZ NOT NULL DEFAULT 'infinity',
+    PRIMARY KEY (id, version)
+);
+
+CREATE INDEX author_history_period_idx ON author_history (id, valid_from, valid_to);
+CREATE INDEX author_history_valid_to_idx ON author_history (valid_to);
+
+CREATE TABLE book_history
+(
+    id         UUID        NOT NULL,
+    version    BIGINT      NOT NULL,
+    data       JSONB       NOT NULL,
+    author_ids UUID[]      NOT NULL DEFAULT '{}',
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to   TIMESTAMPTZ NOT NULL DEFAULT 'infinity',
+    PRIMARY KEY (id, version)
+);
+
+CREATE INDEX book_history_period_idx ON book_history (id, valid_from, valid_to);
+CREATE INDEX book_history_valid_to_idx ON book_history (valid_to);
+CREATE INDEX book_history_author_ids_idx ON book_history USING GIN (author_ids);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_author_history() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE author_history
+    SET valid_to = now()
+    WHERE id = NEW.id
+      AND valid_to = 'infinity'
+      AND version <> NEW.version;
+
+    INSERT INTO author_history (id, version, data, valid_from)
+    VALUES (NEW.id, NEW.version, to_jsonb(NEW), now())
+    ON CONFLICT (id, version) DO UPDATE SET data = excluded.data;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_record_author_history
+    AFTER INSERT OR UPDATE
+    ON author
+    FOR EACH ROW
+EXECUTE FUNCTION record_author_history();
+
+-- Authorship lives in author_book and changes after the book row itself, so
+-- the book snapshot is taken at commit, once per version.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_book_history() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS (SELECT 1
+               FROM book_history h
+                        JOIN book b ON b.id = h.id AND b.version = h.version
+               WHERE h.id = NEW.id) THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE book_history
+    SET valid_to = now()
+    WHERE id = NEW.id
+      AND valid_to = 'infinity';
+
+    INSERT INTO book_history (id, version, data, author_ids, valid_from)
+    SELECT b.id,
+           b.version,
+           to_jsonb(b),
+           array_remove(array_agg(ab.author_id ORDER BY ab.author_id), NULL),
+           now()
+    FROM book b
+             LEFT JOIN author_book ab ON ab.book_id = b.id
+    WHERE b.id = NEW.id
+    GROUP BY b.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE CONSTRAINT TRIGGER trigger_record_book_history
+    AFTER INSERT OR UPDATE
+    ON book
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+EXECUTE FUNCTION record_book_history();
+
+INSERT INTO author_history (id, version, data, valid_from)
+SELECT a.id, a.version, to_jsonb(a), a.updated_at
+FROM author a;
+
+INSERT INTO book_history (id, version, data, author_ids, valid_from)
+SELECT b.id,
+       b.version,
+       to_jsonb(b),
+       array_remove(array_agg(ab.author_id ORDER BY ab.author_id), NULL),
+       b.updated_at
+FROM book b
+         LEFT JOIN author_book ab ON ab.book_id = b.id
+GROUP BY b.id;
+
+-- +goose Down
+DROP TRIGGER trigger_record_book_history ON book;
+DROP TRIGGER trigger_record_author_history ON author;
+DROP FUNCTION record_book_history;
+DROP FUNCTION record_author_history;
+DROP TABLE book_history;
+DROP TABLE author_history;
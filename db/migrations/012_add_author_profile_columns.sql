@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE author
+    ADD COLUMN biography  TEXT   DEFAULT ''   NOT NULL,
+    ADD COLUMN birth_date DATE,
+    ADD COLUMN death_date DATE,
+    ADD COLUMN country    TEXT   DEFAULT ''   NOT NULL,
+    ADD COLUMN aliases    TEXT[] DEFAULT '{}' NOT NULL,
+    ADD COLUMN viaf       TEXT,
+    ADD COLUMN isni       TEXT,
+    ADD COLUMN wikidata   TEXT,
+    ADD CONSTRAINT author_life_dates_check CHECK (death_date >= birth_date);
+
+CREATE UNIQUE INDEX author_viaf_idx ON author (viaf) WHERE viaf IS NOT NULL;
+CREATE UNIQUE INDEX author_isni_idx ON author (isni) WHERE isni IS NOT NULL;
+CREATE UNIQUE INDEX author_wikidata_idx ON author (wikidata) WHERE wikidata IS NOT NULL;
+
+-- +goose Down
+DROP INDEX author_wikidata_idx;
+DROP INDEX author_isni_idx;
+DROP INDEX author_viaf_idx;
+
+ALTER TABLE author
+    DROP CONSTRAINT author_life_dates_check,
+    DROP COLUMN wikidata,
+    DROP COLUMN isni,
+    DROP COLUMN viaf,
+    DROP COLUMN aliases,
+    DROP COLUMN country,
+    DROP COLUMN death_date,
+    DROP COLUMN birth_date,
+    DROP COLUMN biography;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE book
+    ADD COLUMN isbn             TEXT,
+    ADD COLUMN publisher        TEXT DEFAULT '' NOT NULL,
+    ADD COLUMN publication_year INT  DEFAULT 0  NOT NULL,
+    ADD COLUMN language         TEXT DEFAULT '' NOT NULL,
+    ADD COLUMN page_count       INT  DEFAULT 0  NOT NULL,
+    ADD COLUMN description      TEXT DEFAULT '' NOT NULL;
+
+CREATE UNIQUE INDEX book_isbn_idx ON book (isbn) WHERE isbn IS NOT NULL;
+
+-- +goose Down
+DROP INDEX book_isbn_idx;
+
+ALTER TABLE book
+    DROP COLUMN description,
+    DROP COLUMN page_count,
+    DROP COLUMN language,
+    DROP COLUMN publication_year,
+    DROP COLUMN publisher,
+    DROP COLUMN isbn;
@@ -0,0 +1,118 @@
+-- +goose Up
+CREATE TYPE contributor_role AS ENUM ('author', 'translator', 'editor', 'illustrator');
+
+-- An author may be credited on a book in several roles, position is the
+-- credited order of the book starting at 0. Existing links become authors
+-- credited in the order of their ids.
+ALTER TABLE author_book
+    ADD COLUMN role     contributor_role NOT NULL DEFAULT 'author',
+    ADD COLUMN position INT              NOT NULL DEFAULT 0;
+
+UPDATE author_book ab
+SET position = o.position
+FROM (SELECT author_id, book_id, row_number() OVER (PARTITION BY book_id ORDER BY author_id) - 1 AS position
+      FROM author_book) o
+WHERE ab.author_id = o.author_id
+  AND ab.book_id = o.book_id;
+
+ALTER TABLE author_book
+    DROP CONSTRAINT author_book_pkey,
+    ADD PRIMARY KEY (author_id, book_id, role);
+
+ALTER TABLE book_history
+    ADD COLUMN contributors JSONB NOT NULL DEFAULT '[]';
+
+UPDATE book_history h
+SET contributors = (SELECT coalesce(jsonb_agg(jsonb_build_object('author_id', a.author_id,
+                                                                 'role', 'author',
+                                                                 'position', a.ordinality - 1)
+                                              ORDER BY a.ordinality), '[]')
+                    FROM unnest(h.author_ids) WITH ORDINALITY a(author_id, ordinality));
+
+CREATE INDEX book_history_contributors_idx ON book_history USING GIN (contributors jsonb_path_ops);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_book_history() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS (SELECT 1
+               FROM book_history h
+                        JOIN book b ON b.id = h.id AND b.version = h.version
+               WHERE h.id = NEW.id) THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE book_history
+    SET valid_to = now()
+    WHERE id = NEW.id
+      AND valid_to = 'infinity';
+
+    INSERT INTO book_history (id, version, data, author_ids, contributors, valid_from)
+    SELECT b.id,
+           b.version,
+           to_jsonb(b),
+           array_remove(array_agg(DISTINCT ab.author_id ORDER BY ab.author_id), NULL),
+           coalesce(jsonb_agg(jsonb_build_object('author_id', ab.author_id,
+                                                 'role', ab.role,
+                                                 'position', ab.position)
+                              ORDER BY ab.position) FILTER (WHERE ab.author_id IS NOT NULL), '[]'),
+           now()
+    FROM book b
+             LEFT JOIN author_book ab ON ab.book_id = b.id
+    WHERE b.id = NEW.id
+    GROUP BY b.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION record_book_history() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS (SELECT 1
+               FROM book_history h
+                        JOIN book b ON b.id = h.id AND b.version = h.version
+               WHERE h.id = NEW.id) THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE book_history
+    SET valid_to = now()
+    WHERE id = NEW.id
+      AND valid_to = 'infinity';
+
+    INSERT INTO book_history (id, version, data, author_ids, valid_from)
+    SELECT b.id,
+           b.version,
+           to_jsonb(b),
+           array_remove(array_agg(ab.author_id ORDER BY ab.author_id), NULL),
+           now()
+    FROM book b
+             LEFT JOIN author_book ab ON ab.book_id = b.id
+    WHERE b.id = NEW.id
+    GROUP BY b.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP INDEX book_history_contributors_idx;
+
+ALTER TABLE book_history
+    DROP COLUMN contributors;
+
+DELETE
+FROM author_book
+WHERE role <> 'author';
+
+ALTER TABLE author_book
+    DROP CONSTRAINT author_book_pkey,
+    ADD PRIMARY KEY (author_id, book_id),
+    DROP COLUMN position,
+    DROP COLUMN role;
+
+DROP TYPE contributor_role;
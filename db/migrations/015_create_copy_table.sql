@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TYPE copy_status AS ENUM ('available', 'on_loan', 'lost', 'withdrawn');
+CREATE TYPE copy_condition AS ENUM ('new', 'good', 'fair', 'poor', 'damaged');
+
+CREATE TABLE copy
+(
+    id          UUID PRIMARY KEY        DEFAULT uuid_generate_v4(),
+    book_id     UUID           NOT NULL REFERENCES book (id),
+    barcode     TEXT           NOT NULL UNIQUE,
+    branch      TEXT           NOT NULL DEFAULT '',
+    location    TEXT           NOT NULL DEFAULT '',
+    condition   copy_condition NOT NULL DEFAULT 'good',
+    acquired_on DATE,
+    status      copy_status    NOT NULL DEFAULT 'available',
+    created_at  TIMESTAMP      NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMP      NOT NULL DEFAULT now()
+);
+
+CREATE INDEX copy_book_id_status_idx ON copy (book_id, status);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_copy_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_copy_timestamp
+    BEFORE UPDATE
+    ON copy
+    FOR EACH ROW
+EXECUTE FUNCTION update_copy_timestamp();
+
+-- +goose Down
+DROP TABLE copy;
+DROP FUNCTION update_copy_timestamp;
+DROP TYPE copy_condition;
+DROP TYPE copy_status;
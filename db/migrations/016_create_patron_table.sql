@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TYPE membership_type AS ENUM ('standard', 'student', 'senior', 'staff');
+CREATE TYPE patron_status AS ENUM ('active', 'suspended');
+
+CREATE TABLE patron
+(
+    id                UUID PRIMARY KEY         DEFAULT uuid_generate_v4(),
+    card_number       TEXT            NOT NULL UNIQUE,
+    name              TEXT            NOT NULL,
+    email             TEXT            NOT NULL DEFAULT '',
+    phone             TEXT            NOT NULL DEFAULT '',
+    address           TEXT            NOT NULL DEFAULT '',
+    membership_type   membership_type NOT NULL DEFAULT 'standard',
+    expires_on        DATE,
+    status            patron_status   NOT NULL DEFAULT 'active',
+    suspension_reason TEXT            NOT NULL DEFAULT '',
+    created_at        TIMESTAMP       NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMP       NOT NULL DEFAULT now()
+);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_patron_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_patron_timestamp
+    BEFORE UPDATE
+    ON patron
+    FOR EACH ROW
+EXECUTE FUNCTION update_patron_timestamp();
+
+-- +goose Down
+DROP TABLE patron;
+DROP FUNCTION update_patron_timestamp;
+DROP TYPE patron_status;
+DROP TYPE membership_type;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE loan
+(
+    id             UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    copy_id        UUID      NOT NULL REFERENCES copy (id),
+    patron_id      UUID      NOT NULL REFERENCES patron (id),
+    checked_out_at TIMESTAMP NOT NULL,
+    due_at         TIMESTAMP NOT NULL,
+    renewals       INT       NOT NULL DEFAULT 0 CHECK (renewals >= 0),
+    returned_at    TIMESTAMP,
+    CHECK (due_at > checked_out_at)
+);
+
+-- A copy is on at most one active loan.
+CREATE UNIQUE INDEX loan_active_copy_id_idx ON loan (copy_id) WHERE returned_at IS NULL;
+CREATE INDEX loan_active_patron_id_idx ON loan (patron_id) WHERE returned_at IS NULL;
+
+-- +goose Down
+DROP TABLE loan;
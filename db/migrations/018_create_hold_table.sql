@@ -0,0 +1,33 @@
+-- +goose Up
+-- Copies put aside for a hold wait for pickup on the hold shelf.
+ALTER TYPE copy_status ADD VALUE 'on_hold' AFTER 'on_loan';
+
+CREATE TYPE hold_status AS ENUM ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired');
+
+CREATE TABLE hold
+(
+    id        UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    book_id   UUID        NOT NULL REFERENCES book (id),
+    patron_id UUID        NOT NULL REFERENCES patron (id),
+    status    hold_status NOT NULL DEFAULT 'waiting',
+    copy_id   UUID REFERENCES copy (id),
+    placed_at TIMESTAMP   NOT NULL,
+    pickup_by TIMESTAMP,
+    closed_at TIMESTAMP,
+    CHECK (status <> 'ready' OR (copy_id IS NOT NULL AND pickup_by IS NOT NULL))
+);
+
+-- A patron holds a book at most once at a time.
+CREATE UNIQUE INDEX hold_active_book_id_patron_id_idx ON hold (book_id, patron_id) WHERE status IN ('waiting', 'ready');
+-- The queue of a book.
+CREATE INDEX hold_waiting_book_id_idx ON hold (book_id, placed_at, id) WHERE status = 'waiting';
+-- A copy is put aside for at most one hold.
+CREATE UNIQUE INDEX hold_ready_copy_id_idx ON hold (copy_id) WHERE status = 'ready';
+CREATE INDEX hold_ready_pickup_by_idx ON hold (pickup_by) WHERE status = 'ready';
+CREATE INDEX hold_patron_id_idx ON hold (patron_id);
+
+-- +goose Down
+DROP TABLE hold;
+DROP TYPE hold_status;
+-- Values can not be dropped from an enum type, on_hold stays unused.
+UPDATE copy SET status = 'available' WHERE status = 'on_hold';
@@ -0,0 +1,68 @@
+-- +goose Up
+CREATE TYPE ledger_account AS ENUM ('patron', 'fine_income', 'cash', 'waived');
+CREATE TYPE ledger_entry_kind AS ENUM ('accrual', 'payment', 'waiver');
+
+-- Double-entry: every transaction is a debit entry with a positive amount and
+-- a credit entry with the negative one. The balance of a patron is the sum of
+-- their entries in the patron account, in cents.
+CREATE TABLE fines_ledger
+(
+    id             BIGSERIAL PRIMARY KEY,
+    transaction_id UUID              NOT NULL,
+    kind           ledger_entry_kind NOT NULL,
+    patron_id      UUID              NOT NULL REFERENCES patron (id),
+    loan_id        UUID REFERENCES loan (id),
+    account        ledger_account    NOT NULL,
+    amount         BIGINT            NOT NULL CHECK (amount <> 0),
+    note           TEXT              NOT NULL DEFAULT '',
+    created_at     TIMESTAMP         NOT NULL,
+    CHECK (kind <> 'accrual' OR loan_id IS NOT NULL)
+);
+
+CREATE INDEX fines_ledger_transaction_id_idx ON fines_ledger (transaction_id);
+CREATE INDEX fines_ledger_patron_id_idx ON fines_ledger (patron_id) WHERE account = 'patron';
+CREATE INDEX fines_ledger_loan_id_idx ON fines_ledger (loan_id) WHERE kind = 'accrual' AND account = 'patron';
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION check_fines_ledger_balance() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (SELECT sum(amount) FROM fines_ledger WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'fines_ledger transaction % does not balance', NEW.transaction_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- Checked at commit, when all entries of the transaction are in.
+CREATE CONSTRAINT TRIGGER trigger_check_fines_ledger_balance
+    AFTER INSERT
+    ON fines_ledger
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+EXECUTE FUNCTION check_fines_ledger_balance();
+
+-- Mistakes are corrected by new transactions, like in the audit log.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION forbid_fines_ledger_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'fines_ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_forbid_fines_ledger_change
+    BEFORE UPDATE OR DELETE
+    ON fines_ledger
+    FOR EACH ROW
+EXECUTE FUNCTION forbid_fines_ledger_change();
+
+-- +goose Down
+DROP TABLE fines_ledger;
+DROP FUNCTION check_fines_ledger_balance;
+DROP FUNCTION forbid_fines_ledger_change;
+DROP TYPE ledger_entry_kind;
+DROP TYPE ledger_account;
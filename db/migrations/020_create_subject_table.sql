@@ -0,0 +1,50 @@
+-- +goose Up
+CREATE TABLE subject
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    name       TEXT      NOT NULL CHECK (name <> ''),
+    parent_id  UUID REFERENCES subject (id),
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    CHECK (parent_id <> id)
+);
+
+-- Siblings have distinct names, top-level subjects are siblings too.
+CREATE UNIQUE INDEX subject_parent_id_name_idx ON subject (parent_id, lower(name)) NULLS NOT DISTINCT;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_subject_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_subject_timestamp
+    BEFORE UPDATE
+    ON subject
+    FOR EACH ROW
+EXECUTE FUNCTION update_subject_timestamp();
+
+-- Subjects and tags live on the book row, so they are versioned and kept in
+-- the history with the rest of the book. Arrays can not reference subjects,
+-- the repository locks the subjects of a book while writing it instead.
+ALTER TABLE book
+    ADD COLUMN subject_ids UUID[] NOT NULL DEFAULT '{}',
+    ADD COLUMN tags        TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX book_subject_ids_idx ON book USING GIN (subject_ids);
+CREATE INDEX book_tags_idx ON book USING GIN (tags);
+
+-- +goose Down
+DROP INDEX book_tags_idx;
+DROP INDEX book_subject_ids_idx;
+
+ALTER TABLE book
+    DROP COLUMN tags,
+    DROP COLUMN subject_ids;
+
+DROP TABLE subject;
+DROP FUNCTION update_subject_timestamp;
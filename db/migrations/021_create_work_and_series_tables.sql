@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE work
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    title      TEXT      NOT NULL CHECK (title <> ''),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE series
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    name       TEXT      NOT NULL CHECK (name <> ''),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Unnumbered books of a series have position 0.
+ALTER TABLE book
+    ADD COLUMN work_id         UUID REFERENCES work (id),
+    ADD COLUMN series_id       UUID REFERENCES series (id),
+    ADD COLUMN series_position INT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT book_series_position_check
+        CHECK (series_position >= 0 AND (series_id IS NOT NULL OR series_position = 0));
+
+CREATE INDEX book_work_id_idx ON book (work_id) WHERE work_id IS NOT NULL;
+CREATE INDEX book_series_id_idx ON book (series_id, series_position) WHERE series_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX book_series_id_idx;
+DROP INDEX book_work_id_idx;
+
+ALTER TABLE book
+    DROP CONSTRAINT book_series_position_check,
+    DROP COLUMN series_position,
+    DROP COLUMN series_id,
+    DROP COLUMN work_id;
+
+DROP TABLE series;
+DROP TABLE work;
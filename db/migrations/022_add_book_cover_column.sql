@@ -0,0 +1,9 @@
+-- +goose Up
+-- URLs of the cover image and its thumbnails, see entity.Cover. The images
+-- themselves are kept in the blob store.
+ALTER TABLE book
+    ADD COLUMN cover JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE book
+    DROP COLUMN cover;
@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE review
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    book_id    UUID      NOT NULL REFERENCES book (id),
+    patron_id  UUID      NOT NULL REFERENCES patron (id),
+    rating     INT       NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text       TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (book_id, patron_id)
+);
+
+CREATE INDEX review_book_id_idx ON review (book_id, created_at DESC, id DESC);
+CREATE INDEX review_patron_id_idx ON review (patron_id, created_at DESC, id DESC);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_review_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_review_timestamp
+    BEFORE UPDATE
+    ON review
+    FOR EACH ROW
+EXECUTE FUNCTION update_review_timestamp();
+
+-- The ratings of the reviews of a book, maintained by every review write so
+-- reading the average does not scan the reviews.
+CREATE TABLE book_rating
+(
+    book_id      UUID PRIMARY KEY REFERENCES book (id),
+    rating_count INT    NOT NULL CHECK (rating_count >= 0),
+    rating_sum   BIGINT NOT NULL CHECK (rating_sum >= 0)
+);
+
+-- +goose Down
+DROP TABLE book_rating;
+DROP TABLE review;
+DROP FUNCTION update_review_timestamp;
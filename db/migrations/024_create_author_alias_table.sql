@@ -0,0 +1,43 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Finds authors with similar names for the duplicate report.
+CREATE INDEX author_name_trgm_idx ON author USING GIN (name gin_trgm_ops);
+
+-- Ids of the authors merged into another one. They keep resolving to the
+-- surviving author, which is never a merged one itself.
+CREATE TABLE author_alias
+(
+    alias_id  UUID PRIMARY KEY,
+    author_id UUID      NOT NULL REFERENCES author (id),
+    merged_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX author_alias_author_id_idx ON author_alias (author_id);
+
+-- A deleted author stops being current in its history.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION close_author_history() RETURNS TRIGGER AS
+$$
+BEGIN
+    UPDATE author_history
+    SET valid_to = now()
+    WHERE id = OLD.id
+      AND valid_to = 'infinity';
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_close_author_history
+    AFTER DELETE
+    ON author
+    FOR EACH ROW
+EXECUTE FUNCTION close_author_history();
+
+-- +goose Down
+DROP TRIGGER trigger_close_author_history ON author;
+DROP FUNCTION close_author_history;
+DROP TABLE author_alias;
+DROP INDEX author_name_trgm_idx;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Responses to requests made with an idempotency key, replayed to retries
+-- until expires_at. The token identifies the reservation of a key, so a
+-- request whose key was taken over by a retry neither completes nor releases
+-- it.
+CREATE TABLE idempotency_key
+(
+    key         TEXT PRIMARY KEY,
+    fingerprint BYTEA     NOT NULL,
+    token       TEXT      NOT NULL,
+    response    BYTEA,
+    completed   BOOLEAN   NOT NULL DEFAULT false,
+    created_at  TIMESTAMP NOT NULL,
+    expires_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idempotency_key_expires_at_idx ON idempotency_key (expires_at);
+
+-- +goose Down
+DROP TABLE idempotency_key;
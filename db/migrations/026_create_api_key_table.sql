@@ -0,0 +1,20 @@
+-- +goose Up
+-- Keys of the services calling the API without a bearer token. Only a
+-- salted hash of the secret is stored.
+CREATE TABLE api_key
+(
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name         TEXT      NOT NULL,
+    scopes       TEXT[]    NOT NULL,
+    salt         BYTEA     NOT NULL,
+    hash         BYTEA     NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP
+);
+
+CREATE INDEX api_key_created_at_idx ON api_key (created_at, id);
+
+-- +goose Down
+DROP TABLE api_key;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- The title duplicate books are found by, entity.NormalizeTitle of the
+-- name. Titles of existing books are normalized by the Go migration 028.
+ALTER TABLE book
+    ADD COLUMN normalized_name TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX book_normalized_name_idx ON book (normalized_name);
+
+-- +goose Down
+DROP INDEX book_normalized_name_idx;
+
+ALTER TABLE book
+    DROP COLUMN normalized_name;
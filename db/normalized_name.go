@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+	"github.com/project/library/internal/entity"
+)
+
+// backfillBatchSize is how many books are read at a time by the backfill.
+const backfillBatchSize = 1000
+
+// The normalized names of the books added before migration 027 are computed
+// in Go: only entity.NormalizeTitle itself yields the keys AddBook looks
+// duplicates up by and ListBooks filters titles with, SQL approximations of
+// it miss titles with punctuation or compatibility characters.
+func init() {
+	goose.AddNamedMigrationContext("028_backfill_book_normalized_name.go", upBackfillNormalizedName, nil)
+}
+
+type bookTitle struct {
+	id   string
+	name string
+}
+
+// bookTitles are the books the backfill reads and updates.
+type bookTitles interface {
+	// titles returns the batch of at most limit books following the id, in
+	// the order of their ids.
+	titles(ctx context.Context, after string, limit int) ([]bookTitle, error)
+	setNormalizedName(ctx context.Context, id string, normalizedName string) error
+}
+
+func upBackfillNormalizedName(ctx context.Context, tx *sql.Tx) error {
+	// The backfill is no edit of the books, their timestamps and history stay.
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE book DISABLE TRIGGER USER`); err != nil {
+		return err
+	}
+
+	if err := backfillNormalizedNames(ctx, txBookTitles{tx: tx}); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `ALTER TABLE book ENABLE TRIGGER USER`)
+
+	return err
+}
+
+func backfillNormalizedNames(ctx context.Context, books bookTitles) error {
+	after := "00000000-0000-0000-0000-000000000000"
+
+	for {
+		batch, err := books.titles(ctx, after, backfillBatchSize)
+
+		if err != nil {
+			return err
+		}
+
+		for _, book := range batch {
+			if err = books.setNormalizedName(ctx, book.id, entity.NormalizeTitle(book.name)); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < backfillBatchSize {
+			return nil
+		}
+
+		after = batch[len(batch)-1].id
+	}
+}
+
+type txBookTitles struct {
+	tx *sql.Tx
+}
+
+func (t txBookTitles) titles(ctx context.Context, after string, limit int) ([]bookTitle, error) {
+	const query = `SELECT id, name FROM book WHERE id > $1 ORDER BY id LIMIT $2`
+
+	rows, err := t.tx.QueryContext(ctx, query, after, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var books []bookTitle
+
+	for rows.Next() {
+		var book bookTitle
+
+		if err = rows.Scan(&book.id, &book.name); err != nil {
+			return nil, err
+		}
+
+		books = append(books, book)
+	}
+
+	return books, rows.Err()
+}
+
+func (t txBookTitles) setNormalizedName(ctx context.Context, id string, normalizedName string) error {
+	const query = `UPDATE book SET normalized_name = $2 WHERE id = $1`
+
+	_, err := t.tx.ExecContext(ctx, query, id, normalizedName)
+
+	return err
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBookTitles are books added before the normalized names, ordered by id.
+type fakeBookTitles struct {
+	books      []bookTitle
+	normalized map[string]string
+	batches    int
+}
+
+func (f *fakeBookTitles) titles(_ context.Context, after string, limit int) ([]bookTitle, error) {
+	f.batches++
+
+	start, _ := slices.BinarySearchFunc(f.books, after, func(book bookTitle, id string) int {
+		if book.id <= id {
+			return -1
+		}
+
+		return 1
+	})
+
+	return f.books[start:min(start+limit, len(f.books))], nil
+}
+
+func (f *fakeBookTitles) setNormalizedName(_ context.Context, id string, normalizedName string) error {
+	f.normalized[id] = normalizedName
+	return nil
+}
+
+func TestBackfillNormalizedNames(t *testing.T) {
+	t.Parallel()
+
+	books := &fakeBookTitles{normalized: make(map[string]string)}
+
+	for i := range backfillBatchSize {
+		books.books = append(books.books, bookTitle{
+			id:   fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1),
+			name: fmt.Sprintf("Book %d", i),
+		})
+	}
+
+	books.books = append(books.books,
+		bookTitle{id: "10000000-0000-0000-0000-000000000000", name: "The Lord of the Rings: Part I"},
+		bookTitle{id: "20000000-0000-0000-0000-000000000000", name: "ＤＵＮＥ!"})
+
+	require.NoError(t, backfillNormalizedNames(context.Background(), books))
+	require.Len(t, books.normalized, backfillBatchSize+2)
+	require.Equal(t, 2, books.batches)
+
+	// The titles added since are looked up by the same keys, punctuation
+	// and compatibility characters notwithstanding.
+	require.Equal(t, entity.NormalizeTitle("the lord of the rings — part Ⅰ"),
+		books.normalized["10000000-0000-0000-0000-000000000000"])
+	require.Equal(t, entity.NormalizeTitle("Dune"), books.normalized["20000000-0000-0000-0000-000000000000"])
+}
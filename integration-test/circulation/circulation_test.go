@@ -0,0 +1,301 @@
+//go:build database_hw
+
+// Package circulation lends copies against Postgres, where the partial unique
+// index on the active loans and the locking of concurrent checkouts are
+// exercised.
+package circulation
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/project/library/db"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// borrowers check out the same copy at once.
+const borrowers = 8
+
+var pool *pgxpool.Pool
+
+var loanPolicy = entity.LoanPolicy{
+	Periods: map[entity.MembershipType]time.Duration{
+		entity.MembershipTypeStandard: 21 * 24 * time.Hour,
+	},
+	MaxActiveLoans: 2,
+	MaxRenewals:    1,
+	PickupWindow:   7 * 24 * time.Hour,
+	Fines: entity.FinePolicy{
+		DailyRate:  25,
+		Cap:        100,
+		MaxBalance: 50,
+	},
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run migrates a database of its own: the suites of the other packages
+// truncate the catalog while they run in parallel with this one.
+func run(m *testing.M) int {
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, source(os.Getenv("POSTGRES_DB")))
+
+	if err != nil {
+		log.Fatalf("Could not connect to database: %v", err)
+	}
+
+	defer admin.Close()
+
+	name := "library_circulation_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	if _, err = admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		log.Fatalf("Could not create database: %v", err)
+	}
+
+	defer func() {
+		if _, err := admin.Exec(ctx, "DROP DATABASE "+name+" WITH (FORCE)"); err != nil {
+			log.Errorf("Could not drop database: %v", err)
+		}
+	}()
+
+	pool, err = pgxpool.New(ctx, source(name))
+
+	if err != nil {
+		log.Fatalf("Could not connect to database: %v", err)
+	}
+
+	defer pool.Close()
+
+	db.SetupPostgres(pool, zap.NewNop())
+
+	return m.Run()
+}
+
+func source(database string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&pool_max_conns=%d",
+		url.QueryEscape(os.Getenv("POSTGRES_USER")),
+		url.QueryEscape(os.Getenv("POSTGRES_PASSWORD")),
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		database,
+		2*borrowers,
+	)
+}
+
+// newClient serves the controller backed by the Postgres repositories and
+// returns a client connected to it.
+func newClient(t *testing.T) generated.LibraryClient {
+	t.Helper()
+
+	repo := repository.NewPostgresRepository(pool)
+	useCases := library.New(zap.NewNop(), library.Deps{
+		AuthorRepository:   repo,
+		BooksRepository:    repo,
+		SubjectsRepository: repo,
+		WorksRepository:    repo,
+		CopiesRepository:   repo,
+		PatronsRepository:  repo,
+		LoansRepository:    repo,
+		HoldsRepository:    repo,
+		ReviewsRepository:  repo,
+		FinesRepository:    repo,
+		CatalogRepository:  repo,
+		AuditRepository:    repo,
+		OutboxRepository:   repository.NewOutbox(pool),
+		BlobStore:          repository.NewFileBlobStore(t.TempDir(), ""),
+		Transactor:         repository.NewTransactor(pool),
+		LoanPolicy:         loanPolicy,
+	})
+
+	server := grpc.NewServer()
+	generated.RegisterLibraryServer(server, controller.New(zap.NewNop(), controller.Deps{
+		BooksUseCase:    useCases,
+		SubjectsUseCase: useCases,
+		WorksUseCase:    useCases,
+		AuthorUseCase:   useCases,
+		CopiesUseCase:   useCases,
+		PatronsUseCase:  useCases,
+		LoansUseCase:    useCases,
+		HoldsUseCase:    useCases,
+		ReviewsUseCase:  useCases,
+		FinesUseCase:    useCases,
+		HistoryUseCase:  useCases,
+		CatalogUseCase:  useCases,
+	}))
+
+	listener := bufconn.Listen(1 << 20)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return generated.NewLibraryClient(conn)
+}
+
+// addCopy adds a book with a single copy.
+func addCopy(t *testing.T, client generated.LibraryClient, barcode string) *generated.Copy {
+	t.Helper()
+
+	ctx := context.Background()
+
+	book, err := client.AddBook(ctx, &generated.AddBookRequest{Name: "Dune " + barcode})
+	require.NoError(t, err)
+
+	bookCopy, err := client.AddCopy(ctx, &generated.AddCopyRequest{BookId: book.GetBook().GetId(), Barcode: barcode})
+	require.NoError(t, err)
+
+	return bookCopy.GetCopy()
+}
+
+func registerPatrons(t *testing.T, client generated.LibraryClient, prefix string, count int) []*generated.Patron {
+	t.Helper()
+
+	patrons := make([]*generated.Patron, 0, count)
+
+	for i := range count {
+		resp, err := client.RegisterPatron(context.Background(), &generated.RegisterPatronRequest{
+			CardNumber: fmt.Sprintf("%s-%04d", prefix, i),
+			Name:       "Patron " + strconv.Itoa(i),
+		})
+		require.NoError(t, err)
+
+		patrons = append(patrons, resp.GetPatron())
+	}
+
+	return patrons
+}
+
+// checkoutAtOnce has every patron check the copy out at the same time and
+// returns the codes they got, in the order of the patrons.
+func checkoutAtOnce(client generated.LibraryClient, copyID string, patrons []*generated.Patron) []codes.Code {
+	results := make([]codes.Code, len(patrons))
+	start := make(chan struct{})
+	wg := new(sync.WaitGroup)
+
+	for i, patron := range patrons {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+
+			_, err := client.CheckoutCopy(context.Background(), &generated.CheckoutCopyRequest{
+				CopyId:   copyID,
+				PatronId: patron.GetId(),
+			})
+			results[i] = status.Code(err)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return results
+}
+
+func requireActiveLoans(t *testing.T, copyID string, expected int) {
+	t.Helper()
+
+	const query = `SELECT count(*) FROM loan WHERE copy_id = $1 AND returned_at IS NULL`
+
+	var active int
+	require.NoError(t, pool.QueryRow(context.Background(), query, copyID).Scan(&active))
+	require.Equal(t, expected, active)
+}
+
+func TestConcurrentCheckout(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t)
+	bookCopy := addCopy(t, client, "concurrent")
+	patrons := registerPatrons(t, client, "concurrent", borrowers)
+
+	lent := 0
+
+	for _, code := range checkoutAtOnce(client, bookCopy.GetId(), patrons) {
+		if code == codes.OK {
+			lent++
+			continue
+		}
+
+		require.Equal(t, codes.FailedPrecondition, code)
+	}
+
+	require.Equal(t, 1, lent)
+	requireActiveLoans(t, bookCopy.GetId(), 1)
+
+	got, err := client.GetCopy(context.Background(), &generated.GetCopyRequest{Id: bookCopy.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, generated.CopyStatus_COPY_STATUS_ON_LOAN, got.GetCopy().GetStatus())
+}
+
+func TestConcurrentCheckoutOfHeldCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t)
+	bookCopy := addCopy(t, client, "held")
+	patrons := registerPatrons(t, client, "held", borrowers)
+
+	_, err := client.CheckoutCopy(ctx, &generated.CheckoutCopyRequest{CopyId: bookCopy.GetId(), PatronId: patrons[0].GetId()})
+	require.NoError(t, err)
+
+	_, err = client.PlaceHold(ctx, &generated.PlaceHoldRequest{BookId: bookCopy.GetBookId(), PatronId: patrons[1].GetId()})
+	require.NoError(t, err)
+
+	// The returned copy is put aside for the hold, only its patron gets it.
+	_, err = client.ReturnCopy(ctx, &generated.ReturnCopyRequest{CopyId: bookCopy.GetId()})
+	require.NoError(t, err)
+
+	results := checkoutAtOnce(client, bookCopy.GetId(), patrons[1:])
+	require.Equal(t, codes.OK, results[0])
+
+	for _, code := range results[1:] {
+		require.Equal(t, codes.FailedPrecondition, code)
+	}
+
+	requireActiveLoans(t, bookCopy.GetId(), 1)
+
+	holds, err := client.ListHolds(ctx, &generated.ListHoldsRequest{
+		PatronId:      patrons[1].GetId(),
+		IncludeClosed: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, holds.GetHolds(), 1)
+	require.Equal(t, generated.HoldStatus_HOLD_STATUS_FULFILLED, holds.GetHolds()[0].GetStatus())
+}
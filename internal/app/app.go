@@ -1,13 +1,602 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/project/library/config"
+	"github.com/project/library/db"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/certs"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/auth"
+	"github.com/project/library/internal/usecase/authz"
+	"github.com/project/library/internal/usecase/idempotency"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/outbox"
+	"github.com/project/library/internal/usecase/ratelimit"
+	"github.com/project/library/internal/usecase/repository"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	shutdownTimeout           = 5 * time.Second
+	catalogListenerRetryDelay = time.Second
+	historyPruneInterval      = time.Hour
+	idempotencyPruneInterval  = time.Hour
+	holdExpiryInterval        = time.Minute
+	fineAccrualInterval       = time.Hour
+	jwksTimeout               = 10 * time.Second
+	day                       = 24 * time.Hour
 )
 
 func Run(logger *zap.Logger, cfg *config.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dbPool, err := pgxpool.New(ctx, cfg.PG.URL)
+
+	if err != nil {
+		logger.Error("can not create pgxpool", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	defer dbPool.Close()
+
+	db.SetupPostgres(dbPool, logger)
+
+	repo := repository.NewPostgresRepository(dbPool)
+	outboxRepository := repository.NewOutbox(dbPool)
+	transactor := repository.NewTransactor(dbPool)
+
+	wg := new(sync.WaitGroup)
+	runOutbox(ctx, wg, cfg, logger, outboxRepository, transactor)
+	runCatalogListener(ctx, wg, logger, repo)
+	runHistoryPruner(ctx, wg, cfg, logger, repo)
+
+	useCases := library.New(logger, library.Deps{
+		AuthorRepository:   repo,
+		BooksRepository:    repo,
+		SubjectsRepository: repo,
+		WorksRepository:    repo,
+		CopiesRepository:   repo,
+		PatronsRepository:  repo,
+		LoansRepository:    repo,
+		HoldsRepository:    repo,
+		ReviewsRepository:  repo,
+		FinesRepository:    repo,
+		CatalogRepository:  repo,
+		AuditRepository:    repo,
+		OutboxRepository:   outboxRepository,
+		BlobStore:          newBlobStore(cfg.Covers),
+		Transactor:         transactor,
+		LoanPolicy:         loanPolicy(cfg.Loans),
+		MaxCoverSize:       int64(cfg.Covers.MaxSizeBytes),
+	})
+	runHoldExpirer(ctx, wg, logger, useCases)
+	runFineAccruer(ctx, wg, logger, useCases)
+
+	apiKeys := apikey.New(logger, repo)
+	ctrl := controller.New(logger, controller.Deps{
+		BooksUseCase:    useCases,
+		SubjectsUseCase: useCases,
+		WorksUseCase:    useCases,
+		AuthorUseCase:   useCases,
+		CopiesUseCase:   useCases,
+		PatronsUseCase:  useCases,
+		LoansUseCase:    useCases,
+		HoldsUseCase:    useCases,
+		ReviewsUseCase:  useCases,
+		FinesUseCase:    useCases,
+		HistoryUseCase:  useCases,
+		CatalogUseCase:  useCases,
+		APIKeysUseCase:  apiKeys,
+	})
+
+	keys := idempotency.New(logger, repo, time.Duration(cfg.Idempotency.TTLHours)*time.Hour,
+		time.Duration(cfg.Idempotency.PendingSeconds)*time.Second)
+	runIdempotencyPruner(ctx, wg, cfg, logger, keys)
+
+	reloader := newCertReloader(cfg.TLS, logger)
+	gateway := controller.NewGatewayCredentials()
+	grpcServer := runGrpc(cfg, logger, ctrl, ctrl, reloader, grpcInterceptors(cfg, logger, apiKeys, keys, gateway)...)
+	restServer := runRest(ctx, cfg, logger, reloader, gateway)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+
+	if err = restServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("can not shutdown gateway", zap.Error(err))
+	}
+
+	stopGrpc(shutdownCtx, grpcServer)
+	wg.Wait()
+}
+
+func loanPolicy(cfg config.Loans) entity.LoanPolicy {
+	return entity.LoanPolicy{
+		Periods: map[entity.MembershipType]time.Duration{
+			entity.MembershipTypeStandard: time.Duration(cfg.StandardPeriodDays) * day,
+			entity.MembershipTypeStudent:  time.Duration(cfg.StudentPeriodDays) * day,
+			entity.MembershipTypeSenior:   time.Duration(cfg.SeniorPeriodDays) * day,
+			entity.MembershipTypeStaff:    time.Duration(cfg.StaffPeriodDays) * day,
+		},
+		MaxActiveLoans: cfg.MaxActive,
+		MaxRenewals:    cfg.MaxRenewals,
+		PickupWindow:   time.Duration(cfg.HoldPickupDays) * day,
+		Fines: entity.FinePolicy{
+			DailyRate:  int64(cfg.FineDailyRate),
+			Cap:        int64(cfg.FineCap),
+			MaxBalance: int64(cfg.FineMaxBalance),
+		},
+	}
+}
+
+// rateLimiter resolves the methods of the Library service given by their
+// names alone.
+func rateLimiter(cfg config.RateLimit) ratelimit.Limiter {
+	methods := make(map[string]ratelimit.Limit, len(cfg.Methods))
+
+	for method, limit := range cfg.Methods {
+		if !strings.HasPrefix(method, "/") {
+			method = "/" + generated.Library_ServiceDesc.ServiceName + "/" + method
+		}
+
+		methods[method] = ratelimit.Limit(limit)
+	}
+
+	return ratelimit.New(ratelimit.Limit(cfg.Default), methods, map[string]int{
+		generated.Library_GetAuthorBooks_FullMethodName: cfg.AuthorBooksStreams,
+	})
+}
+
+func runOutbox(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	cfg *config.Config,
+	logger *zap.Logger,
+	outboxRepository repository.OutboxRepository,
+	transactor repository.Transactor,
+) {
+	if !cfg.Outbox.Enabled {
+		return
+	}
+
+	client := new(http.Client)
+	outboxService := outbox.New(logger, outboxRepository, globalHandler(client, cfg), transactor)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		outboxService.Start(ctx, cfg.Outbox.Workers, cfg.Outbox.BatchSize, cfg.Outbox.WaitTimeMS, cfg.Outbox.InProgressTTLMS)
+	}()
+}
+
+type catalogListener interface {
+	ListenCatalogEvents(ctx context.Context) error
+}
+
+// runCatalogListener keeps this replica subscribed to catalog changes made
+// by every replica, reconnecting when the listening connection breaks.
+func runCatalogListener(ctx context.Context, wg *sync.WaitGroup, logger *zap.Logger, listener catalogListener) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			err := listener.ListenCatalogEvents(ctx)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.Error("catalog event listener failed", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(catalogListenerRetryDelay):
+			}
+		}
+	}()
+}
+
+func runRest(
+	ctx context.Context,
+	cfg *config.Config,
+	logger *zap.Logger,
+	reloader *certs.Reloader,
+	gateway *controller.GatewayCredentials,
+) *http.Server {
+	mux := runtime.NewServeMux(gatewayOptions()...)
+	transport := insecure.NewCredentials()
+
+	if reloader != nil {
+		transport = credentials.NewTLS(reloader.ClientConfig())
+	}
+
+	conn, err := grpc.NewClient("localhost:"+cfg.GRPC.Port,
+		grpc.WithTransportCredentials(transport),
+		grpc.WithPerRPCCredentials(gateway))
+
+	if err != nil {
+		logger.Error("can not create grpc client", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		if err := conn.Close(); err != nil {
+			logger.Error("can not close grpc client", zap.Error(err))
+		}
+	}()
+
+	client := generated.NewLibraryClient(conn)
+
+	if err = generated.RegisterLibraryHandlerClient(ctx, mux, client); err != nil {
+		logger.Error("can not register grpc gateway", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	if err = generated.RegisterApiKeysHandler(ctx, mux, conn); err != nil {
+		logger.Error("can not register api keys gateway", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	// Streaming uploads have no generated gateway handler.
+	if err = mux.HandlePath(http.MethodPut, coverPath, uploadCoverHandler(mux, client)); err != nil {
+		logger.Error("can not register cover upload", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	server := &http.Server{
+		Addr:              ":" + cfg.GRPC.GatewayPort,
+		Handler:           serveCovers(cfg.Covers, inferUpdateMask(mux)),
+		ReadHeaderTimeout: shutdownTimeout,
+	}
+
+	go func() {
+		logger.Info("gateway listening", zap.String("address", server.Addr), zap.Bool("tls", reloader != nil))
+
+		if err := serveGateway(server, reloader); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("gateway listening error", zap.Error(err))
+		}
+	}()
+
+	return server
+}
+
+func serveGateway(server *http.Server, reloader *certs.Reloader) error {
+	if reloader == nil {
+		return server.ListenAndServe()
+	}
+
+	// The certificate comes from the TLS config.
+	server.TLSConfig = reloader.ServerConfig()
+
+	return server.ListenAndServeTLS("", "")
+}
+
+// newCertReloader returns nil when the listeners serve plaintext.
+func newCertReloader(cfg config.TLS, logger *zap.Logger) *certs.Reloader {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	reloader, err := certs.NewReloader(logger, cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile, cfg.MinVersion)
+
+	if err != nil {
+		logger.Error("can not load tls certificates", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	return reloader
+}
+
+type historyPruner interface {
+	PruneHistory(ctx context.Context, before time.Time) (int64, error)
+}
+
+// runHistoryPruner periodically drops the versions of authors and books that
+// are older than the configured retention.
+func runHistoryPruner(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	cfg *config.Config,
+	logger *zap.Logger,
+	pruner historyPruner,
+) {
+	if cfg.History.RetentionDays <= 0 {
+		return
+	}
+
+	retention := time.Duration(cfg.History.RetentionDays) * day
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(historyPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			pruned, err := pruner.PruneHistory(ctx, time.Now().Add(-retention))
+
+			if err != nil && ctx.Err() == nil {
+				logger.Error("can not prune history", zap.Error(err))
+			} else if pruned > 0 {
+				logger.Info("pruned history", zap.Int64("versions", pruned))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runIdempotencyPruner periodically deletes the expired idempotency keys.
+func runIdempotencyPruner(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	cfg *config.Config,
+	logger *zap.Logger,
+	keys idempotency.Idempotency,
+) {
+	if cfg.Idempotency.TTLHours <= 0 {
+		return
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(idempotencyPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			pruned, err := keys.Prune(ctx)
+
+			if err != nil && ctx.Err() == nil {
+				logger.Error("can not prune idempotency keys", zap.Error(err))
+			} else if pruned > 0 {
+				logger.Info("pruned idempotency keys", zap.Int64("keys", pruned))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+type holdExpirer interface {
+	ExpireHolds(ctx context.Context) (int, error)
+}
+
+// runHoldExpirer periodically closes the holds that were not picked up in
+// time, passing their copies to the next holds.
+func runHoldExpirer(ctx context.Context, wg *sync.WaitGroup, logger *zap.Logger, expirer holdExpirer) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(holdExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			expired, err := expirer.ExpireHolds(ctx)
+
+			if err != nil && ctx.Err() == nil {
+				logger.Error("can not expire holds", zap.Error(err))
+			} else if expired > 0 {
+				logger.Info("expired holds", zap.Int("holds", expired))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+type fineAccruer interface {
+	AccrueFines(ctx context.Context) (int, error)
+}
+
+// runFineAccruer periodically charges the fines of overdue loans. Every
+// replica runs it, the accrual itself makes sure only one of them works at a
+// time.
+func runFineAccruer(ctx context.Context, wg *sync.WaitGroup, logger *zap.Logger, accruer fineAccruer) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(fineAccrualInterval)
+		defer ticker.Stop()
+
+		for {
+			charged, err := accruer.AccrueFines(ctx)
+
+			if err != nil && ctx.Err() == nil {
+				logger.Error("can not accrue fines", zap.Error(err))
+			} else if charged > 0 {
+				logger.Info("accrued fines", zap.Int("loans", charged))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runGrpc(
+	cfg *config.Config,
+	logger *zap.Logger,
+	libraryService generated.LibraryServer,
+	apiKeysService generated.ApiKeysServer,
+	reloader *certs.Reloader,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	port := ":" + cfg.GRPC.Port
+	lis, err := net.Listen("tcp", port)
+
+	if err != nil {
+		logger.Error("can not open tcp socket", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	if reloader != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	}
+
+	s := grpc.NewServer(opts...)
+	reflection.Register(s)
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+	generated.RegisterLibraryServer(s, libraryService)
+	generated.RegisterApiKeysServer(s, apiKeysService)
+
+	go func() {
+		logger.Info("grpc server listening", zap.String("port", port), zap.Bool("tls", reloader != nil))
+
+		if err := s.Serve(lis); err != nil {
+			logger.Error("grpc server listening error", zap.Error(err))
+		}
+	}()
+
+	return s
+}
+
+// grpcInterceptors authenticate the callers by their API keys or bearer
+// tokens, authorize and throttle them before the handlers run. The requests
+// carrying the gateway credentials are throttled by the HTTP client.
+func grpcInterceptors(
+	cfg *config.Config,
+	logger *zap.Logger,
+	apiKeys apikey.APIKeys,
+	keys idempotency.Idempotency,
+	gateway *controller.GatewayCredentials,
+) []grpc.ServerOption {
+	authenticator := newAuthenticator(cfg.Auth, logger)
+	authorizer := newAuthorizer(cfg.Auth, logger)
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{controller.NewAPIKeyUnaryInterceptor(apiKeys)}
+	streamInterceptors := []grpc.StreamServerInterceptor{controller.NewAPIKeyStreamInterceptor(apiKeys)}
+
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, controller.NewAuthUnaryInterceptor(authenticator))
+		streamInterceptors = append(streamInterceptors, controller.NewAuthStreamInterceptor(authenticator))
+	}
+
+	// API keys identify their callers even when the endpoints are open, so
+	// the policy is enforced in every configuration.
+	open := authenticator == nil
+	unaryInterceptors = append(unaryInterceptors, controller.NewAuthzUnaryInterceptor(authorizer, open))
+	streamInterceptors = append(streamInterceptors, controller.NewAuthzStreamInterceptor(authorizer, open))
+
+	limiter := rateLimiter(cfg.RateLimit)
+	unaryInterceptors = append(unaryInterceptors, controller.NewRateLimitUnaryInterceptor(limiter, gateway))
+	streamInterceptors = append(streamInterceptors, controller.NewRateLimitStreamInterceptor(limiter, gateway))
+
+	unaryInterceptors = append(unaryInterceptors, controller.ActorUnaryInterceptor)
+	streamInterceptors = append(streamInterceptors, controller.ActorStreamInterceptor)
+
+	if cfg.Idempotency.TTLHours > 0 {
+		deadline := time.Duration(cfg.Idempotency.DeadlineSeconds) * time.Second
+		unaryInterceptors = append(unaryInterceptors, controller.NewIdempotencyUnaryInterceptor(keys, deadline))
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+}
+
+// newAuthenticator returns nil when the endpoints are open.
+func newAuthenticator(cfg config.Auth, logger *zap.Logger) auth.Authenticator {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	var keys repository.VerificationKeys
+
+	if cfg.JWKSFile != "" {
+		keys = repository.NewFileJWKS(cfg.JWKSFile)
+	} else {
+		keys = repository.NewURLJWKS(&http.Client{Timeout: jwksTimeout}, cfg.JWKSURL)
+	}
+
+	return auth.New(logger, keys, cfg.Issuer, cfg.Audience, cfg.RolesClaim)
+}
+
+// newAuthorizer loads the policy in every configuration: the roles come from
+// the bearer tokens and the scopes from the API keys. An unreadable policy
+// stops the service rather than leaving the methods unchecked.
+func newAuthorizer(cfg config.Auth, logger *zap.Logger) authz.Authorizer {
+	policy, err := authz.LoadPolicy(cfg.PolicyFile)
+
+	if err != nil {
+		logger.Error("can not load authorization policy", zap.Error(err))
+		os.Exit(-1)
+	}
+
+	return authz.New(logger, policy)
 }
 
-func runRest() {}
+// stopGrpc waits for in-flight calls until ctx is done and then drops the
+// rest, so that long-lived streams do not block the shutdown.
+func stopGrpc(ctx context.Context, s *grpc.Server) {
+	stopped := make(chan struct{})
 
-func runGrpc() {}
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
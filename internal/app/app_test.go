@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/project/library/config"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/idempotency"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// serve runs the services registered by register behind the interceptors
+// of the configuration and returns a connection to them.
+func serve(
+	t *testing.T,
+	cfg *config.Config,
+	apiKeys apikey.APIKeys,
+	register func(server *grpc.Server),
+) *grpc.ClientConn {
+	t.Helper()
+
+	repo := repository.NewInMemoryRepository()
+	keys := idempotency.New(zap.NewNop(), repo, time.Hour, time.Minute)
+	server := grpc.NewServer(grpcInterceptors(cfg, zap.NewNop(), apiKeys, keys, controller.NewGatewayCredentials())...)
+	register(server)
+
+	listener := bufconn.Listen(1 << 20)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// newAPIKeysClient serves the ApiKeys service and returns a client
+// connected to it.
+func newAPIKeysClient(t *testing.T, cfg *config.Config, apiKeys apikey.APIKeys) generated.ApiKeysClient {
+	t.Helper()
+
+	return generated.NewApiKeysClient(serve(t, cfg, apiKeys, func(server *grpc.Server) {
+		generated.RegisterApiKeysServer(server, controller.New(zap.NewNop(), controller.Deps{APIKeysUseCase: apiKeys}))
+	}))
+}
+
+func TestAPIKeysAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	apiKeys := apikey.New(zap.NewNop(), repository.NewInMemoryRepository())
+
+	// Without a key set the endpoints are open, yet the ApiKeys service
+	// requires a caller and the scopes of API keys are enforced.
+	client := newAPIKeysClient(t, &config.Config{}, apiKeys)
+
+	_, reader, err := apiKeys.Create(ctx, "indexer", []string{"read"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, admin, err := apiKeys.Create(ctx, "provisioner", []string{"admin"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	request := &generated.CreateApiKeyRequest{
+		Name:      "ci",
+		Scopes:    []string{"read"},
+		ExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+	}
+
+	_, err = client.CreateApiKey(ctx, request)
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+
+	_, err = client.CreateApiKey(metadata.AppendToOutgoingContext(ctx, controller.APIKeyMetadataKey, reader), request)
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+
+	_, err = client.CreateApiKey(metadata.AppendToOutgoingContext(ctx, controller.APIKeyMetadataKey, admin), request)
+	require.NoError(t, err)
+}
+
+func TestPublicRole(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	useCases := library.New(zap.NewNop(), library.Deps{
+		AuthorRepository:  repo,
+		BooksRepository:   repo,
+		CopiesRepository:  repo,
+		ReviewsRepository: repo,
+		CatalogRepository: repo,
+		AuditRepository:   repo,
+		OutboxRepository:  repo,
+		Transactor:        repo,
+	})
+
+	book, err := useCases.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	// The key set is never read, none of the calls sends a token.
+	cfg := &config.Config{Auth: config.Auth{JWKSFile: filepath.Join(t.TempDir(), "jwks.json")}}
+	conn := serve(t, cfg, apikey.New(zap.NewNop(), repo), func(server *grpc.Server) {
+		generated.RegisterLibraryServer(server, controller.New(zap.NewNop(), controller.Deps{
+			BooksUseCase:   useCases,
+			CopiesUseCase:  useCases,
+			ReviewsUseCase: useCases,
+		}))
+	})
+	client := generated.NewLibraryClient(conn)
+
+	got, err := client.GetBookInfo(ctx, &generated.GetBookInfoRequest{Id: book.ID})
+	require.NoError(t, err)
+	require.Equal(t, "Dune", got.GetBook().GetName())
+
+	_, err = client.AddBook(ctx, &generated.AddBookRequest{Name: "Dune Messiah"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), err)
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/project/library/config"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/usecase/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	coverPath      = "/v1/library/book/{id}/cover"
+	coverChunkSize = 64 << 10
+)
+
+func newBlobStore(cfg config.Covers) repository.BlobStore {
+	if cfg.Storage == config.CoverStorageS3 {
+		return repository.NewS3BlobStore(new(http.Client), repository.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKey,
+			SecretAccessKey: cfg.S3SecretKey,
+			PublicURL:       cfg.PublicURL,
+		})
+	}
+
+	return repository.NewFileBlobStore(cfg.Dir, cfg.PublicURL)
+}
+
+// serveCovers serves the covers kept in the file system next to the API,
+// covers kept in S3 are downloaded from the storage itself.
+func serveCovers(cfg config.Covers, api http.Handler) http.Handler {
+	if cfg.Storage != config.CoverStorageFS || !strings.HasPrefix(cfg.PublicURL, "/") {
+		return api
+	}
+
+	prefix := strings.TrimSuffix(cfg.PublicURL, "/") + "/"
+
+	mux := http.NewServeMux()
+	mux.Handle("/", api)
+	mux.Handle("GET "+prefix, http.StripPrefix(prefix, http.FileServer(http.Dir(cfg.Dir))))
+
+	return mux
+}
+
+// uploadCoverHandler streams the request body to UploadCover, the body is
+// the image itself and Content-Type its type.
+func uploadCoverHandler(mux *runtime.ServeMux, client generated.LibraryClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		_, marshaler := runtime.MarshalerForRequest(mux, r)
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, "/library.Library/UploadCover",
+			runtime.WithHTTPPathPattern(coverPath))
+
+		if err != nil {
+			runtime.HTTPError(r.Context(), mux, marshaler, w, r, err)
+			return
+		}
+
+		info := &generated.CoverInfo{BookId: params["id"], ContentType: r.Header.Get("Content-Type")}
+		response, err := uploadCover(ctx, client, info, r.Body)
+
+		if err != nil {
+			runtime.HTTPError(ctx, mux, marshaler, w, r, err)
+			return
+		}
+
+		runtime.ForwardResponseMessage(ctx, mux, marshaler, w, r, response)
+	}
+}
+
+func uploadCover(
+	ctx context.Context,
+	client generated.LibraryClient,
+	info *generated.CoverInfo,
+	body io.Reader,
+) (*generated.UploadCoverResponse, error) {
+	stream, err := client.UploadCover(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Info{Info: info}})
+	buffer := make([]byte, coverChunkSize)
+
+	for err == nil {
+		var n int
+		n, err = body.Read(buffer)
+
+		if n > 0 {
+			chunk := &generated.UploadCoverRequest_Chunk{Chunk: append([]byte(nil), buffer[:n]...)}
+
+			if sendErr := stream.Send(&generated.UploadCoverRequest{Data: chunk}); sendErr != nil {
+				err = sendErr
+			}
+		}
+	}
+
+	// io.EOF either ends the body or tells that the server has ended the
+	// stream early, the outcome comes with CloseAndRecv in both cases.
+	if errors.Is(err, io.EOF) {
+		return stream.CloseAndRecv()
+	}
+
+	if _, ok := status.FromError(err); !ok {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil, err
+}
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func gatewayOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithForwardResponseOption(setETag),
+		runtime.WithErrorHandler(preconditionErrorHandler),
+	}
+}
+
+func incomingHeaderMatcher(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case "If-Match":
+		return controller.IfMatchMetadataKey, true
+	case "X-Actor":
+		return controller.ActorMetadataKey, true
+	case "Idempotency-Key":
+		return controller.IdempotencyKeyMetadataKey, true
+	case "X-Api-Key":
+		return controller.APIKeyMetadataKey, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// setETag exposes the version of the returned entity as the ETag header.
+func setETag(_ context.Context, w http.ResponseWriter, message proto.Message) error {
+	var version uint64
+
+	switch response := message.(type) {
+	case *generated.AddBookResponse:
+		version = response.GetBook().GetVersion()
+	case *generated.GetBookInfoResponse:
+		version = response.GetBook().GetVersion()
+	case *generated.UpdateBookResponse:
+		version = response.GetVersion()
+	case *generated.GetAuthorInfoResponse:
+		version = response.GetVersion()
+	case *generated.ChangeAuthorInfoResponse:
+		version = response.GetVersion()
+	default:
+		return nil
+	}
+
+	w.Header().Set("ETag", controller.FormatETag(version))
+
+	return nil
+}
+
+// preconditionErrorHandler reports a failed If-Match check as
+// 412 Precondition Failed instead of the 409 Conflict used for ABORTED, the
+// other aborted requests stay 409. A throttled request, 429 Too Many
+// Requests, gets the Retry-After header.
+func preconditionErrorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	marshaler runtime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if r.Header.Get("If-Match") != "" && controller.IsVersionMismatch(err) {
+		w = &preconditionResponseWriter{ResponseWriter: w}
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		setRetryAfter(w, md)
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// setRetryAfter copies the wait of a throttled request from the metadata,
+// an error without a response carries its headers in the trailer.
+func setRetryAfter(w http.ResponseWriter, md runtime.ServerMetadata) {
+	for _, values := range [][]string{
+		md.HeaderMD.Get(controller.RetryAfterMetadataKey),
+		md.TrailerMD.Get(controller.RetryAfterMetadataKey),
+	} {
+		if len(values) > 0 {
+			w.Header().Set("Retry-After", values[0])
+			return
+		}
+	}
+}
+
+type preconditionResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (p *preconditionResponseWriter) WriteHeader(statusCode int) {
+	if statusCode == http.StatusConflict {
+		statusCode = http.StatusPreconditionFailed
+	}
+
+	p.ResponseWriter.WriteHeader(statusCode)
+}
+
+// patchRoute describes a PATCH route whose update mask is inferred from the
+// fields present in the request body.
+type patchRoute struct {
+	prefix     string
+	newRequest func() proto.Message
+	// maskable lists the paths the update mask may hold, with the nested
+	// paths of message fields. Other fields in the body are not updated
+	// through the mask.
+	maskable []string
+	// incremental lists the paths that change the entity on their own.
+	incremental []string
+}
+
+var errNothingToUpdate = errors.New("request body has no fields to update")
+
+var patchRoutes = []patchRoute{
+	{
+		prefix:     "/v1/library/book/",
+		newRequest: func() proto.Message { return &generated.UpdateBookRequest{} },
+		maskable: []string{
+			"name", "author_ids", "contributors", "isbn", "publisher", "publication_year", "language", "page_count", "description",
+			"subject_ids", "tags", "work_id", "series_id", "series_position",
+		},
+		incremental: []string{"add_author_ids", "remove_author_ids"},
+	},
+	{
+		prefix:     "/v1/library/author/",
+		newRequest: func() proto.Message { return &generated.ChangeAuthorInfoRequest{} },
+		maskable: []string{
+			"name", "biography", "birth_date", "death_date", "country", "aliases", "external_ids",
+		},
+	},
+	{
+		prefix:     "/v1/library/copy/",
+		newRequest: func() proto.Message { return &generated.UpdateCopyRequest{} },
+		maskable:   []string{"barcode", "branch", "location", "condition", "acquired_on", "status"},
+	},
+	{
+		prefix:     "/v1/library/patron/",
+		newRequest: func() proto.Message { return &generated.UpdatePatronRequest{} },
+		maskable: []string{
+			"card_number", "name", "email", "phone", "address", "membership_type", "expires_on",
+		},
+	},
+	{
+		prefix:     "/v1/library/subject/",
+		newRequest: func() proto.Message { return &generated.UpdateSubjectRequest{} },
+		maskable:   []string{"name", "parent_id"},
+	},
+	{
+		prefix:     "/v1/library/review/",
+		newRequest: func() proto.Message { return &generated.UpdateReviewRequest{} },
+		maskable:   []string{"rating", "text"},
+	},
+}
+
+// inferUpdateMask gives PATCH requests merge semantics: unless the body sets
+// updateMask itself, only the fields present in the body are updated.
+func inferUpdateMask(mux *runtime.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := findPatchRoute(r)
+
+		if route == nil {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+
+		if err == nil {
+			body, err = route.withUpdateMask(body)
+		}
+
+		if err != nil {
+			_, marshaler := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(r.Context(), mux, marshaler, w, r, status.Error(codes.InvalidArgument, err.Error()))
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func findPatchRoute(r *http.Request) *patchRoute {
+	if r.Method != http.MethodPatch {
+		return nil
+	}
+
+	for i := range patchRoutes {
+		if strings.HasPrefix(r.URL.Path, patchRoutes[i].prefix) {
+			return &patchRoutes[i]
+		}
+	}
+
+	return nil
+}
+
+func (p *patchRoute) withUpdateMask(body []byte) ([]byte, error) {
+	present, err := runtime.FieldMaskFromRequestBody(bytes.NewReader(body), p.newRequest())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(present.GetPaths(), "update_mask") {
+		return body, nil
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	incremental := false
+
+	for _, path := range present.GetPaths() {
+		if field, _, _ := strings.Cut(path, "."); slices.Contains(p.maskable, field) {
+			mask.Paths = append(mask.Paths, path)
+		}
+
+		incremental = incremental || slices.Contains(p.incremental, path)
+	}
+
+	// An empty mask would replace the whole entity.
+	if len(mask.GetPaths()) == 0 && !incremental {
+		return nil, errNothingToUpdate
+	}
+
+	return setUpdateMask(body, mask)
+}
+
+func setUpdateMask(body []byte, mask *fieldmaskpb.FieldMask) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	encoded, err := protojson.Marshal(mask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields["updateMask"] = encoded
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/project/library/internal/controller"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPreconditionErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	versionMismatch, err := status.New(codes.Aborted, "version mismatch").
+		WithDetails(&errdetails.PreconditionFailure{
+			Violations: []*errdetails.PreconditionFailure_Violation{{Type: "VERSION"}},
+		})
+	require.NoError(t, err)
+	require.True(t, controller.IsVersionMismatch(versionMismatch.Err()))
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		err     error
+		code    int
+	}{
+		{
+			name:    "version mismatch",
+			ifMatch: `"1"`,
+			err:     versionMismatch.Err(),
+			code:    http.StatusPreconditionFailed,
+		},
+		{
+			name: "version mismatch without If-Match",
+			err:  versionMismatch.Err(),
+			code: http.StatusConflict,
+		},
+		{
+			name:    "idempotency key in use",
+			ifMatch: `"1"`,
+			err:     status.Error(codes.Aborted, "idempotency key in use"),
+			code:    http.StatusConflict,
+		},
+		{
+			name:    "already exists",
+			ifMatch: `"1"`,
+			err:     status.Error(codes.AlreadyExists, "barcode taken"),
+			code:    http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPatch, "/v1/library/author/id", nil)
+
+			if test.ifMatch != "" {
+				r.Header.Set("If-Match", test.ifMatch)
+			}
+
+			w := httptest.NewRecorder()
+			preconditionErrorHandler(context.Background(), runtime.NewServeMux(), &runtime.JSONPb{}, w, r, test.err)
+			require.Equal(t, test.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/outbox"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func globalHandler(client *http.Client, cfg *config.Config) outbox.GlobalHandler {
+	return func(kind repository.OutboxKind) (outbox.KindHandler, error) {
+		switch kind {
+		case repository.OutboxKindBook:
+			return bookOutboxHandler(client, cfg.Outbox.BookSendURL), nil
+		case repository.OutboxKindAuthor:
+			return authorOutboxHandler(client, cfg.Outbox.AuthorSendURL), nil
+		case repository.OutboxKindPatron:
+			return patronOutboxHandler(client, cfg.Outbox.PatronSendURL), nil
+		case repository.OutboxKindLoan:
+			return loanOutboxHandler(client, cfg.Outbox.LoanSendURL), nil
+		case repository.OutboxKindHold:
+			return holdOutboxHandler(client, cfg.Outbox.HoldSendURL), nil
+		case repository.OutboxKindAuthorMerge:
+			return authorMergeOutboxHandler(client, cfg.Outbox.MergeSendURL), nil
+		default:
+			return nil, fmt.Errorf("unsupported outbox kind: %d", kind)
+		}
+	}
+}
+
+func bookOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var book entity.Book
+
+		if err := json.Unmarshal(data, &book); err != nil {
+			return fmt.Errorf("can not deserialize book from outbox: %w", err)
+		}
+
+		return sendID(ctx, client, url, book.ID)
+	}
+}
+
+func authorOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var author entity.Author
+
+		if err := json.Unmarshal(data, &author); err != nil {
+			return fmt.Errorf("can not deserialize author from outbox: %w", err)
+		}
+
+		return sendID(ctx, client, url, author.ID)
+	}
+}
+
+func patronOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var patron entity.Patron
+
+		if err := json.Unmarshal(data, &patron); err != nil {
+			return fmt.Errorf("can not deserialize patron from outbox: %w", err)
+		}
+
+		return sendID(ctx, client, url, patron.ID)
+	}
+}
+
+// loanOutboxHandler sends the whole event, the loan id alone does not tell
+// what has happened to the loan.
+func loanOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var event entity.LoanEvent
+
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("can not deserialize loan event from outbox: %w", err)
+		}
+
+		return send(ctx, client, url, "application/json", bytes.NewReader(data))
+	}
+}
+
+// holdOutboxHandler sends the whole event for the patron to be notified.
+func holdOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var event entity.HoldEvent
+
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("can not deserialize hold event from outbox: %w", err)
+		}
+
+		return send(ctx, client, url, "application/json", bytes.NewReader(data))
+	}
+}
+
+// authorMergeOutboxHandler sends the whole merge, the subscribers replace
+// the ids of the sources with the target id.
+func authorMergeOutboxHandler(client *http.Client, url string) outbox.KindHandler {
+	return func(ctx context.Context, data []byte) error {
+		var merge entity.AuthorMerge
+
+		if err := json.Unmarshal(data, &merge); err != nil {
+			return fmt.Errorf("can not deserialize author merge from outbox: %w", err)
+		}
+
+		return send(ctx, client, url, "application/json", bytes.NewReader(data))
+	}
+}
+
+func sendID(ctx context.Context, client *http.Client, url string, id string) error {
+	return send(ctx, client, url, "", strings.NewReader(id))
+}
+
+func send(ctx context.Context, client *http.Client, url string, contentType string, body io.Reader) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+
+	if err != nil {
+		return err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d from %s", response.StatusCode, url)
+	}
+
+	return nil
+}
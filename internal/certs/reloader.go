@@ -0,0 +1,255 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// checkInterval limits how often the files are checked for changes, the
+// check runs on a handshake.
+const checkInterval = 10 * time.Second
+
+var (
+	errNoClientCAs     = errors.New("no certificates in the client CA file")
+	errUnknownPeerCert = errors.New("peer certificate is not the served one")
+)
+
+// Reloader serves the certificate of the listeners and the CAs of their
+// clients, and reloads them when their files change on disk. A change that
+// fails to load is logged and the previous files keep being served.
+type Reloader struct {
+	logger       *zap.Logger
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	minVersion   uint16
+	interval     time.Duration
+
+	mx          *sync.Mutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	modTimes    []time.Time
+	checkedAt   time.Time
+}
+
+// NewReloader loads the files, clientCAFile may be empty to serve TLS
+// without client certificates.
+func NewReloader(
+	logger *zap.Logger,
+	certFile string,
+	keyFile string,
+	clientCAFile string,
+	minVersion uint16,
+) (*Reloader, error) {
+	r := &Reloader{
+		logger:       logger,
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		minVersion:   minVersion,
+		interval:     checkInterval,
+		mx:           new(sync.Mutex),
+	}
+
+	modTimes, err := r.stat()
+
+	if err == nil {
+		err = r.load(modTimes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.checkedAt = time.Now()
+
+	return r, nil
+}
+
+// ServerConfig returns the configuration of a listener. With a client CA
+// file the clients must present a certificate signed by one of its CAs, or
+// the certificate of the listener itself, which is what the gateway dials
+// with.
+func (r *Reloader) ServerConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: r.minVersion,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			certificate, _ := r.current()
+			return certificate, nil
+		},
+	}
+
+	if r.clientCAFile != "" {
+		// The chain is verified by verifyClient against the current CAs.
+		config.ClientAuth = tls.RequireAnyClientCert
+		config.VerifyPeerCertificate = r.verifyClient
+	}
+
+	return config
+}
+
+// ClientConfig returns the configuration the gateway dials the gRPC
+// listener of the same process with. It presents the certificate of the
+// listeners and accepts exactly that certificate from the server, whatever
+// names it holds.
+func (r *Reloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certificate, _ := r.current()
+			return certificate, nil
+		},
+		// VerifyConnection pins the served certificate instead.
+		InsecureSkipVerify: true, //nolint:gosec // pinned to the served leaf by VerifyConnection
+		VerifyConnection: func(state tls.ConnectionState) error {
+			certificate, _ := r.current()
+
+			if len(state.PeerCertificates) == 0 || !bytes.Equal(state.PeerCertificates[0].Raw, certificate.Leaf.Raw) {
+				return errUnknownPeerCert
+			}
+
+			return nil
+		},
+	}
+}
+
+func (r *Reloader) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	certificate, clientCAs := r.current()
+
+	if len(rawCerts) == 0 {
+		return errUnknownPeerCert
+	}
+
+	if bytes.Equal(rawCerts[0], certificate.Leaf.Raw) {
+		return nil
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+
+		if err != nil {
+			return err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	return err
+}
+
+// current returns the certificate and the client CAs, reloading them first
+// if their files have changed.
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if now := time.Now(); now.Sub(r.checkedAt) >= r.interval {
+		r.checkedAt = now
+		r.reload()
+	}
+
+	return r.certificate, r.clientCAs
+}
+
+func (r *Reloader) reload() {
+	modTimes, err := r.stat()
+
+	if err == nil && slices.EqualFunc(modTimes, r.modTimes, time.Time.Equal) {
+		return
+	}
+
+	if err == nil {
+		err = r.load(modTimes)
+	}
+
+	if err != nil {
+		r.logger.Error("can not reload tls certificates", zap.Error(err))
+		return
+	}
+
+	r.logger.Info("tls certificates reloaded", zap.String("cert_file", r.certFile))
+}
+
+func (r *Reloader) files() []string {
+	if r.clientCAFile == "" {
+		return []string{r.certFile, r.keyFile}
+	}
+
+	return []string{r.certFile, r.keyFile, r.clientCAFile}
+}
+
+func (r *Reloader) stat() ([]time.Time, error) {
+	files := r.files()
+	modTimes := make([]time.Time, 0, len(files))
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func (r *Reloader) load(modTimes []time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+
+	if r.clientCAFile != "" {
+		if clientCAs, err = loadCertPool(r.clientCAFile); err != nil {
+			return err
+		}
+	}
+
+	r.certificate, r.clientCAs, r.modTimes = &certificate, clientCAs, modTimes
+
+	return nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s: %w", path, errNoClientCAs)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,195 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writePair(t *testing.T, certFile string, keyFile string, modTime time.Time) tls.Certificate {
+	t.Helper()
+
+	certPEM, keyPEM, err := SelfSigned(time.Hour, "localhost", "127.0.0.1")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	return pair
+}
+
+// handshake returns the certificate served to the client and the error of
+// the server side.
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) ([]byte, error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	errs := make(chan error, 1)
+
+	go func() {
+		serverConn, acceptErr := listener.Accept()
+
+		if acceptErr != nil {
+			errs <- acceptErr
+			return
+		}
+
+		conn := tls.Server(serverConn, server)
+		handshakeErr := conn.Handshake()
+
+		// TLS 1.3 clients finish before the server checks their certificate.
+		if handshakeErr == nil {
+			_, handshakeErr = conn.Write([]byte{1})
+		}
+
+		errs <- handshakeErr
+		_ = serverConn.Close()
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	conn := tls.Client(clientConn, client)
+	err = conn.Handshake()
+
+	var served []byte
+
+	if err == nil {
+		served = conn.ConnectionState().PeerCertificates[0].Raw
+		_, err = conn.Read(make([]byte, 1))
+	}
+
+	_ = clientConn.Close()
+	serverErr := <-errs
+
+	if serverErr == nil {
+		require.NoError(t, err)
+	}
+
+	return served, serverErr
+}
+
+func TestReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	first := writePair(t, certFile, keyFile, time.Now().Add(-time.Hour))
+
+	reloader, err := NewReloader(zap.NewNop(), certFile, keyFile, "", tls.VersionTLS12)
+	require.NoError(t, err)
+
+	reloader.interval = 0
+
+	served, err := handshake(t, reloader.ServerConfig(), reloader.ClientConfig())
+	require.NoError(t, err)
+	require.Equal(t, first.Leaf.Raw, served)
+
+	// A client trusting the certificate verifies it as usual.
+	roots := x509.NewCertPool()
+	roots.AddCert(first.Leaf)
+
+	_, err = handshake(t, reloader.ServerConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12})
+	require.NoError(t, err)
+
+	second := writePair(t, certFile, keyFile, time.Now())
+
+	served, err = handshake(t, reloader.ServerConfig(), reloader.ClientConfig())
+	require.NoError(t, err)
+	require.Equal(t, second.Leaf.Raw, served)
+
+	// A broken write keeps the previous certificate.
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	require.NoError(t, os.Chtimes(certFile, time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+
+	served, err = handshake(t, reloader.ServerConfig(), reloader.ClientConfig())
+	require.NoError(t, err)
+	require.Equal(t, second.Leaf.Raw, served)
+
+	_, err = handshake(t, reloader.ServerConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12})
+	require.Error(t, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	caFile, caKeyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")
+	served := writePair(t, certFile, keyFile, time.Now())
+	client := writePair(t, caFile, caKeyFile, time.Now())
+
+	reloader, err := NewReloader(zap.NewNop(), certFile, keyFile, caFile, tls.VersionTLS13)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(served.Leaf)
+
+	clientConfig := func(certificates ...tls.Certificate) *tls.Config {
+		return &tls.Config{
+			RootCAs:      roots,
+			ServerName:   "localhost",
+			Certificates: certificates,
+			MinVersion:   tls.VersionTLS13,
+		}
+	}
+
+	_, err = handshake(t, reloader.ServerConfig(), clientConfig(client))
+	require.NoError(t, err)
+
+	// The gateway presents the certificate of the listener.
+	_, err = handshake(t, reloader.ServerConfig(), reloader.ClientConfig())
+	require.NoError(t, err)
+
+	_, err = handshake(t, reloader.ServerConfig(), clientConfig())
+	require.Error(t, err)
+
+	otherCert, otherKey := filepath.Join(dir, "other.pem"), filepath.Join(dir, "other-key.pem")
+	other := writePair(t, otherCert, otherKey, time.Now())
+
+	_, err = handshake(t, reloader.ServerConfig(), clientConfig(other))
+	require.Error(t, err)
+
+	_, err = NewReloader(zap.NewNop(), certFile, keyFile, keyFile, tls.VersionTLS13)
+	require.Error(t, err)
+}
+
+func TestVerifyClient(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	caFile, caKeyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")
+	otherCert, otherKey := filepath.Join(dir, "other.pem"), filepath.Join(dir, "other-key.pem")
+	served := writePair(t, certFile, keyFile, time.Now())
+	client := writePair(t, caFile, caKeyFile, time.Now())
+	other := writePair(t, otherCert, otherKey, time.Now())
+
+	reloader, err := NewReloader(zap.NewNop(), certFile, keyFile, caFile, tls.VersionTLS13)
+	require.NoError(t, err)
+
+	require.NoError(t, reloader.verifyClient([][]byte{served.Leaf.Raw}, nil))
+	require.NoError(t, reloader.verifyClient([][]byte{client.Leaf.Raw}, nil))
+
+	// A certificate neither served nor signed by a client CA is rejected,
+	// even when it follows the served one.
+	require.Error(t, reloader.verifyClient([][]byte{other.Leaf.Raw}, nil))
+	require.Error(t, reloader.verifyClient([][]byte{other.Leaf.Raw, served.Leaf.Raw}, nil))
+	require.ErrorIs(t, reloader.verifyClient(nil, nil), errUnknownPeerCert)
+	require.Error(t, reloader.verifyClient([][]byte{[]byte("garbage")}, nil))
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ActorMetadataKey carries the name of whoever makes the request. It is
+// recorded in the audit log.
+const ActorMetadataKey = "x-actor"
+
+// ActorUnaryInterceptor puts the actor from the request metadata into the
+// context of the handler.
+func ActorUnaryInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	if values := metadata.ValueFromIncomingContext(ctx, ActorMetadataKey); len(values) > 0 {
+		ctx = entity.WithActor(ctx, values[0])
+	}
+
+	return handler(ctx, req)
+}
+
+// ActorStreamInterceptor works as ActorUnaryInterceptor for streaming calls.
+func ActorStreamInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if values := metadata.ValueFromIncomingContext(stream.Context(), ActorMetadataKey); len(values) > 0 {
+		stream = &contextStream{ServerStream: stream, ctx: entity.WithActor(stream.Context(), values[0])}
+	}
+
+	return handler(srv, stream)
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (c *contextStream) Context() context.Context {
+	return c.ctx
+}
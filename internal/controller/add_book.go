@@ -1 +1,65 @@
 package controller
+
+import (
+	"context"
+	"errors"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *implementation) AddBook(ctx context.Context, req *generated.AddBookRequest) (*generated.AddBookResponse, error) {
+	i.logger.Info("received AddBook request",
+		zap.String("name", req.GetName()),
+		zap.Strings("author_ids", req.GetAuthorIds()),
+		zap.String("isbn", req.GetIsbn()),
+		zap.Bool("allow_duplicate", req.GetAllowDuplicate()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	contributors, err := bookCredits(req.GetAuthorIds(), req.GetContributors())
+
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := i.booksUseCase.RegisterBook(ctx, req.GetName(), contributors, toBookMetadata(req),
+		req.GetAllowDuplicate())
+
+	var duplicate *entity.DuplicateBookError
+
+	if errors.As(err, &duplicate) {
+		return nil, duplicateBookStatus(duplicate)
+	}
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.AddBookResponse{
+		Book: toProtoBook(book),
+	}, nil
+}
+
+// duplicateBookStatus is AlreadyExists naming the existing book in the
+// message and in a ResourceInfo detail, which the gateway returns as well.
+func duplicateBookStatus(duplicate *entity.DuplicateBookError) error {
+	result := status.New(codes.AlreadyExists, duplicate.Error())
+	detailed, err := result.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: "library.Book",
+		ResourceName: duplicate.BookID,
+		Description:  "the existing book",
+	})
+
+	if err != nil {
+		return result.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) AddCopy(ctx context.Context, req *generated.AddCopyRequest) (*generated.AddCopyResponse, error) {
+	i.logger.Info("received AddCopy request",
+		zap.String("book_id", req.GetBookId()),
+		zap.String("barcode", req.GetBarcode()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	bookCopy, err := i.copiesUseCase.AddCopy(ctx, entity.Copy{
+		BookID:     req.GetBookId(),
+		Barcode:    req.GetBarcode(),
+		Branch:     req.GetBranch(),
+		Location:   req.GetLocation(),
+		Condition:  toCopyCondition(req.GetCondition()),
+		AcquiredOn: req.GetAcquiredOn(),
+		Status:     toCopyStatus(req.GetStatus()),
+	})
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.AddCopyResponse{
+		Copy: toProtoCopy(bookCopy),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ generated.ApiKeysServer = (*implementation)(nil)
+
+func toProtoAPIKey(key entity.APIKey) *generated.ApiKey {
+	result := &generated.ApiKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Scopes:    key.Scopes,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+		ExpiresAt: timestamppb.New(key.ExpiresAt),
+	}
+
+	if key.LastUsedAt != nil {
+		result.LastUsedAt = timestamppb.New(*key.LastUsedAt)
+	}
+
+	if key.RevokedAt != nil {
+		result.RevokedAt = timestamppb.New(*key.RevokedAt)
+	}
+
+	return result
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAPIKeyHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).apiKeys
+	expiresAt := timestamppb.New(time.Now().Add(time.Hour))
+
+	created, err := client.CreateApiKey(ctx, &generated.CreateApiKeyRequest{
+		Name:      "indexer",
+		Scopes:    []string{"read"},
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.GetSecret())
+
+	_, err = client.CreateApiKey(ctx, &generated.CreateApiKeyRequest{Name: "indexer", Scopes: []string{"read"}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	rotated, err := client.RotateApiKey(ctx, &generated.RotateApiKeyRequest{Id: created.GetApiKey().GetId()})
+	require.NoError(t, err)
+	require.NotEqual(t, created.GetSecret(), rotated.GetSecret())
+
+	revoked, err := client.RevokeApiKey(ctx, &generated.RevokeApiKeyRequest{Id: created.GetApiKey().GetId()})
+	require.NoError(t, err)
+	require.NotNil(t, revoked.GetApiKey().GetRevokedAt())
+
+	_, err = client.RotateApiKey(ctx, &generated.RotateApiKeyRequest{Id: created.GetApiKey().GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	keys, err := client.ListApiKeys(ctx, &generated.ListApiKeysRequest{})
+	require.NoError(t, err)
+	require.Empty(t, keys.GetApiKeys())
+
+	keys, err = client.ListApiKeys(ctx, &generated.ListApiKeysRequest{IncludeRevoked: true})
+	require.NoError(t, err)
+	require.Len(t, keys.GetApiKeys(), 1)
+	require.Equal(t, expiresAt.AsTime(), keys.GetApiKeys()[0].GetExpiresAt().AsTime())
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationMetadataKey carries the bearer token of a request, the
+// gateway forwards the Authorization header in it.
+const AuthorizationMetadataKey = "authorization"
+
+// APIKeyMetadataKey carries the key of a service calling without a bearer
+// token.
+const APIKeyMetadataKey = "x-api-key"
+
+// exemptServices are served without a token, so probes and tooling keep
+// working.
+var exemptServices = []string{"/grpc.health.", "/grpc.reflection."}
+
+// NewAuthUnaryInterceptor rejects requests with an invalid bearer token and
+// puts the principal of the token into the context of the handler. The
+// requests without credentials go on without a principal, the authorization
+// interceptor holds them to the public role.
+func NewAuthUnaryInterceptor(authenticator auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewAuthStreamInterceptor works as NewAuthUnaryInterceptor for streaming
+// calls.
+func NewAuthStreamInterceptor(authenticator auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// NewAPIKeyUnaryInterceptor authenticates the requests sending an API key
+// and puts the principal of the key into the context of the handler. The
+// requests without one are left to the bearer token.
+func NewAPIKeyUnaryInterceptor(apiKeys apikey.APIKeys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticateAPIKey(ctx, apiKeys, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewAPIKeyStreamInterceptor works as NewAPIKeyUnaryInterceptor for
+// streaming calls.
+func NewAPIKeyStreamInterceptor(apiKeys apikey.APIKeys) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateAPIKey(stream.Context(), apiKeys, info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authenticateAPIKey(ctx context.Context, apiKeys apikey.APIKeys, fullMethod string) (context.Context, error) {
+	values := metadata.ValueFromIncomingContext(ctx, APIKeyMetadataKey)
+
+	if exempt(fullMethod) || len(values) == 0 {
+		return ctx, nil
+	}
+
+	principal, err := apiKeys.Authenticate(ctx, strings.TrimSpace(values[0]))
+
+	if err != nil {
+		return nil, authenticationError(err)
+	}
+
+	return entity.WithPrincipal(ctx, principal), nil
+}
+
+func authenticate(ctx context.Context, authenticator auth.Authenticator, fullMethod string) (context.Context, error) {
+	// The caller may have sent an API key instead.
+	if _, ok := entity.PrincipalFromContext(ctx); ok || exempt(fullMethod) {
+		return ctx, nil
+	}
+
+	values := metadata.ValueFromIncomingContext(ctx, AuthorizationMetadataKey)
+
+	// The callers without credentials are left to the policy of the public
+	// role.
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	principal, err := authenticator.Authenticate(ctx, strings.TrimSpace(token))
+
+	if err != nil {
+		return nil, authenticationError(err)
+	}
+
+	return entity.WithPrincipal(ctx, principal), nil
+}
+
+// authenticationError tells invalid credentials from a failure to check
+// them.
+func authenticationError(err error) error {
+	if errors.Is(err, entity.ErrUnauthenticated) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return status.Error(codes.Unavailable, "can not verify the credentials")
+}
+
+func exempt(fullMethod string) bool {
+	for _, prefix := range exemptServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const getBookInfoMethod = "/library.Library/GetBookInfo"
+
+// fakeAuthenticator accepts the token "valid" for alice, fails to check the
+// token "down" and rejects the others.
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(_ context.Context, token string) (entity.Principal, error) {
+	switch token {
+	case "valid":
+		return entity.Principal{Subject: "alice", Roles: []string{"reader"}}, nil
+	case "down":
+		return entity.Principal{}, errors.New("issuer is down")
+	default:
+		return entity.Principal{}, entity.ErrUnauthenticated
+	}
+}
+
+// fakeServerStream is a server stream with a context, which records the
+// headers sent.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) SetHeader(md metadata.MD) error {
+	f.header = metadata.Join(f.header, md)
+	return nil
+}
+
+// principalHandler is a fake handler returning the subject of the principal
+// in its context.
+func principalHandler(ctx context.Context, _ any) (any, error) {
+	principal, _ := entity.PrincipalFromContext(ctx)
+	return principal.Subject, nil
+}
+
+func withMetadata(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		method  string
+		code    codes.Code
+		subject string
+	}{
+		{
+			name:    "valid token",
+			ctx:     withMetadata(AuthorizationMetadataKey, "Bearer valid"),
+			method:  getBookInfoMethod,
+			subject: "alice",
+		},
+		{
+			name:    "lowercase scheme",
+			ctx:     withMetadata(AuthorizationMetadataKey, "bearer  valid "),
+			method:  getBookInfoMethod,
+			subject: "alice",
+		},
+		{
+			name:   "missing token",
+			ctx:    context.Background(),
+			method: getBookInfoMethod,
+		},
+		{
+			name:   "basic credentials",
+			ctx:    withMetadata(AuthorizationMetadataKey, "Basic YWxpY2U6c2VjcmV0"),
+			method: getBookInfoMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "token without a scheme",
+			ctx:    withMetadata(AuthorizationMetadataKey, "valid"),
+			method: getBookInfoMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "bad token",
+			ctx:    withMetadata(AuthorizationMetadataKey, "Bearer forged"),
+			method: getBookInfoMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "keys unavailable",
+			ctx:    withMetadata(AuthorizationMetadataKey, "Bearer down"),
+			method: getBookInfoMethod,
+			code:   codes.Unavailable,
+		},
+		{
+			name:   "health check",
+			ctx:    context.Background(),
+			method: "/grpc.health.v1.Health/Check",
+		},
+		{
+			name:    "authenticated by an API key",
+			ctx:     entity.WithPrincipal(context.Background(), entity.Principal{Subject: "api-key:id"}),
+			method:  getBookInfoMethod,
+			subject: "api-key:id",
+		},
+	}
+
+	unary := NewAuthUnaryInterceptor(fakeAuthenticator{})
+	stream := NewAuthStreamInterceptor(fakeAuthenticator{})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, err := unary(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, principalHandler)
+			require.Equal(t, test.code, status.Code(err), err)
+
+			if err == nil {
+				require.Equal(t, test.subject, subject)
+			}
+
+			calls := 0
+			err = stream(nil, &fakeServerStream{ctx: test.ctx}, &grpc.StreamServerInfo{FullMethod: test.method},
+				func(_ any, ss grpc.ServerStream) error {
+					calls++
+					streamSubject, _ := principalHandler(ss.Context(), nil)
+					require.Equal(t, test.subject, streamSubject)
+
+					return nil
+				})
+			require.Equal(t, test.code, status.Code(err), err)
+			require.Equal(t, test.code == codes.OK, calls == 1)
+		})
+	}
+}
+
+func TestAPIKeyInterceptor(t *testing.T) {
+	t.Parallel()
+
+	apiKeys := apikey.New(zap.NewNop(), repository.NewInMemoryRepository())
+
+	key, secret, err := apiKeys.Create(context.Background(), "indexer", []string{"read"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, expired, err := apiKeys.Create(context.Background(), "old", []string{"read"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		method  string
+		code    codes.Code
+		subject string
+	}{
+		{
+			name:    "valid key",
+			ctx:     withMetadata(APIKeyMetadataKey, " "+secret+" "),
+			method:  getBookInfoMethod,
+			subject: "api-key:" + key.ID,
+		},
+		{
+			name:   "expired key",
+			ctx:    withMetadata(APIKeyMetadataKey, expired),
+			method: getBookInfoMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "malformed key",
+			ctx:    withMetadata(APIKeyMetadataKey, "secret"),
+			method: getBookInfoMethod,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "left to the bearer token",
+			ctx:    withMetadata(AuthorizationMetadataKey, "Bearer valid"),
+			method: getBookInfoMethod,
+		},
+		{
+			name:   "health check",
+			ctx:    withMetadata(APIKeyMetadataKey, "secret"),
+			method: "/grpc.health.v1.Health/Check",
+		},
+	}
+
+	unary := NewAPIKeyUnaryInterceptor(apiKeys)
+	stream := NewAPIKeyStreamInterceptor(apiKeys)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, err := unary(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, principalHandler)
+			require.Equal(t, test.code, status.Code(err), err)
+
+			if err == nil {
+				require.Equal(t, test.subject, subject)
+			}
+
+			err = stream(nil, &fakeServerStream{ctx: test.ctx}, &grpc.StreamServerInfo{FullMethod: test.method},
+				func(_ any, ss grpc.ServerStream) error {
+					streamSubject, _ := principalHandler(ss.Context(), nil)
+					require.Equal(t, test.subject, streamSubject)
+
+					return nil
+				})
+			require.Equal(t, test.code, status.Code(err), err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminServices manage credentials, so they are never open to callers
+// without a principal.
+var adminServices = []string{"/" + generated.ApiKeys_ServiceDesc.ServiceName + "/"}
+
+// NewAuthzUnaryInterceptor rejects requests whose principal is not granted
+// the method, the requests without one are held to the public role and
+// rejected as unauthenticated. It runs after the authentication
+// interceptors. With open set
+// the requests without a principal are let through, except to the admin
+// services: no bearer tokens are verified then and only the callers sending
+// an API key are identified. The methods granted on the records of the
+// caller only are marked so in the context of the handler.
+func NewAuthzUnaryInterceptor(authorizer authz.Authorizer, open bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authorizer, info.FullMethod, open)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewAuthzStreamInterceptor works as NewAuthzUnaryInterceptor for streaming
+// calls.
+func NewAuthzStreamInterceptor(authorizer authz.Authorizer, open bool) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authorizer, info.FullMethod, open)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authorize(
+	ctx context.Context,
+	authorizer authz.Authorizer,
+	fullMethod string,
+	open bool,
+) (context.Context, error) {
+	if exempt(fullMethod) {
+		return ctx, nil
+	}
+
+	_, authenticated := entity.PrincipalFromContext(ctx)
+
+	if !authenticated && open && !adminOnly(fullMethod) {
+		return ctx, nil
+	}
+
+	ctx, err := authorizer.Authorize(ctx, fullMethod)
+
+	// The callers without credentials may be granted the method once they
+	// sign in.
+	if err != nil && !authenticated {
+		return nil, status.Errorf(codes.Unauthenticated, "%s: %s", entity.ErrUnauthenticated, fullMethod)
+	}
+
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "%s: %s", err, fullMethod)
+	}
+
+	return ctx, nil
+}
+
+func adminOnly(fullMethod string) bool {
+	for _, prefix := range adminServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/authz"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthzInterceptor(t *testing.T) {
+	t.Parallel()
+
+	withRoles := func(roles ...string) context.Context {
+		return entity.WithPrincipal(context.Background(), entity.Principal{Subject: "alice", Roles: roles})
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		open   bool
+		code   codes.Code
+	}{
+		{
+			name:   "granted role",
+			ctx:    withRoles("reader"),
+			method: getBookInfoMethod,
+		},
+		{
+			name:   "one of the roles granted",
+			ctx:    withRoles("guest", "librarian"),
+			method: "/library.Library/AddBook",
+		},
+		{
+			name:   "forbidden role",
+			ctx:    withRoles("reader"),
+			method: "/library.Library/AddBook",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "no roles",
+			ctx:    withRoles(),
+			method: getBookInfoMethod,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "unknown method",
+			ctx:    withRoles("admin"),
+			method: "/other.Library/GetBookInfo",
+			code:   codes.PermissionDenied,
+		},
+		{
+			name: "granted scope",
+			ctx: entity.WithPrincipal(context.Background(),
+				entity.Principal{Subject: "api-key:id", Scopes: []string{"read"}}),
+			method: getBookInfoMethod,
+		},
+		{
+			name:   "public role",
+			ctx:    context.Background(),
+			method: getBookInfoMethod,
+		},
+		{
+			name:   "unauthenticated",
+			ctx:    context.Background(),
+			method: "/library.Library/AddBook",
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "unauthenticated with open endpoints",
+			ctx:    context.Background(),
+			method: "/library.Library/AddBook",
+			open:   true,
+		},
+		{
+			name:   "unauthenticated API keys call with open endpoints",
+			ctx:    context.Background(),
+			method: "/library.ApiKeys/CreateApiKey",
+			open:   true,
+			code:   codes.Unauthenticated,
+		},
+		{
+			name: "forbidden scope with open endpoints",
+			ctx: entity.WithPrincipal(context.Background(),
+				entity.Principal{Subject: "api-key:id", Scopes: []string{"read"}}),
+			method: "/library.Library/AddBook",
+			open:   true,
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "health check",
+			ctx:    context.Background(),
+			method: "/grpc.health.v1.Health/Check",
+		},
+	}
+
+	authorizer := authz.New(zap.NewNop(), authz.DefaultPolicy())
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			unary := NewAuthzUnaryInterceptor(authorizer, test.open)
+			stream := NewAuthzStreamInterceptor(authorizer, test.open)
+
+			calls := 0
+			handler := func(context.Context, any) (any, error) {
+				calls++
+				return &generated.GetBookInfoResponse{}, nil
+			}
+
+			_, err := unary(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			require.Equal(t, test.code, status.Code(err), err)
+
+			err = stream(nil, &fakeServerStream{ctx: test.ctx}, &grpc.StreamServerInfo{FullMethod: test.method},
+				func(any, grpc.ServerStream) error {
+					calls++
+					return nil
+				})
+			require.Equal(t, test.code, status.Code(err), err)
+
+			// The handler only runs for granted methods.
+			require.Equal(t, test.code == codes.OK, calls == 2)
+			require.Equal(t, test.code != codes.OK, calls == 0)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CancelHold(ctx context.Context, req *generated.CancelHoldRequest) (*generated.CancelHoldResponse, error) {
+	i.logger.Info("received CancelHold request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	hold, err := i.holdsUseCase.CancelHold(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CancelHoldResponse{
+		Hold: toProtoHold(hold),
+	}, nil
+}
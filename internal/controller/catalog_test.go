@@ -0,0 +1,532 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func registerAuthor(t *testing.T, client generated.LibraryClient, name string) string {
+	t.Helper()
+
+	resp, err := client.RegisterAuthor(context.Background(), &generated.RegisterAuthorRequest{Name: name})
+	require.NoError(t, err)
+
+	return resp.GetId()
+}
+
+func addBook(t *testing.T, client generated.LibraryClient, name string, authorIDs ...string) *generated.Book {
+	t.Helper()
+
+	resp, err := client.AddBook(context.Background(), &generated.AddBookRequest{Name: name, AuthorIds: authorIDs})
+	require.NoError(t, err)
+
+	return resp.GetBook()
+}
+
+func receiveAll[T any](t *testing.T, stream grpc.ServerStreamingClient[T]) ([]*T, error) {
+	t.Helper()
+
+	var messages []*T
+
+	for {
+		message, err := stream.Recv()
+
+		if errors.Is(err, io.EOF) {
+			return messages, nil
+		}
+
+		if err != nil {
+			return messages, err
+		}
+
+		messages = append(messages, message)
+	}
+}
+
+func TestAuthorHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+
+	registered, err := client.RegisterAuthor(ctx, &generated.RegisterAuthorRequest{
+		Name:        "Frank Herbert",
+		BirthDate:   "1920-10-08",
+		Country:     "US",
+		Aliases:     []string{"Frank Patrick Herbert"},
+		ExternalIds: &generated.ExternalIds{Wikidata: "Q7934"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.RegisterAuthor(ctx, &generated.RegisterAuthorRequest{Name: "Frank  Herbert"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = client.RegisterAuthor(ctx, &generated.RegisterAuthorRequest{Name: "Other", BirthDate: "1920-13-01"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = client.RegisterAuthor(ctx, &generated.RegisterAuthorRequest{
+		Name:        "Other",
+		ExternalIds: &generated.ExternalIds{Wikidata: "Q7934"},
+	})
+	requireCode(t, codes.AlreadyExists, err)
+
+	author, err := client.GetAuthorInfo(ctx, &generated.GetAuthorInfoRequest{Id: registered.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Frank Herbert", author.GetName())
+	require.Equal(t, "Q7934", author.GetExternalIds().GetWikidata())
+
+	found, err := client.GetAuthorByExternalId(ctx, &generated.GetAuthorByExternalIdRequest{
+		Scheme: generated.GetAuthorByExternalIdRequest_SCHEME_WIKIDATA,
+		Value:  "Q7934",
+	})
+	require.NoError(t, err)
+	require.Equal(t, registered.GetId(), found.GetAuthor().GetId())
+
+	_, err = client.GetAuthorByExternalId(ctx, &generated.GetAuthorByExternalIdRequest{
+		Scheme: generated.GetAuthorByExternalIdRequest_SCHEME_VIAF,
+		Value:  "1",
+	})
+	requireCode(t, codes.NotFound, err)
+
+	// A stale If-Match header aborts the change.
+	stale := metadata.AppendToOutgoingContext(ctx, IfMatchMetadataKey, FormatETag(author.GetVersion()+1))
+	_, err = client.ChangeAuthorInfo(stale, &generated.ChangeAuthorInfoRequest{
+		Id:         registered.GetId(),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"biography"}},
+		Biography:  "Wrote Dune.",
+	})
+	requireCode(t, codes.Aborted, err)
+	require.True(t, IsVersionMismatch(err))
+
+	current := metadata.AppendToOutgoingContext(ctx, IfMatchMetadataKey, FormatETag(author.GetVersion()))
+	changed, err := client.ChangeAuthorInfo(current, &generated.ChangeAuthorInfoRequest{
+		Id:         registered.GetId(),
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"biography", "external_ids.viaf"}},
+		Biography:  "Wrote Dune.",
+		ExternalIds: &generated.ExternalIds{
+			Viaf: "59083591",
+		},
+	})
+	require.NoError(t, err)
+	require.Greater(t, changed.GetVersion(), author.GetVersion())
+
+	_, err = client.ChangeAuthorInfo(ctx, &generated.ChangeAuthorInfoRequest{Id: registered.GetId()})
+	requireCode(t, codes.InvalidArgument, err)
+
+	malformed := metadata.AppendToOutgoingContext(ctx, IfMatchMetadataKey, "W/1")
+	_, err = client.ChangeAuthorInfo(malformed, &generated.ChangeAuthorInfoRequest{Id: registered.GetId(), Name: "F"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	author, err = client.GetAuthorInfo(ctx, &generated.GetAuthorInfoRequest{Id: registered.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Frank Herbert", author.GetName())
+	require.Equal(t, "Wrote Dune.", author.GetBiography())
+	require.Equal(t, "Q7934", author.GetExternalIds().GetWikidata())
+	require.Equal(t, "59083591", author.GetExternalIds().GetViaf())
+
+	past, err := client.GetAuthorInfo(ctx, &generated.GetAuthorInfoRequest{
+		Id:   registered.GetId(),
+		AsOf: timestamppb.New(time.Now().Add(-time.Hour)),
+	})
+	requireCode(t, codes.NotFound, err)
+	require.Nil(t, past)
+
+	history, err := client.GetAuthorHistory(ctx, &generated.GetAuthorHistoryRequest{Id: registered.GetId(), PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, history.GetRecords(), 1)
+	require.NotEmpty(t, history.GetNextPageToken())
+
+	history, err = client.GetAuthorHistory(ctx, &generated.GetAuthorHistoryRequest{
+		Id:        registered.GetId(),
+		PageToken: history.GetNextPageToken(),
+	})
+	require.NoError(t, err)
+	require.Len(t, history.GetRecords(), 1)
+	require.Empty(t, history.GetNextPageToken())
+
+	_, err = client.GetAuthorInfo(ctx, &generated.GetAuthorInfoRequest{Id: "not-a-uuid"})
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func TestMergeAuthorHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+
+	target := registerAuthor(t, client, "Ursula K Le Guin")
+	source := registerAuthor(t, client, "Ursula Le Guin")
+	book := addBook(t, client, "The Dispossessed", source)
+
+	duplicates, err := client.FindDuplicateAuthors(ctx, &generated.FindDuplicateAuthorsRequest{MinSimilarity: 0.8})
+	require.NoError(t, err)
+	require.Len(t, duplicates.GetDuplicates(), 1)
+
+	merged, err := client.MergeAuthors(ctx, &generated.MergeAuthorsRequest{TargetId: target, SourceIds: []string{source}})
+	require.NoError(t, err)
+	require.Equal(t, target, merged.GetAuthor().GetId())
+
+	info, err := client.GetBookInfo(ctx, &generated.GetBookInfoRequest{Id: book.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, []string{target}, info.GetBook().GetAuthorId())
+
+	_, err = client.MergeAuthors(ctx, &generated.MergeAuthorsRequest{TargetId: target, SourceIds: []string{source}})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestBookHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+
+	author := registerAuthor(t, client, "Frank Herbert")
+	translator := registerAuthor(t, client, "Michel Demuth")
+
+	added, err := client.AddBook(ctx, &generated.AddBookRequest{
+		Name:            "Dune",
+		AuthorIds:       []string{author},
+		Isbn:            "978-0-441-01359-3",
+		Publisher:       "Chilton",
+		PublicationYear: 1965,
+		Tags:            []string{"science fiction"},
+	})
+	require.NoError(t, err)
+
+	book := added.GetBook()
+	require.Equal(t, "9780441013593", book.GetIsbn())
+
+	// A duplicate names the existing book.
+	_, err = client.AddBook(ctx, &generated.AddBookRequest{Name: "DUNE", AuthorIds: []string{author}})
+	requireCode(t, codes.AlreadyExists, err)
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	require.Equal(t, book.GetId(), details[0].(*errdetails.ResourceInfo).GetResourceName())
+
+	_, err = client.AddBook(ctx, &generated.AddBookRequest{
+		Name:      "Dune",
+		AuthorIds: []string{author},
+		Contributors: []*generated.Contributor{
+			{AuthorId: author, Role: generated.ContributorRole_CONTRIBUTOR_ROLE_AUTHOR},
+		},
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = client.AddBook(ctx, &generated.AddBookRequest{Name: "Dune", AuthorIds: []string{translator[:8]}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	translated, err := client.AddBook(ctx, &generated.AddBookRequest{
+		Name: "Dune (French)",
+		Contributors: []*generated.Contributor{
+			{AuthorId: author, Role: generated.ContributorRole_CONTRIBUTOR_ROLE_AUTHOR},
+			{AuthorId: translator, Role: generated.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, translated.GetBook().GetContributors(), 2)
+
+	byISBN, err := client.GetBookByIsbn(ctx, &generated.GetBookByIsbnRequest{Isbn: "0441013597"})
+	require.NoError(t, err)
+	require.Equal(t, book.GetId(), byISBN.GetBook().GetId())
+
+	_, err = client.GetBookByIsbn(ctx, &generated.GetBookByIsbnRequest{Isbn: "0-306-40615-2"})
+	requireCode(t, codes.NotFound, err)
+
+	librarian := metadata.AppendToOutgoingContext(ctx, ActorMetadataKey, "librarian")
+	updated, err := client.UpdateBook(librarian, &generated.UpdateBookRequest{
+		Id:              book.GetId(),
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"page_count"}},
+		PageCount:       412,
+		ExpectedVersion: &book.Version,
+	})
+	require.NoError(t, err)
+
+	_, err = client.UpdateBook(ctx, &generated.UpdateBookRequest{
+		Id:              book.GetId(),
+		AddAuthorIds:    []string{translator},
+		ExpectedVersion: &book.Version,
+	})
+	requireCode(t, codes.Aborted, err)
+	require.True(t, IsVersionMismatch(err))
+
+	_, err = client.UpdateBook(ctx, &generated.UpdateBookRequest{Id: book.GetId(), UpdateMask: &fieldmaskpb.FieldMask{
+		Paths: []string{"title"},
+	}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	info, err := client.GetBookInfo(ctx, &generated.GetBookInfoRequest{Id: book.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, updated.GetVersion(), info.GetBook().GetVersion())
+	require.Equal(t, int32(412), info.GetBook().GetPageCount())
+	require.Equal(t, "Chilton", info.GetBook().GetPublisher())
+	require.NotNil(t, info.GetAvailability())
+
+	past, err := client.GetBookInfo(ctx, &generated.GetBookInfoRequest{
+		Id:   book.GetId(),
+		AsOf: timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, updated.GetVersion(), past.GetBook().GetVersion())
+	require.Nil(t, past.GetAvailability())
+
+	_, err = client.GetBookInfo(ctx, &generated.GetBookInfoRequest{
+		Id:   book.GetId(),
+		AsOf: &timestamppb.Timestamp{Nanos: -1},
+	})
+	requireCode(t, codes.InvalidArgument, err)
+
+	history, err := client.GetBookHistory(ctx, &generated.GetBookHistoryRequest{Id: book.GetId()})
+	require.NoError(t, err)
+	require.Len(t, history.GetRecords(), 2)
+	require.Equal(t, "librarian", history.GetRecords()[1].GetActor())
+
+	stream, err := client.GetAuthorBooks(ctx, &generated.GetAuthorBooksRequest{AuthorId: author})
+	require.NoError(t, err)
+
+	books, err := receiveAll(t, stream)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+
+	stream, err = client.GetAuthorBooks(ctx, &generated.GetAuthorBooksRequest{
+		AuthorId: translator,
+		Role:     generated.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR,
+	})
+	require.NoError(t, err)
+
+	books, err = receiveAll(t, stream)
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+
+	stream, err = client.GetAuthorBooks(ctx, &generated.GetAuthorBooksRequest{AuthorId: "author"})
+	require.NoError(t, err)
+
+	_, err = receiveAll(t, stream)
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func TestListBookHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+
+	fiction, err := client.CreateSubject(ctx, &generated.CreateSubjectRequest{Name: "Fiction"})
+	require.NoError(t, err)
+
+	scienceFiction, err := client.CreateSubject(ctx, &generated.CreateSubjectRequest{
+		Name:     "Science fiction",
+		ParentId: fiction.GetSubject().GetId(),
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateSubject(ctx, &generated.CreateSubjectRequest{
+		Name:     "Science fiction",
+		ParentId: fiction.GetSubject().GetId(),
+	})
+	requireCode(t, codes.AlreadyExists, err)
+
+	author := registerAuthor(t, client, "Frank Herbert")
+
+	for _, name := range []string{"Dune", "Dune Messiah", "Children of Dune"} {
+		_, err = client.AddBook(ctx, &generated.AddBookRequest{
+			Name:       name,
+			AuthorIds:  []string{author},
+			SubjectIds: []string{scienceFiction.GetSubject().GetId()},
+			Tags:       []string{"dune"},
+		})
+		require.NoError(t, err)
+	}
+
+	page, err := client.ListBooks(ctx, &generated.ListBooksRequest{PageSize: 2, SubjectId: fiction.GetSubject().GetId()})
+	require.NoError(t, err)
+	require.Len(t, page.GetBooks(), 2)
+
+	page, err = client.ListBooks(ctx, &generated.ListBooksRequest{PageToken: page.GetNextPageToken(), Tag: "dune"})
+	require.NoError(t, err)
+	require.Len(t, page.GetBooks(), 1)
+	require.Empty(t, page.GetNextPageToken())
+
+	page, err = client.ListBooks(ctx, &generated.ListBooksRequest{Title: "children of dune."})
+	require.NoError(t, err)
+	require.Len(t, page.GetBooks(), 1)
+	require.Equal(t, "Children of Dune", page.GetBooks()[0].GetName())
+
+	_, err = client.ListBooks(ctx, &generated.ListBooksRequest{Title: "—"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	tags, err := client.ListTags(ctx, &generated.ListTagsRequest{SubjectId: fiction.GetSubject().GetId()})
+	require.NoError(t, err)
+	require.Len(t, tags.GetTags(), 1)
+	require.Equal(t, int32(3), tags.GetTags()[0].GetCount())
+
+	tags, err = client.ListTags(ctx, &generated.ListTagsRequest{Title: "Dune Messiah"})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), tags.GetTags()[0].GetCount())
+
+	subjects, err := client.ListSubjects(ctx, &generated.ListSubjectsRequest{RootId: fiction.GetSubject().GetId()})
+	require.NoError(t, err)
+	require.Len(t, subjects.GetSubjects(), 2)
+
+	renamed, err := client.UpdateSubject(ctx, &generated.UpdateSubjectRequest{
+		Id:         scienceFiction.GetSubject().GetId(),
+		Name:       "SF",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, fiction.GetSubject().GetId(), renamed.GetSubject().GetParentId())
+
+	_, err = client.UpdateSubject(ctx, &generated.UpdateSubjectRequest{
+		Id:       fiction.GetSubject().GetId(),
+		Name:     "Fiction",
+		ParentId: scienceFiction.GetSubject().GetId(),
+	})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	_, err = client.DeleteSubject(ctx, &generated.DeleteSubjectRequest{Id: fiction.GetSubject().GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	empty, err := client.CreateSubject(ctx, &generated.CreateSubjectRequest{Name: "Poetry"})
+	require.NoError(t, err)
+
+	_, err = client.DeleteSubject(ctx, &generated.DeleteSubjectRequest{Id: empty.GetSubject().GetId()})
+	require.NoError(t, err)
+}
+
+func TestWorkAndSeriesHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	author := registerAuthor(t, client, "Frank Herbert")
+
+	work, err := client.CreateWork(ctx, &generated.CreateWorkRequest{Title: "Dune"})
+	require.NoError(t, err)
+
+	series, err := client.CreateSeries(ctx, &generated.CreateSeriesRequest{Name: "Dune Chronicles"})
+	require.NoError(t, err)
+
+	// Editions of a work share its title, so they are added as duplicates.
+	for position := range 2 {
+		_, err = client.AddBook(ctx, &generated.AddBookRequest{
+			Name:           "Dune",
+			AuthorIds:      []string{author},
+			WorkId:         work.GetWork().GetId(),
+			SeriesId:       series.GetSeries().GetId(),
+			SeriesPosition: int32(position + 1),
+			AllowDuplicate: true,
+		})
+		require.NoError(t, err)
+	}
+
+	editions, err := client.GetWorkEditions(ctx, &generated.GetWorkEditionsRequest{Id: work.GetWork().GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Dune", editions.GetWork().GetTitle())
+	require.Len(t, editions.GetEditions(), 2)
+
+	books, err := client.GetSeriesBooks(ctx, &generated.GetSeriesBooksRequest{Id: series.GetSeries().GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "Dune Chronicles", books.GetSeries().GetName())
+	require.Len(t, books.GetBooks(), 2)
+
+	_, err = client.GetWorkEditions(ctx, &generated.GetWorkEditionsRequest{Id: author})
+	requireCode(t, codes.NotFound, err)
+
+	_, err = client.GetSeriesBooks(ctx, &generated.GetSeriesBooksRequest{Id: author})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestUploadCoverHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	book := addBook(t, client, "Dune", registerAuthor(t, client, "Frank Herbert"))
+
+	var image bytes.Buffer
+	require.NoError(t, png.Encode(&image, newTestImage()))
+
+	upload := func(messages ...*generated.UploadCoverRequest) (*generated.UploadCoverResponse, error) {
+		stream, err := client.UploadCover(ctx)
+		require.NoError(t, err)
+
+		for _, message := range messages {
+			require.NoError(t, stream.Send(message))
+		}
+
+		return stream.CloseAndRecv()
+	}
+
+	info := &generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Info{
+		Info: &generated.CoverInfo{BookId: book.GetId(), ContentType: "image/png"},
+	}}
+	data := image.Bytes()
+
+	resp, err := upload(info,
+		&generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Chunk{Chunk: data[:len(data)/2]}},
+		&generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Chunk{Chunk: data[len(data)/2:]}})
+	require.NoError(t, err)
+	require.NotNil(t, resp.GetBook().GetCover())
+
+	_, err = upload(&generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Chunk{Chunk: data}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = upload(info, info)
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = upload(info, &generated.UploadCoverRequest{Data: &generated.UploadCoverRequest_Chunk{Chunk: []byte("text")}})
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func newTestImage() image.Image {
+	return image.NewRGBA(image.Rect(0, 0, 4, 6))
+}
+
+func TestWatchCatalogHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newTestClients(t).library
+	author := registerAuthor(t, client, "Frank Herbert")
+	addBook(t, client, "Dune", author)
+
+	stream, err := client.WatchCatalog(ctx, &generated.WatchCatalogRequest{AuthorId: author, Since: "0"})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, generated.CatalogEvent_KIND_CREATED, first.GetKind())
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	require.NotEqual(t, first.GetCursor(), second.GetCursor())
+
+	cancel()
+
+	_, err = stream.Recv()
+	requireCode(t, codes.Canceled, err)
+
+	stream, err = client.WatchCatalog(context.Background(), &generated.WatchCatalogRequest{Since: "x"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	requireCode(t, codes.InvalidArgument, err)
+}
@@ -1 +1,44 @@
 package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ChangeAuthorInfo(
+	ctx context.Context,
+	req *generated.ChangeAuthorInfoRequest,
+) (*generated.ChangeAuthorInfoResponse, error) {
+	i.logger.Info("received ChangeAuthorInfo request",
+		zap.String("id", req.GetId()),
+		zap.String("name", req.GetName()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := authorPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(ctx, req.ExpectedVersion)
+
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.ChangeAuthorInfo(ctx, req.GetId(), patch, version)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.ChangeAuthorInfoResponse{
+		Version: author.Version,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CheckoutCopy(
+	ctx context.Context,
+	req *generated.CheckoutCopyRequest,
+) (*generated.CheckoutCopyResponse, error) {
+	i.logger.Info("received CheckoutCopy request",
+		zap.String("copy_id", req.GetCopyId()),
+		zap.String("patron_id", req.GetPatronId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	loan, err := i.loansUseCase.CheckoutCopy(ctx, req.GetCopyId(), req.GetPatronId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CheckoutCopyResponse{
+		Loan: toProtoLoan(loan),
+	}, nil
+}
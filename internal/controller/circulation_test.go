@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func registerPatron(t *testing.T, client generated.LibraryClient, cardNumber string) *generated.Patron {
+	t.Helper()
+
+	resp, err := client.RegisterPatron(context.Background(), &generated.RegisterPatronRequest{
+		CardNumber:     cardNumber,
+		Name:           "Ada Lovelace",
+		Email:          "ada@example.com",
+		MembershipType: generated.MembershipType_MEMBERSHIP_TYPE_STANDARD,
+	})
+	require.NoError(t, err)
+
+	return resp.GetPatron()
+}
+
+func addCopy(t *testing.T, client generated.LibraryClient, bookID string, barcode string) *generated.Copy {
+	t.Helper()
+
+	resp, err := client.AddCopy(context.Background(), &generated.AddCopyRequest{
+		BookId:    bookID,
+		Barcode:   barcode,
+		Branch:    "Main",
+		Condition: generated.CopyCondition_COPY_CONDITION_GOOD,
+	})
+	require.NoError(t, err)
+
+	return resp.GetCopy()
+}
+
+func TestPatronHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	patron := registerPatron(t, client, "0001")
+
+	_, err := client.RegisterPatron(ctx, &generated.RegisterPatronRequest{CardNumber: "0001", Name: "Other"})
+	requireCode(t, codes.AlreadyExists, err)
+
+	got, err := client.GetPatron(ctx, &generated.GetPatronRequest{Id: patron.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, "ada@example.com", got.GetPatron().GetEmail())
+	require.Equal(t, generated.PatronStatus_PATRON_STATUS_ACTIVE, got.GetPatron().GetStatus())
+
+	updated, err := client.UpdatePatron(ctx, &generated.UpdatePatronRequest{
+		Id:         patron.GetId(),
+		Phone:      "+1 555 0100",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"phone"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "+1 555 0100", updated.GetPatron().GetPhone())
+	require.Equal(t, "ada@example.com", updated.GetPatron().GetEmail())
+
+	_, err = client.UpdatePatron(ctx, &generated.UpdatePatronRequest{Id: patron.GetId(), Name: "Ada"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	suspended, err := client.SuspendPatron(ctx, &generated.SuspendPatronRequest{Id: patron.GetId(), Reason: "lost card"})
+	require.NoError(t, err)
+	require.Equal(t, generated.PatronStatus_PATRON_STATUS_SUSPENDED, suspended.GetPatron().GetStatus())
+
+	_, err = client.SuspendPatron(ctx, &generated.SuspendPatronRequest{Id: patron.GetId(), Reason: "lost card"})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	registerPatron(t, client, "0002")
+
+	page, err := client.ListPatrons(ctx, &generated.ListPatronsRequest{
+		Status: generated.PatronStatus_PATRON_STATUS_SUSPENDED,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.GetPatrons(), 1)
+
+	reinstated, err := client.ReinstatePatron(ctx, &generated.ReinstatePatronRequest{Id: patron.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, generated.PatronStatus_PATRON_STATUS_ACTIVE, reinstated.GetPatron().GetStatus())
+
+	page, err = client.ListPatrons(ctx, &generated.ListPatronsRequest{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, page.GetPatrons(), 1)
+
+	page, err = client.ListPatrons(ctx, &generated.ListPatronsRequest{PageToken: page.GetNextPageToken()})
+	require.NoError(t, err)
+	require.Len(t, page.GetPatrons(), 1)
+	require.Empty(t, page.GetNextPageToken())
+
+	_, err = client.ListPatrons(ctx, &generated.ListPatronsRequest{PageToken: "!"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = client.GetPatron(ctx, &generated.GetPatronRequest{Id: uuid.NewString()})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestCopyHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	book := addBook(t, client, "Dune", registerAuthor(t, client, "Frank Herbert"))
+	bookCopy := addCopy(t, client, book.GetId(), "0001")
+
+	_, err := client.AddCopy(ctx, &generated.AddCopyRequest{BookId: book.GetId(), Barcode: "0001"})
+	requireCode(t, codes.AlreadyExists, err)
+
+	got, err := client.GetCopy(ctx, &generated.GetCopyRequest{Id: bookCopy.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, generated.CopyStatus_COPY_STATUS_AVAILABLE, got.GetCopy().GetStatus())
+
+	updated, err := client.UpdateCopy(ctx, &generated.UpdateCopyRequest{
+		Id:         bookCopy.GetId(),
+		Location:   "Shelf 4",
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"location"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Shelf 4", updated.GetCopy().GetLocation())
+	require.Equal(t, "Main", updated.GetCopy().GetBranch())
+
+	_, err = client.UpdateCopy(ctx, &generated.UpdateCopyRequest{Id: bookCopy.GetId()})
+	requireCode(t, codes.InvalidArgument, err)
+
+	addCopy(t, client, book.GetId(), "0002")
+
+	copies, err := client.ListBookCopies(ctx, &generated.ListBookCopiesRequest{BookId: book.GetId()})
+	require.NoError(t, err)
+	require.Len(t, copies.GetCopies(), 2)
+
+	_, err = client.DeleteCopy(ctx, &generated.DeleteCopyRequest{Id: bookCopy.GetId()})
+	require.NoError(t, err)
+
+	_, err = client.GetCopy(ctx, &generated.GetCopyRequest{Id: bookCopy.GetId()})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestLoanAndHoldHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	book := addBook(t, client, "Dune", registerAuthor(t, client, "Frank Herbert"))
+	bookCopy := addCopy(t, client, book.GetId(), "0001")
+	borrower := registerPatron(t, client, "0001")
+	waiting := registerPatron(t, client, "0002")
+
+	loan, err := client.CheckoutCopy(ctx, &generated.CheckoutCopyRequest{
+		CopyId:   bookCopy.GetId(),
+		PatronId: borrower.GetId(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, bookCopy.GetId(), loan.GetLoan().GetCopyId())
+
+	_, err = client.CheckoutCopy(ctx, &generated.CheckoutCopyRequest{CopyId: bookCopy.GetId(), PatronId: waiting.GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	// A copy on loan can not be deleted.
+	_, err = client.DeleteCopy(ctx, &generated.DeleteCopyRequest{Id: bookCopy.GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	renewed, err := client.RenewLoan(ctx, &generated.RenewLoanRequest{Id: loan.GetLoan().GetId()})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), renewed.GetLoan().GetRenewals())
+
+	hold, err := client.PlaceHold(ctx, &generated.PlaceHoldRequest{BookId: book.GetId(), PatronId: waiting.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, generated.HoldStatus_HOLD_STATUS_WAITING, hold.GetHold().GetStatus())
+
+	// The renewal is refused while another patron waits for the book.
+	_, err = client.RenewLoan(ctx, &generated.RenewLoanRequest{Id: loan.GetLoan().GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	returned, err := client.ReturnCopy(ctx, &generated.ReturnCopyRequest{CopyId: bookCopy.GetId()})
+	require.NoError(t, err)
+	require.NotNil(t, returned.GetLoan().GetReturnedAt())
+
+	holds, err := client.ListHolds(ctx, &generated.ListHoldsRequest{PatronId: waiting.GetId()})
+	require.NoError(t, err)
+	require.Len(t, holds.GetHolds(), 1)
+	require.Equal(t, generated.HoldStatus_HOLD_STATUS_READY, holds.GetHolds()[0].GetStatus())
+	require.Equal(t, bookCopy.GetId(), holds.GetHolds()[0].GetCopyId())
+
+	cancelled, err := client.CancelHold(ctx, &generated.CancelHoldRequest{Id: hold.GetHold().GetId()})
+	require.NoError(t, err)
+	require.Equal(t, generated.HoldStatus_HOLD_STATUS_CANCELLED, cancelled.GetHold().GetStatus())
+
+	holds, err = client.ListHolds(ctx, &generated.ListHoldsRequest{BookId: book.GetId(), IncludeClosed: true})
+	require.NoError(t, err)
+	require.Len(t, holds.GetHolds(), 1)
+
+	_, err = client.ReturnCopy(ctx, &generated.ReturnCopyRequest{CopyId: bookCopy.GetId()})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	_, err = client.ListHolds(ctx, &generated.ListHoldsRequest{})
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func TestFineHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	patron := registerPatron(t, client, "0001")
+
+	balance, err := client.GetPatronBalance(ctx, &generated.GetPatronBalanceRequest{PatronId: patron.GetId()})
+	require.NoError(t, err)
+	require.Zero(t, balance.GetBalanceCents())
+
+	_, err = client.PayFine(ctx, &generated.PayFineRequest{PatronId: patron.GetId(), AmountCents: 100})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	_, err = client.WaiveFine(ctx, &generated.WaiveFineRequest{PatronId: patron.GetId(), AmountCents: 100, Reason: "r"})
+	requireCode(t, codes.FailedPrecondition, err)
+
+	_, err = client.PayFine(ctx, &generated.PayFineRequest{PatronId: patron.GetId()})
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+func TestReviewHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newTestClients(t).library
+	book := addBook(t, client, "Dune", registerAuthor(t, client, "Frank Herbert"))
+	patron := registerPatron(t, client, "0001")
+
+	review, err := client.CreateReview(ctx, &generated.CreateReviewRequest{
+		BookId:   book.GetId(),
+		PatronId: patron.GetId(),
+		Rating:   4,
+		Text:     "Dense.",
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateReview(ctx, &generated.CreateReviewRequest{
+		BookId:   book.GetId(),
+		PatronId: patron.GetId(),
+		Rating:   5,
+	})
+	requireCode(t, codes.AlreadyExists, err)
+
+	updated, err := client.UpdateReview(ctx, &generated.UpdateReviewRequest{
+		Id:         review.GetReview().GetId(),
+		Rating:     5,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rating"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(5), updated.GetReview().GetRating())
+	require.Equal(t, "Dense.", updated.GetReview().GetText())
+
+	info, err := client.GetBookInfo(ctx, &generated.GetBookInfoRequest{Id: book.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), info.GetRating().GetCount())
+
+	reviews, err := client.ListReviews(ctx, &generated.ListReviewsRequest{BookId: book.GetId(), PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, reviews.GetReviews(), 1)
+
+	reviews, err = client.ListReviews(ctx, &generated.ListReviewsRequest{PatronId: patron.GetId()})
+	require.NoError(t, err)
+	require.Len(t, reviews.GetReviews(), 1)
+
+	_, err = client.DeleteReview(ctx, &generated.DeleteReviewRequest{Id: review.GetReview().GetId()})
+	require.NoError(t, err)
+
+	_, err = client.UpdateReview(ctx, &generated.UpdateReviewRequest{Id: review.GetReview().GetId(), Rating: 3})
+	requireCode(t, codes.NotFound, err)
+}
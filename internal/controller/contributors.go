@@ -0,0 +1,81 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bookCredits returns the contributors of a request, which credits a book
+// either with author_ids or with contributors.
+func bookCredits(authorIDs []string, contributors []*generated.Contributor) ([]entity.Contributor, error) {
+	if len(authorIDs) > 0 && len(contributors) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "author_ids and contributors can not be combined")
+	}
+
+	if len(contributors) > 0 {
+		return toContributors(contributors), nil
+	}
+
+	return entity.AuthorContributors(authorIDs), nil
+}
+
+func toContributors(contributors []*generated.Contributor) []entity.Contributor {
+	converted := make([]entity.Contributor, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		converted = append(converted, entity.Contributor{
+			AuthorID: contributor.GetAuthorId(),
+			Role:     toContributorRole(contributor.GetRole()),
+		})
+	}
+
+	return converted
+}
+
+func toProtoContributors(contributors []entity.Contributor) []*generated.Contributor {
+	converted := make([]*generated.Contributor, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		converted = append(converted, &generated.Contributor{
+			AuthorId: contributor.AuthorID,
+			Role:     toProtoContributorRole(contributor.Role),
+			Position: int32(contributor.Position),
+		})
+	}
+
+	return converted
+}
+
+// toContributorRole maps the unspecified role to an empty one, which is
+// credited as author and matches any role in filters.
+func toContributorRole(role generated.ContributorRole) entity.ContributorRole {
+	switch role {
+	case generated.ContributorRole_CONTRIBUTOR_ROLE_AUTHOR:
+		return entity.ContributorRoleAuthor
+	case generated.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR:
+		return entity.ContributorRoleTranslator
+	case generated.ContributorRole_CONTRIBUTOR_ROLE_EDITOR:
+		return entity.ContributorRoleEditor
+	case generated.ContributorRole_CONTRIBUTOR_ROLE_ILLUSTRATOR:
+		return entity.ContributorRoleIllustrator
+	default:
+		return ""
+	}
+}
+
+func toProtoContributorRole(role entity.ContributorRole) generated.ContributorRole {
+	switch role {
+	case entity.ContributorRoleAuthor:
+		return generated.ContributorRole_CONTRIBUTOR_ROLE_AUTHOR
+	case entity.ContributorRoleTranslator:
+		return generated.ContributorRole_CONTRIBUTOR_ROLE_TRANSLATOR
+	case entity.ContributorRoleEditor:
+		return generated.ContributorRole_CONTRIBUTOR_ROLE_EDITOR
+	case entity.ContributorRoleIllustrator:
+		return generated.ContributorRole_CONTRIBUTOR_ROLE_ILLUSTRATOR
+	default:
+		return generated.ContributorRole_CONTRIBUTOR_ROLE_UNSPECIFIED
+	}
+}
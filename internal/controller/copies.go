@@ -0,0 +1,79 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoCopy(bookCopy entity.Copy) *generated.Copy {
+	return &generated.Copy{
+		Id:         bookCopy.ID,
+		BookId:     bookCopy.BookID,
+		Barcode:    bookCopy.Barcode,
+		Branch:     bookCopy.Branch,
+		Location:   bookCopy.Location,
+		Condition:  toProtoCopyCondition(bookCopy.Condition),
+		AcquiredOn: bookCopy.AcquiredOn,
+		Status:     toProtoCopyStatus(bookCopy.Status),
+		CreatedAt:  timestamppb.New(bookCopy.CreatedAt),
+		UpdatedAt:  timestamppb.New(bookCopy.UpdatedAt),
+	}
+}
+
+func toProtoAvailability(availability entity.Availability) *generated.Availability {
+	return &generated.Availability{
+		Total:     int32(availability.Total),
+		Available: int32(availability.Available),
+		OnLoan:    int32(availability.OnLoan),
+		OnHold:    int32(availability.OnHold),
+		Lost:      int32(availability.Lost),
+		Withdrawn: int32(availability.Withdrawn),
+	}
+}
+
+var copyStatuses = map[generated.CopyStatus]entity.CopyStatus{
+	generated.CopyStatus_COPY_STATUS_AVAILABLE: entity.CopyStatusAvailable,
+	generated.CopyStatus_COPY_STATUS_ON_LOAN:   entity.CopyStatusOnLoan,
+	generated.CopyStatus_COPY_STATUS_ON_HOLD:   entity.CopyStatusOnHold,
+	generated.CopyStatus_COPY_STATUS_LOST:      entity.CopyStatusLost,
+	generated.CopyStatus_COPY_STATUS_WITHDRAWN: entity.CopyStatusWithdrawn,
+}
+
+var copyConditions = map[generated.CopyCondition]entity.CopyCondition{
+	generated.CopyCondition_COPY_CONDITION_NEW:     entity.CopyConditionNew,
+	generated.CopyCondition_COPY_CONDITION_GOOD:    entity.CopyConditionGood,
+	generated.CopyCondition_COPY_CONDITION_FAIR:    entity.CopyConditionFair,
+	generated.CopyCondition_COPY_CONDITION_POOR:    entity.CopyConditionPoor,
+	generated.CopyCondition_COPY_CONDITION_DAMAGED: entity.CopyConditionDamaged,
+}
+
+// toCopyStatus maps the unspecified status to an empty one.
+func toCopyStatus(status generated.CopyStatus) entity.CopyStatus {
+	return copyStatuses[status]
+}
+
+func toProtoCopyStatus(status entity.CopyStatus) generated.CopyStatus {
+	for converted, value := range copyStatuses {
+		if value == status {
+			return converted
+		}
+	}
+
+	return generated.CopyStatus_COPY_STATUS_UNSPECIFIED
+}
+
+// toCopyCondition maps the unspecified condition to an empty one.
+func toCopyCondition(condition generated.CopyCondition) entity.CopyCondition {
+	return copyConditions[condition]
+}
+
+func toProtoCopyCondition(condition entity.CopyCondition) generated.CopyCondition {
+	for converted, value := range copyConditions {
+		if value == condition {
+			return converted
+		}
+	}
+
+	return generated.CopyCondition_COPY_CONDITION_UNSPECIFIED
+}
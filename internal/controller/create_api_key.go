@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CreateApiKey(
+	ctx context.Context,
+	req *generated.CreateApiKeyRequest,
+) (*generated.CreateApiKeyResponse, error) {
+	i.logger.Info("received CreateApiKey request",
+		zap.String("name", req.GetName()),
+		zap.Strings("scopes", req.GetScopes()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	key, secret, err := i.apiKeysUseCase.Create(ctx, req.GetName(), req.GetScopes(), req.GetExpiresAt().AsTime())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CreateApiKeyResponse{
+		ApiKey: toProtoAPIKey(key),
+		Secret: secret,
+	}, nil
+}
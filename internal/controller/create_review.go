@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CreateReview(
+	ctx context.Context,
+	req *generated.CreateReviewRequest,
+) (*generated.CreateReviewResponse, error) {
+	i.logger.Info("received CreateReview request",
+		zap.String("book_id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()),
+		zap.Int32("rating", req.GetRating()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	review, err := i.reviewsUseCase.CreateReview(ctx, entity.Review{
+		BookID:   req.GetBookId(),
+		PatronID: req.GetPatronId(),
+		Rating:   int(req.GetRating()),
+		Text:     req.GetText(),
+	})
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CreateReviewResponse{
+		Review: toProtoReview(review),
+	}, nil
+}
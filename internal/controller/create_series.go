@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CreateSeries(
+	ctx context.Context,
+	req *generated.CreateSeriesRequest,
+) (*generated.CreateSeriesResponse, error) {
+	i.logger.Info("received CreateSeries request", zap.String("name", req.GetName()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	series, err := i.worksUseCase.CreateSeries(ctx, req.GetName())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CreateSeriesResponse{
+		Series: toProtoSeries(series),
+	}, nil
+}
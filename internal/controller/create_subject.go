@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CreateSubject(
+	ctx context.Context,
+	req *generated.CreateSubjectRequest,
+) (*generated.CreateSubjectResponse, error) {
+	i.logger.Info("received CreateSubject request",
+		zap.String("name", req.GetName()),
+		zap.String("parent_id", req.GetParentId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	subject, err := i.subjectsUseCase.CreateSubject(ctx, req.GetName(), req.GetParentId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CreateSubjectResponse{
+		Subject: toProtoSubject(subject),
+	}, nil
+}
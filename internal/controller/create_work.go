@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) CreateWork(
+	ctx context.Context,
+	req *generated.CreateWorkRequest,
+) (*generated.CreateWorkResponse, error) {
+	i.logger.Info("received CreateWork request", zap.String("title", req.GetTitle()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	work, err := i.worksUseCase.CreateWork(ctx, req.GetTitle())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.CreateWorkResponse{
+		Work: toProtoWork(work),
+	}, nil
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) DeleteCopy(
+	ctx context.Context,
+	req *generated.DeleteCopyRequest,
+) (*generated.DeleteCopyResponse, error) {
+	i.logger.Info("received DeleteCopy request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	if err := i.copiesUseCase.DeleteCopy(ctx, req.GetId()); err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.DeleteCopyResponse{}, nil
+}
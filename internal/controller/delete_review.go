@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) DeleteReview(
+	ctx context.Context,
+	req *generated.DeleteReviewRequest,
+) (*generated.DeleteReviewResponse, error) {
+	i.logger.Info("received DeleteReview request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	if err := i.reviewsUseCase.DeleteReview(ctx, req.GetId()); err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.DeleteReviewResponse{}, nil
+}
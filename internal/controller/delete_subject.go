@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) DeleteSubject(
+	ctx context.Context,
+	req *generated.DeleteSubjectRequest,
+) (*generated.DeleteSubjectResponse, error) {
+	i.logger.Info("received DeleteSubject request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	if err := i.subjectsUseCase.DeleteSubject(ctx, req.GetId()); err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.DeleteSubjectResponse{}, nil
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// IfMatchMetadataKey carries the HTTP If-Match header of gateway requests.
+const IfMatchMetadataKey = "if-match"
+
+// versionViolation is the type of the PreconditionFailure violation a
+// version mismatch carries.
+const versionViolation = "VERSION"
+
+// FormatETag renders an entity version as a strong HTTP entity tag.
+func FormatETag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// expectedVersion returns the version a conditional update must match:
+// the request field when set, otherwise the If-Match header. It returns nil
+// for unconditional updates, including "If-Match: *".
+func expectedVersion(ctx context.Context, requested *uint64) (*uint64, error) {
+	values := metadata.ValueFromIncomingContext(ctx, IfMatchMetadataKey)
+
+	if requested != nil || len(values) == 0 || values[0] == "*" {
+		return requested, nil
+	}
+
+	unquoted, err := strconv.Unquote(values[0])
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed If-Match header %q", values[0])
+	}
+
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed If-Match header %q", values[0])
+	}
+
+	return &version, nil
+}
+
+// versionMismatchStatus is Aborted with a PreconditionFailure detail, which
+// tells a failed version check from the other aborted requests.
+func versionMismatchStatus(err error) error {
+	result := status.New(codes.Aborted, err.Error())
+	detailed, detailErr := result.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{
+			Type:        versionViolation,
+			Subject:     "expected_version",
+			Description: err.Error(),
+		}},
+	})
+
+	if detailErr != nil {
+		return result.Err()
+	}
+
+	return detailed.Err()
+}
+
+// IsVersionMismatch reports whether the error is a failed version check of
+// a conditional update.
+func IsVersionMismatch(err error) bool {
+	result, ok := status.FromError(err)
+
+	if !ok || result.Code() != codes.Aborted {
+		return false
+	}
+
+	for _, detail := range result.Details() {
+		if failure, isFailure := detail.(*errdetails.PreconditionFailure); isFailure {
+			for _, violation := range failure.GetViolations() {
+				if violation.GetType() == versionViolation {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
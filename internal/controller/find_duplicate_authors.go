@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMinSimilarity  = 0.6
+	defaultDuplicateLimit = 100
+)
+
+func (i *implementation) FindDuplicateAuthors(
+	ctx context.Context,
+	req *generated.FindDuplicateAuthorsRequest,
+) (*generated.FindDuplicateAuthorsResponse, error) {
+	i.logger.Info("received FindDuplicateAuthors request",
+		zap.Float64("min_similarity", req.GetMinSimilarity()),
+		zap.Int32("limit", req.GetLimit()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	minSimilarity := cmp.Or(req.GetMinSimilarity(), defaultMinSimilarity)
+	limit := cmp.Or(int(req.GetLimit()), defaultDuplicateLimit)
+	duplicates, err := i.authorUseCase.FindDuplicateAuthors(ctx, minSimilarity, limit)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	result := make([]*generated.AuthorDuplicate, 0, len(duplicates))
+
+	for _, duplicate := range duplicates {
+		result = append(result, &generated.AuthorDuplicate{
+			Author:     toProtoAuthor(duplicate.Author),
+			Duplicate:  toProtoAuthor(duplicate.Duplicate),
+			Similarity: duplicate.Similarity,
+		})
+	}
+
+	return &generated.FindDuplicateAuthorsResponse{
+		Duplicates: result,
+	}, nil
+}
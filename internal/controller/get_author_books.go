@@ -1 +1,40 @@
 package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetAuthorBooks(
+	req *generated.GetAuthorBooksRequest,
+	server generated.Library_GetAuthorBooksServer,
+) error {
+	i.logger.Info("received GetAuthorBooks request",
+		zap.String("author_id", req.GetAuthorId()),
+		zap.Stringer("role", req.GetRole()))
+
+	if err := validate(req); err != nil {
+		return err
+	}
+
+	moment, err := asOf(req.GetAsOf())
+
+	if err != nil {
+		return err
+	}
+
+	role := toContributorRole(req.GetRole())
+	books, err := i.authorUseCase.GetAuthorBooks(server.Context(), req.GetAuthorId(), role, moment)
+
+	if err != nil {
+		return i.convertErr(err)
+	}
+
+	for _, book := range books {
+		if err = server.Send(toProtoBook(book)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
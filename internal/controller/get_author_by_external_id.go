@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetAuthorByExternalId(
+	ctx context.Context,
+	req *generated.GetAuthorByExternalIdRequest,
+) (*generated.GetAuthorByExternalIdResponse, error) {
+	i.logger.Info("received GetAuthorByExternalId request",
+		zap.Stringer("scheme", req.GetScheme()),
+		zap.String("value", req.GetValue()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.GetAuthorByExternalID(ctx, toExternalIDScheme(req.GetScheme()), req.GetValue())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetAuthorByExternalIdResponse{
+		Author: toProtoAuthor(author),
+	}, nil
+}
+
+func toExternalIDScheme(scheme generated.GetAuthorByExternalIdRequest_Scheme) entity.ExternalIDScheme {
+	switch scheme {
+	case generated.GetAuthorByExternalIdRequest_SCHEME_VIAF:
+		return entity.ExternalIDSchemeVIAF
+	case generated.GetAuthorByExternalIdRequest_SCHEME_ISNI:
+		return entity.ExternalIDSchemeISNI
+	case generated.GetAuthorByExternalIdRequest_SCHEME_WIKIDATA:
+		return entity.ExternalIDSchemeWikidata
+	default:
+		return entity.ExternalIDSchemeUndefined
+	}
+}
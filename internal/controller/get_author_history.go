@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetAuthorHistory(
+	ctx context.Context,
+	req *generated.GetAuthorHistoryRequest,
+) (*generated.GetAuthorHistoryResponse, error) {
+	i.logger.Info("received GetAuthorHistory request",
+		zap.String("id", req.GetId()),
+		zap.Int32("page_size", req.GetPageSize()),
+		zap.String("page_token", req.GetPageToken()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	afterID, err := parsePageToken(req.GetPageToken())
+
+	if err != nil {
+		return nil, err
+	}
+
+	size := pageSize(req.GetPageSize())
+	records, err := i.historyUseCase.GetAuthorHistory(ctx, req.GetId(), afterID, size+1)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	page, token, err := toProtoAuditPage(records, size)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetAuthorHistoryResponse{
+		Records:       page,
+		NextPageToken: token,
+	}, nil
+}
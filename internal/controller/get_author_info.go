@@ -1 +1,43 @@
 package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetAuthorInfo(
+	ctx context.Context,
+	req *generated.GetAuthorInfoRequest,
+) (*generated.GetAuthorInfoResponse, error) {
+	i.logger.Info("received GetAuthorInfo request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	moment, err := asOf(req.GetAsOf())
+
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.GetAuthorInfo(ctx, req.GetId(), moment)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetAuthorInfoResponse{
+		Id:          author.ID,
+		Name:        author.Name,
+		Version:     author.Version,
+		Biography:   author.Biography,
+		BirthDate:   author.BirthDate,
+		DeathDate:   author.DeathDate,
+		Country:     author.Country,
+		Aliases:     author.Aliases,
+		ExternalIds: toProtoExternalIDs(author.ExternalIDs),
+	}, nil
+}
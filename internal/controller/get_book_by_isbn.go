@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetBookByIsbn(
+	ctx context.Context,
+	req *generated.GetBookByIsbnRequest,
+) (*generated.GetBookByIsbnResponse, error) {
+	i.logger.Info("received GetBookByIsbn request", zap.String("isbn", req.GetIsbn()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	book, err := i.booksUseCase.GetBookByISBN(ctx, req.GetIsbn())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetBookByIsbnResponse{
+		Book: toProtoBook(book),
+	}, nil
+}
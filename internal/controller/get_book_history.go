@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (i *implementation) GetBookHistory(
+	ctx context.Context,
+	req *generated.GetBookHistoryRequest,
+) (*generated.GetBookHistoryResponse, error) {
+	i.logger.Info("received GetBookHistory request",
+		zap.String("id", req.GetId()),
+		zap.Int32("page_size", req.GetPageSize()),
+		zap.String("page_token", req.GetPageToken()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	afterID, err := parsePageToken(req.GetPageToken())
+
+	if err != nil {
+		return nil, err
+	}
+
+	size := pageSize(req.GetPageSize())
+	records, err := i.historyUseCase.GetBookHistory(ctx, req.GetId(), afterID, size+1)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	page, token, err := toProtoAuditPage(records, size)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetBookHistoryResponse{
+		Records:       page,
+		NextPageToken: token,
+	}, nil
+}
+
+// toProtoAuditPage converts the first size records and builds the token of
+// the next page.
+func toProtoAuditPage(records []entity.AuditRecord, size int) ([]*generated.AuditRecord, string, error) {
+	page := make([]*generated.AuditRecord, 0, min(size, len(records)))
+
+	for _, record := range records[:min(size, len(records))] {
+		converted, err := toProtoAuditRecord(record)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		page = append(page, converted)
+	}
+
+	var lastID uint64
+
+	if len(page) > 0 {
+		lastID = records[len(page)-1].ID
+	}
+
+	return page, nextPageToken(len(records), size, lastID), nil
+}
+
+func toProtoAuditRecord(record entity.AuditRecord) (*generated.AuditRecord, error) {
+	result := &generated.AuditRecord{
+		Actor:     record.Actor,
+		Operation: string(record.Operation),
+		EntityId:  record.EntityID,
+		After:     new(structpb.Struct),
+		CreatedAt: timestamppb.New(record.CreatedAt),
+	}
+
+	if err := protojson.Unmarshal(record.After, result.After); err != nil {
+		return nil, err
+	}
+
+	if record.Before == nil {
+		return result, nil
+	}
+
+	result.Before = new(structpb.Struct)
+
+	return result, protojson.Unmarshal(record.Before, result.Before)
+}
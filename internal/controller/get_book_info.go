@@ -1 +1,64 @@
 package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetBookInfo(
+	ctx context.Context,
+	req *generated.GetBookInfoRequest,
+) (*generated.GetBookInfoResponse, error) {
+	i.logger.Info("received GetBookInfo request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	moment, err := asOf(req.GetAsOf())
+
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := i.booksUseCase.GetBookInfo(ctx, req.GetId(), moment)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	response := &generated.GetBookInfoResponse{
+		Book: toProtoBook(book),
+	}
+
+	// Copies and reviews have no history, so past versions come without
+	// availability and rating.
+	if moment == nil {
+		if err = i.addCurrentState(ctx, response, book.ID); err != nil {
+			return nil, i.convertErr(err)
+		}
+	}
+
+	return response, nil
+}
+
+func (i *implementation) addCurrentState(ctx context.Context, response *generated.GetBookInfoResponse, bookID string) error {
+	availability, err := i.copiesUseCase.GetBookAvailability(ctx, bookID)
+
+	if err != nil {
+		return err
+	}
+
+	rating, err := i.reviewsUseCase.GetBookRating(ctx, bookID)
+
+	if err != nil {
+		return err
+	}
+
+	response.Availability = toProtoAvailability(availability)
+	response.Rating = toProtoBookRating(rating)
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetCopy(ctx context.Context, req *generated.GetCopyRequest) (*generated.GetCopyResponse, error) {
+	i.logger.Info("received GetCopy request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	bookCopy, err := i.copiesUseCase.GetCopy(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetCopyResponse{
+		Copy: toProtoCopy(bookCopy),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetPatron(
+	ctx context.Context,
+	req *generated.GetPatronRequest,
+) (*generated.GetPatronResponse, error) {
+	i.logger.Info("received GetPatron request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patron, err := i.patronsUseCase.GetPatron(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetPatronResponse{
+		Patron: toProtoPatron(patron),
+	}, nil
+}
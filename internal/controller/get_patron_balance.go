@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetPatronBalance(
+	ctx context.Context,
+	req *generated.GetPatronBalanceRequest,
+) (*generated.GetPatronBalanceResponse, error) {
+	i.logger.Info("received GetPatronBalance request", zap.String("patron_id", req.GetPatronId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	balance, err := i.finesUseCase.GetPatronBalance(ctx, req.GetPatronId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetPatronBalanceResponse{
+		BalanceCents: balance,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetSeriesBooks(
+	ctx context.Context,
+	req *generated.GetSeriesBooksRequest,
+) (*generated.GetSeriesBooksResponse, error) {
+	i.logger.Info("received GetSeriesBooks request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	series, books, err := i.worksUseCase.GetSeriesBooks(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetSeriesBooksResponse{
+		Series: toProtoSeries(series),
+		Books:  toProtoBooks(books),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) GetWorkEditions(
+	ctx context.Context,
+	req *generated.GetWorkEditionsRequest,
+) (*generated.GetWorkEditionsResponse, error) {
+	i.logger.Info("received GetWorkEditions request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	work, editions, err := i.worksUseCase.GetWorkEditions(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.GetWorkEditionsResponse{
+		Work:     toProtoWork(work),
+		Editions: toProtoBooks(editions),
+	}, nil
+}
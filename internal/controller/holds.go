@@ -0,0 +1,40 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoHold(hold entity.Hold) *generated.Hold {
+	result := &generated.Hold{
+		Id:       hold.ID,
+		BookId:   hold.BookID,
+		PatronId: hold.PatronID,
+		Status:   toProtoHoldStatus(hold.Status),
+		CopyId:   hold.CopyID,
+		PlacedAt: timestamppb.New(hold.PlacedAt),
+	}
+
+	if hold.PickupBy != nil {
+		result.PickupBy = timestamppb.New(*hold.PickupBy)
+	}
+
+	if hold.ClosedAt != nil {
+		result.ClosedAt = timestamppb.New(*hold.ClosedAt)
+	}
+
+	return result
+}
+
+var holdStatuses = map[entity.HoldStatus]generated.HoldStatus{
+	entity.HoldStatusWaiting:   generated.HoldStatus_HOLD_STATUS_WAITING,
+	entity.HoldStatusReady:     generated.HoldStatus_HOLD_STATUS_READY,
+	entity.HoldStatusFulfilled: generated.HoldStatus_HOLD_STATUS_FULFILLED,
+	entity.HoldStatusCancelled: generated.HoldStatus_HOLD_STATUS_CANCELLED,
+	entity.HoldStatusExpired:   generated.HoldStatus_HOLD_STATUS_EXPIRED,
+}
+
+func toProtoHoldStatus(status entity.HoldStatus) generated.HoldStatus {
+	return holdStatuses[status]
+}
@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/idempotency"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// IdempotencyKeyMetadataKey carries the key a client retries a mutating
+// request with, the response to the first request is returned to the
+// retries.
+const IdempotencyKeyMetadataKey = "idempotency-key"
+
+const maxIdempotencyKeyLength = 255
+
+// secretMethods return credentials, which must not outlive the response in
+// the replay storage, so idempotency keys are refused for them.
+var secretMethods = map[string]bool{
+	generated.ApiKeys_CreateApiKey_FullMethodName: true,
+	generated.ApiKeys_RotateApiKey_FullMethodName: true,
+}
+
+// NewIdempotencyUnaryInterceptor replays the stored response to mutating
+// requests made with a key already used for the same request. A method is
+// mutating when its HTTP binding is not a GET. Failed requests are not
+// stored, so they can be retried with the same key. Methods returning
+// secrets reject keys.
+//
+// The requests with a key run with the deadline, which must be well below
+// the pending TTL of the keys: a retry takes the key over only once the
+// first request is over. A request whose response can not be stored is
+// not run again by the retries until the pending TTL lapses, after that a
+// retry runs it once more.
+func NewIdempotencyUnaryInterceptor(keys idempotency.Idempotency, deadline time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		values := metadata.ValueFromIncomingContext(ctx, IdempotencyKeyMetadataKey)
+		request, ok := req.(proto.Message)
+		method := mutatingMethod(info.FullMethod)
+
+		if len(values) == 0 || !ok || method == nil {
+			return handler(ctx, req)
+		}
+
+		if secretMethods[info.FullMethod] {
+			return nil, status.Error(codes.InvalidArgument, "idempotency keys are not accepted for methods returning secrets")
+		}
+
+		key := values[0]
+
+		if key == "" || len(key) > maxIdempotencyKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "idempotency key must have 1 to %d bytes",
+				maxIdempotencyKeyLength)
+		}
+
+		// Keys of different callers never collide, the length of the subject
+		// keeps the prefix unambiguous.
+		if principal, authenticated := entity.PrincipalFromContext(ctx); authenticated {
+			key = strconv.Itoa(len(principal.Subject)) + ":" + principal.Subject + ":" + key
+		}
+
+		fingerprint, err := requestFingerprint(info.FullMethod, request)
+
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		record, replay, err := keys.Begin(ctx, key, fingerprint)
+
+		if err != nil {
+			return nil, idempotencyError(err)
+		}
+
+		if replay {
+			return replayResponse(method, record.Response)
+		}
+
+		handlerCtx, cancel := context.WithTimeout(ctx, deadline)
+		defer cancel()
+
+		resp, err := handler(handlerCtx, req)
+
+		return completeIdempotent(ctx, keys, record, resp, err)
+	}
+}
+
+// completeIdempotent stores the response to the request holding the
+// reservation, or releases the key when the request has failed. The key of
+// a request that has taken effect is never released: when its response can
+// not be stored the key stays reserved and the client still gets the
+// response, so that it has no reason to retry.
+func completeIdempotent(
+	ctx context.Context,
+	keys idempotency.Idempotency,
+	reservation entity.IdempotencyRecord,
+	resp any,
+	handlerErr error,
+) (any, error) {
+	// The outcome must be recorded even when the client has gone.
+	ctx = context.WithoutCancel(ctx)
+
+	if handlerErr != nil {
+		if err := keys.Release(ctx, reservation.Key, reservation.Token); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		return resp, handlerErr
+	}
+
+	response, ok := resp.(proto.Message)
+
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "unexpected response %T", resp)
+	}
+
+	// A response that can not be marshaled could not be sent either.
+	serialized, err := proto.MarshalOptions{Deterministic: true}.Marshal(response)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Complete logs its failure, the client gets the response either way.
+	_ = keys.Complete(ctx, reservation.Key, reservation.Token, serialized)
+
+	return resp, nil
+}
+
+// mutatingMethod returns the descriptor of the unary method named like
+// "/library.Library/AddBook" when its HTTP binding is not a GET.
+func mutatingMethod(fullMethod string) protoreflect.MethodDescriptor {
+	name := protoreflect.FullName(strings.Replace(strings.TrimPrefix(fullMethod, "/"), "/", ".", 1))
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+
+	if err != nil {
+		return nil
+	}
+
+	method, ok := descriptor.(protoreflect.MethodDescriptor)
+
+	if !ok || method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil
+	}
+
+	rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+
+	if !ok || rule == nil || rule.GetGet() != "" {
+		return nil
+	}
+
+	return method
+}
+
+// requestFingerprint hashes the method with the request, so a key reused
+// for another method does not match either.
+func requestFingerprint(fullMethod string, request proto.Message) ([]byte, error) {
+	serialized, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(fullMethod))
+	hash.Write([]byte{0})
+	hash.Write(serialized)
+
+	return hash.Sum(nil), nil
+}
+
+func replayResponse(method protoreflect.MethodDescriptor, stored []byte) (any, error) {
+	responseType, err := protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName())
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := responseType.New().Interface()
+
+	if err = proto.Unmarshal(stored, response); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return response, nil
+}
+
+func idempotencyError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrIdempotencyKeyReused):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entity.ErrIdempotencyKeyInUse):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/idempotency"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingIdempotencyRepository remembers every response stored for a key.
+type recordingIdempotencyRepository struct {
+	repository.IdempotencyRepository
+	stored [][]byte
+}
+
+func (r *recordingIdempotencyRepository) CompleteIdempotencyKey(
+	ctx context.Context,
+	key, token string,
+	response []byte,
+) error {
+	r.stored = append(r.stored, response)
+
+	return r.IdempotencyRepository.CompleteIdempotencyKey(ctx, key, token, response)
+}
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadataKey, key))
+}
+
+func TestIdempotencySecretMethods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method   string
+		request  any
+		response any
+	}{
+		{
+			method:   generated.ApiKeys_CreateApiKey_FullMethodName,
+			request:  &generated.CreateApiKeyRequest{Name: "ci"},
+			response: &generated.CreateApiKeyResponse{Secret: "id.secret"},
+		},
+		{
+			method:   generated.ApiKeys_RotateApiKey_FullMethodName,
+			request:  &generated.RotateApiKeyRequest{Id: "id"},
+			response: &generated.RotateApiKeyResponse{Secret: "id.secret"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &recordingIdempotencyRepository{IdempotencyRepository: repository.NewInMemoryRepository()}
+			interceptor := NewIdempotencyUnaryInterceptor(idempotency.New(zap.NewNop(), repo, time.Hour, time.Minute), time.Second)
+			info := &grpc.UnaryServerInfo{FullMethod: test.method}
+			calls := 0
+
+			handler := func(context.Context, any) (any, error) {
+				calls++
+				return test.response, nil
+			}
+
+			_, err := interceptor(withIdempotencyKey("key"), test.request, info, handler)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+			require.Zero(t, calls)
+
+			// Without a key the request is served and nothing is stored.
+			resp, err := interceptor(context.Background(), test.request, info, handler)
+			require.NoError(t, err)
+			require.Equal(t, test.response, resp)
+			require.Equal(t, 1, calls)
+			require.Empty(t, repo.stored)
+		})
+	}
+}
+
+// addBookHandler is a fake AddBook handler counting its calls, the first
+// failures calls fail.
+func addBookHandler(calls *int, failures int) grpc.UnaryHandler {
+	return func(context.Context, any) (any, error) {
+		*calls++
+
+		if *calls <= failures {
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		}
+
+		return &generated.AddBookResponse{Book: &generated.Book{Id: strconv.Itoa(*calls)}}, nil
+	}
+}
+
+func TestIdempotencyInterceptor(t *testing.T) {
+	t.Parallel()
+
+	dune := &generated.AddBookRequest{Name: "Dune"}
+	emma := &generated.AddBookRequest{Name: "Emma"}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		requests []any
+		failures int
+		codes    []codes.Code
+		calls    int
+		replayed bool
+	}{
+		{
+			name:     "replay",
+			ctx:      withIdempotencyKey("key"),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune, dune, dune},
+			codes:    []codes.Code{codes.OK, codes.OK, codes.OK},
+			calls:    1,
+			replayed: true,
+		},
+		{
+			name:     "key reused for another request",
+			ctx:      withIdempotencyKey("key"),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune, emma},
+			codes:    []codes.Code{codes.OK, codes.FailedPrecondition},
+			calls:    1,
+		},
+		{
+			name:     "failed request retried",
+			ctx:      withIdempotencyKey("key"),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune, dune, dune},
+			failures: 1,
+			codes:    []codes.Code{codes.Unavailable, codes.OK, codes.OK},
+			calls:    2,
+			replayed: true,
+		},
+		{
+			name:     "without a key",
+			ctx:      context.Background(),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune, dune},
+			codes:    []codes.Code{codes.OK, codes.OK},
+			calls:    2,
+		},
+		{
+			name:     "empty key",
+			ctx:      withIdempotencyKey(""),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune},
+			codes:    []codes.Code{codes.InvalidArgument},
+		},
+		{
+			name:     "long key",
+			ctx:      withIdempotencyKey(strings.Repeat("k", maxIdempotencyKeyLength+1)),
+			method:   generated.Library_AddBook_FullMethodName,
+			requests: []any{dune},
+			codes:    []codes.Code{codes.InvalidArgument},
+		},
+		{
+			name:     "read method",
+			ctx:      withIdempotencyKey("key"),
+			method:   generated.Library_GetBookInfo_FullMethodName,
+			requests: []any{&generated.GetBookInfoRequest{Id: "id"}, &generated.GetBookInfoRequest{Id: "id"}},
+			codes:    []codes.Code{codes.OK, codes.OK},
+			calls:    2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			interceptor := NewIdempotencyUnaryInterceptor(
+				idempotency.New(zap.NewNop(), repository.NewInMemoryRepository(), time.Hour, time.Minute), time.Second)
+			info := &grpc.UnaryServerInfo{FullMethod: test.method}
+			calls := 0
+
+			var responses []proto.Message
+
+			for i, req := range test.requests {
+				resp, err := interceptor(test.ctx, req, info, addBookHandler(&calls, test.failures))
+				require.Equal(t, test.codes[i], status.Code(err), err)
+
+				if err == nil {
+					responses = append(responses, resp.(proto.Message))
+				}
+			}
+
+			require.Equal(t, test.calls, calls)
+
+			// A replay returns the response to the first request.
+			for i := 1; i < len(responses); i++ {
+				require.Equal(t, test.replayed, proto.Equal(responses[0], responses[i]))
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyInUse(t *testing.T) {
+	t.Parallel()
+
+	interceptor := NewIdempotencyUnaryInterceptor(
+		idempotency.New(zap.NewNop(), repository.NewInMemoryRepository(), time.Hour, time.Minute), time.Second)
+	info := &grpc.UnaryServerInfo{FullMethod: generated.Library_AddBook_FullMethodName}
+	req := &generated.AddBookRequest{Name: "Dune"}
+	calls := 0
+
+	// A retry arriving while the first request runs is aborted.
+	_, err := interceptor(withIdempotencyKey("key"), req, info, func(ctx context.Context, req any) (any, error) {
+		_, retryErr := interceptor(ctx, req, info, addBookHandler(&calls, 0))
+		require.Equal(t, codes.Aborted, status.Code(retryErr))
+
+		return addBookHandler(&calls, 0)(ctx, req)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// The keys of different callers do not collide.
+	for _, subject := range []string{"alice", "bob"} {
+		ctx := entity.WithPrincipal(withIdempotencyKey("key"), entity.Principal{Subject: subject})
+
+		_, err = interceptor(ctx, req, info, addBookHandler(&calls, 0))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 3, calls)
+}
+
+// failingIdempotencyRepository can not store responses.
+type failingIdempotencyRepository struct {
+	repository.IdempotencyRepository
+}
+
+func (failingIdempotencyRepository) CompleteIdempotencyKey(context.Context, string, string, []byte) error {
+	return errors.New("database is down")
+}
+
+func TestIdempotencyCompleteFailure(t *testing.T) {
+	t.Parallel()
+
+	repo := failingIdempotencyRepository{IdempotencyRepository: repository.NewInMemoryRepository()}
+	interceptor := NewIdempotencyUnaryInterceptor(idempotency.New(zap.NewNop(), repo, time.Hour, time.Minute), time.Second)
+	info := &grpc.UnaryServerInfo{FullMethod: generated.Library_AddBook_FullMethodName}
+	req := &generated.AddBookRequest{Name: "Dune"}
+	calls := 0
+
+	// The book has been added, so the client gets the response.
+	resp, err := interceptor(withIdempotencyKey("key"), req, info, addBookHandler(&calls, 0))
+	require.NoError(t, err)
+	require.Equal(t, "1", resp.(*generated.AddBookResponse).GetBook().GetId())
+
+	// The key stays reserved, a retry does not add the book again.
+	_, err = interceptor(withIdempotencyKey("key"), req, info, addBookHandler(&calls, 0))
+	require.Equal(t, codes.Aborted, status.Code(err))
+	require.Equal(t, 1, calls)
+}
+
+func TestIdempotencyDeadline(t *testing.T) {
+	t.Parallel()
+
+	interceptor := NewIdempotencyUnaryInterceptor(
+		idempotency.New(zap.NewNop(), repository.NewInMemoryRepository(), time.Hour, time.Minute), time.Second)
+	info := &grpc.UnaryServerInfo{FullMethod: generated.Library_AddBook_FullMethodName}
+	calls := 0
+
+	_, err := interceptor(withIdempotencyKey("key"), &generated.AddBookRequest{Name: "Dune"}, info,
+		func(ctx context.Context, req any) (any, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			require.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+
+			return addBookHandler(&calls, 0)(ctx, req)
+		})
+	require.NoError(t, err)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListApiKeys(
+	ctx context.Context,
+	req *generated.ListApiKeysRequest,
+) (*generated.ListApiKeysResponse, error) {
+	i.logger.Info("received ListApiKeys request", zap.Bool("include_revoked", req.GetIncludeRevoked()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	keys, err := i.apiKeysUseCase.List(ctx, req.GetIncludeRevoked())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	result := make([]*generated.ApiKey, 0, len(keys))
+
+	for _, key := range keys {
+		result = append(result, toProtoAPIKey(key))
+	}
+
+	return &generated.ListApiKeysResponse{ApiKeys: result}, nil
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListBookCopies(
+	ctx context.Context,
+	req *generated.ListBookCopiesRequest,
+) (*generated.ListBookCopiesResponse, error) {
+	i.logger.Info("received ListBookCopies request", zap.String("book_id", req.GetBookId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	copies, err := i.copiesUseCase.ListBookCopies(ctx, req.GetBookId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	converted := make([]*generated.Copy, 0, len(copies))
+
+	for _, bookCopy := range copies {
+		converted = append(converted, toProtoCopy(bookCopy))
+	}
+
+	return &generated.ListBookCopiesResponse{
+		Copies: converted,
+	}, nil
+}
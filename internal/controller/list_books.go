@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListBooks(
+	ctx context.Context,
+	req *generated.ListBooksRequest,
+) (*generated.ListBooksResponse, error) {
+	i.logger.Info("received ListBooks request",
+		zap.Int32("page_size", req.GetPageSize()),
+		zap.String("subject_id", req.GetSubjectId()),
+		zap.String("tag", req.GetTag()),
+		zap.String("title", req.GetTitle()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	afterName, afterID, err := parseBookPageToken(req.GetPageToken())
+
+	if err != nil {
+		return nil, err
+	}
+
+	size := pageSize(req.GetPageSize())
+	books, err := i.booksUseCase.ListBooks(ctx, toBookFilter(req), afterName, afterID, size+1)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	page := books[:min(size, len(books))]
+
+	var nextToken string
+
+	if len(page) > 0 {
+		nextToken = nextBookPageToken(len(books), size, page[len(page)-1])
+	}
+
+	return &generated.ListBooksResponse{
+		Books:         toProtoBooks(page),
+		NextPageToken: nextToken,
+	}, nil
+}
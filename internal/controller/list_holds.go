@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *implementation) ListHolds(ctx context.Context, req *generated.ListHoldsRequest) (*generated.ListHoldsResponse, error) {
+	i.logger.Info("received ListHolds request",
+		zap.String("book_id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()),
+		zap.Bool("include_closed", req.GetIncludeClosed()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	if req.GetBookId() == "" && req.GetPatronId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id or patron_id must be set")
+	}
+
+	holds, err := i.holdsUseCase.ListHolds(ctx, entity.HoldFilter{
+		BookID:     req.GetBookId(),
+		PatronID:   req.GetPatronId(),
+		ActiveOnly: !req.GetIncludeClosed(),
+	})
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	result := make([]*generated.Hold, 0, len(holds))
+
+	for _, hold := range holds {
+		result = append(result, toProtoHold(hold))
+	}
+
+	return &generated.ListHoldsResponse{
+		Holds: result,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListPatrons(
+	ctx context.Context,
+	req *generated.ListPatronsRequest,
+) (*generated.ListPatronsResponse, error) {
+	i.logger.Info("received ListPatrons request",
+		zap.Int32("page_size", req.GetPageSize()),
+		zap.Stringer("status", req.GetStatus()),
+		zap.Stringer("membership_type", req.GetMembershipType()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	afterCardNumber, err := parseCardPageToken(req.GetPageToken())
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.PatronFilter{
+		Status:         toPatronStatus(req.GetStatus()),
+		MembershipType: toMembershipType(req.GetMembershipType()),
+	}
+
+	size := pageSize(req.GetPageSize())
+	patrons, err := i.patronsUseCase.ListPatrons(ctx, filter, afterCardNumber, size+1)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	page := patrons[:min(size, len(patrons))]
+	result := make([]*generated.Patron, 0, len(page))
+
+	for _, patron := range page {
+		result = append(result, toProtoPatron(patron))
+	}
+
+	var lastCardNumber string
+
+	if len(page) > 0 {
+		lastCardNumber = page[len(page)-1].CardNumber
+	}
+
+	return &generated.ListPatronsResponse{
+		Patrons:       result,
+		NextPageToken: nextCardPageToken(len(patrons), size, lastCardNumber),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *implementation) ListReviews(
+	ctx context.Context,
+	req *generated.ListReviewsRequest,
+) (*generated.ListReviewsResponse, error) {
+	i.logger.Info("received ListReviews request",
+		zap.String("book_id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()),
+		zap.Int32("page_size", req.GetPageSize()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	if req.GetBookId() == "" && req.GetPatronId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id or patron_id must be set")
+	}
+
+	afterCreatedAt, afterID, err := parseReviewPageToken(req.GetPageToken())
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entity.ReviewFilter{BookID: req.GetBookId(), PatronID: req.GetPatronId()}
+	size := pageSize(req.GetPageSize())
+	reviews, err := i.reviewsUseCase.ListReviews(ctx, filter, afterCreatedAt, afterID, size+1)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	page := reviews[:min(size, len(reviews))]
+	result := make([]*generated.Review, 0, len(page))
+
+	for _, review := range page {
+		result = append(result, toProtoReview(review))
+	}
+
+	var nextToken string
+
+	if len(page) > 0 {
+		nextToken = nextReviewPageToken(len(reviews), size, page[len(page)-1])
+	}
+
+	return &generated.ListReviewsResponse{
+		Reviews:       result,
+		NextPageToken: nextToken,
+	}, nil
+}
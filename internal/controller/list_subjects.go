@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListSubjects(
+	ctx context.Context,
+	req *generated.ListSubjectsRequest,
+) (*generated.ListSubjectsResponse, error) {
+	i.logger.Info("received ListSubjects request", zap.String("root_id", req.GetRootId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	subjects, err := i.subjectsUseCase.ListSubjects(ctx, req.GetRootId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	result := make([]*generated.Subject, 0, len(subjects))
+
+	for _, subject := range subjects {
+		result = append(result, toProtoSubject(subject))
+	}
+
+	return &generated.ListSubjectsResponse{
+		Subjects: result,
+	}, nil
+}
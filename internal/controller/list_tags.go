@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"cmp"
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ListTags(
+	ctx context.Context,
+	req *generated.ListTagsRequest,
+) (*generated.ListTagsResponse, error) {
+	i.logger.Info("received ListTags request",
+		zap.String("subject_id", req.GetSubjectId()),
+		zap.String("tag", req.GetTag()),
+		zap.String("title", req.GetTitle()),
+		zap.Int32("limit", req.GetLimit()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	limit := cmp.Or(int(req.GetLimit()), defaultTagLimit)
+	counts, err := i.booksUseCase.ListTags(ctx, toBookFilter(req), limit)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	result := make([]*generated.TagCount, 0, len(counts))
+
+	for _, count := range counts {
+		result = append(result, &generated.TagCount{
+			Tag:   count.Tag,
+			Count: int32(count.Count),
+		})
+	}
+
+	return &generated.ListTagsResponse{
+		Tags: result,
+	}, nil
+}
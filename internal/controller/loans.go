@@ -0,0 +1,24 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoLoan(loan entity.Loan) *generated.Loan {
+	result := &generated.Loan{
+		Id:           loan.ID,
+		CopyId:       loan.CopyID,
+		PatronId:     loan.PatronID,
+		CheckedOutAt: timestamppb.New(loan.CheckedOutAt),
+		DueAt:        timestamppb.New(loan.DueAt),
+		Renewals:     int32(loan.Renewals),
+	}
+
+	if loan.ReturnedAt != nil {
+		result.ReturnedAt = timestamppb.New(*loan.ReturnedAt)
+	}
+
+	return result
+}
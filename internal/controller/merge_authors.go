@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) MergeAuthors(
+	ctx context.Context,
+	req *generated.MergeAuthorsRequest,
+) (*generated.MergeAuthorsResponse, error) {
+	i.logger.Info("received MergeAuthors request",
+		zap.String("target_id", req.GetTargetId()),
+		zap.Strings("source_ids", req.GetSourceIds()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.MergeAuthors(ctx, req.GetTargetId(), req.GetSourceIds())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.MergeAuthorsResponse{
+		Author: toProtoAuthor(author),
+	}, nil
+}
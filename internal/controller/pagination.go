@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultPageSize = 50
+
+// pageSize returns the requested page size or the default one.
+func pageSize(requested int32) int {
+	if requested <= 0 {
+		return defaultPageSize
+	}
+
+	return int(requested)
+}
+
+// parsePageToken returns the id after which the requested page starts.
+func parsePageToken(token string) (uint64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	afterID, err := strconv.ParseUint(token, 10, 64)
+
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "malformed page token %q", token)
+	}
+
+	return afterID, nil
+}
+
+// nextPageToken returns the token of the page following the one ending with
+// lastID. One item more than the page size is fetched to tell whether it is
+// the last page.
+func nextPageToken(fetched int, size int, lastID uint64) string {
+	if fetched <= size {
+		return ""
+	}
+
+	return strconv.FormatUint(lastID, 10)
+}
+
+// parseBookPageToken returns the name and the id of the book after which the
+// requested page of books starts.
+func parseBookPageToken(token string) (string, string, error) {
+	if token == "" {
+		return "", "", nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	name, bookID, found := strings.Cut(string(decoded), "\x00")
+
+	if err != nil || !found || bookID == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "malformed page token %q", token)
+	}
+
+	return name, bookID, nil
+}
+
+// nextBookPageToken works as nextPageToken for pages ordered by book name.
+func nextBookPageToken(fetched int, size int, last entity.Book) string {
+	if fetched <= size {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(last.Name + "\x00" + last.ID))
+}
+
+// parseReviewPageToken returns the creation time and the id of the review
+// after which the requested page of reviews starts.
+func parseReviewPageToken(token string) (time.Time, string, error) {
+	if token == "" {
+		return time.Time{}, "", nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	createdAt, reviewID, found := strings.Cut(string(decoded), "\x00")
+	moment, parseErr := time.Parse(time.RFC3339Nano, createdAt)
+
+	if err != nil || parseErr != nil || !found || reviewID == "" {
+		return time.Time{}, "", status.Errorf(codes.InvalidArgument, "malformed page token %q", token)
+	}
+
+	return moment, reviewID, nil
+}
+
+// nextReviewPageToken works as nextPageToken for pages of reviews, newest
+// first.
+func nextReviewPageToken(fetched int, size int, last entity.Review) string {
+	if fetched <= size {
+		return ""
+	}
+
+	token := last.CreatedAt.UTC().Format(time.RFC3339Nano) + "\x00" + last.ID
+
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"encoding/base64"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoPatron(patron entity.Patron) *generated.Patron {
+	return &generated.Patron{
+		Id:               patron.ID,
+		CardNumber:       patron.CardNumber,
+		Name:             patron.Name.Reveal(),
+		Email:            patron.Email.Reveal(),
+		Phone:            patron.Phone.Reveal(),
+		Address:          patron.Address.Reveal(),
+		MembershipType:   toProtoMembershipType(patron.MembershipType),
+		ExpiresOn:        patron.ExpiresOn,
+		Status:           toProtoPatronStatus(patron.Status),
+		SuspensionReason: patron.SuspensionReason,
+		CreatedAt:        timestamppb.New(patron.CreatedAt),
+		UpdatedAt:        timestamppb.New(patron.UpdatedAt),
+	}
+}
+
+var membershipTypes = map[generated.MembershipType]entity.MembershipType{
+	generated.MembershipType_MEMBERSHIP_TYPE_STANDARD: entity.MembershipTypeStandard,
+	generated.MembershipType_MEMBERSHIP_TYPE_STUDENT:  entity.MembershipTypeStudent,
+	generated.MembershipType_MEMBERSHIP_TYPE_SENIOR:   entity.MembershipTypeSenior,
+	generated.MembershipType_MEMBERSHIP_TYPE_STAFF:    entity.MembershipTypeStaff,
+}
+
+var patronStatuses = map[generated.PatronStatus]entity.PatronStatus{
+	generated.PatronStatus_PATRON_STATUS_ACTIVE:    entity.PatronStatusActive,
+	generated.PatronStatus_PATRON_STATUS_SUSPENDED: entity.PatronStatusSuspended,
+}
+
+// toMembershipType maps the unspecified membership type to an empty one.
+func toMembershipType(membershipType generated.MembershipType) entity.MembershipType {
+	return membershipTypes[membershipType]
+}
+
+func toProtoMembershipType(membershipType entity.MembershipType) generated.MembershipType {
+	for converted, value := range membershipTypes {
+		if value == membershipType {
+			return converted
+		}
+	}
+
+	return generated.MembershipType_MEMBERSHIP_TYPE_UNSPECIFIED
+}
+
+// toPatronStatus maps the unspecified status to an empty one.
+func toPatronStatus(patronStatus generated.PatronStatus) entity.PatronStatus {
+	return patronStatuses[patronStatus]
+}
+
+func toProtoPatronStatus(patronStatus entity.PatronStatus) generated.PatronStatus {
+	for converted, value := range patronStatuses {
+		if value == patronStatus {
+			return converted
+		}
+	}
+
+	return generated.PatronStatus_PATRON_STATUS_UNSPECIFIED
+}
+
+// parseCardPageToken returns the card number after which the requested page
+// of patrons starts.
+func parseCardPageToken(token string) (string, error) {
+	afterCardNumber, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "malformed page token %q", token)
+	}
+
+	return string(afterCardNumber), nil
+}
+
+// nextCardPageToken works as nextPageToken for pages ordered by card number.
+func nextCardPageToken(fetched int, size int, lastCardNumber string) string {
+	if fetched <= size {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(lastCardNumber))
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) PayFine(
+	ctx context.Context,
+	req *generated.PayFineRequest,
+) (*generated.PayFineResponse, error) {
+	i.logger.Info("received PayFine request",
+		zap.String("patron_id", req.GetPatronId()),
+		zap.Int64("amount_cents", req.GetAmountCents()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	balance, err := i.finesUseCase.PayFine(ctx, req.GetPatronId(), req.GetAmountCents(), req.GetNote())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.PayFineResponse{
+		BalanceCents: balance,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) PlaceHold(ctx context.Context, req *generated.PlaceHoldRequest) (*generated.PlaceHoldResponse, error) {
+	i.logger.Info("received PlaceHold request",
+		zap.String("book_id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	hold, err := i.holdsUseCase.PlaceHold(ctx, req.GetBookId(), req.GetPatronId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.PlaceHoldResponse{
+		Hold: toProtoHold(hold),
+	}, nil
+}
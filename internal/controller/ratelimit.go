@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterMetadataKey carries the seconds a throttled client should wait
+// before retrying. The gateway returns it as the Retry-After header.
+const RetryAfterMetadataKey = "retry-after"
+
+// forwardedForMetadataKey is set by the gateway to the address of the HTTP
+// client, the last one in the list is the one the gateway saw. It is only
+// trusted on the requests carrying the GatewayCredentials.
+const forwardedForMetadataKey = "x-forwarded-for"
+
+// NewRateLimitUnaryInterceptor rejects the requests of a client that has
+// used up its limit for the method with RESOURCE_EXHAUSTED. The requests
+// forwarded by the gateway are counted against the HTTP client.
+func NewRateLimitUnaryInterceptor(limiter ratelimit.Limiter, gateway *GatewayCredentials) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if exempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		if allowed, wait := limiter.Allow(rateLimitClient(ctx, gateway), info.FullMethod); !allowed {
+			_ = grpc.SetHeader(ctx, retryAfter(wait))
+			return nil, rateLimitError(wait)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewRateLimitStreamInterceptor works as NewRateLimitUnaryInterceptor for
+// streaming calls and caps the concurrent streams of a client.
+func NewRateLimitStreamInterceptor(limiter ratelimit.Limiter, gateway *GatewayCredentials) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if exempt(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		client := rateLimitClient(stream.Context(), gateway)
+
+		if allowed, wait := limiter.Allow(client, info.FullMethod); !allowed {
+			_ = stream.SetHeader(retryAfter(wait))
+			return rateLimitError(wait)
+		}
+
+		release, ok := limiter.Acquire(client, info.FullMethod)
+
+		if !ok {
+			return status.Error(codes.ResourceExhausted, "too many concurrent streams")
+		}
+
+		defer release()
+
+		return handler(srv, stream)
+	}
+}
+
+// rateLimitClient identifies the caller by its principal, which is an API
+// key or the subject of a bearer token, and else by its IP address.
+func rateLimitClient(ctx context.Context, gateway *GatewayCredentials) string {
+	if principal, ok := entity.PrincipalFromContext(ctx); ok {
+		return "principal:" + principal.Subject
+	}
+
+	var address string
+
+	if p, ok := peer.FromContext(ctx); ok {
+		address = p.Addr.String()
+	}
+
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		host = address
+	}
+
+	// The client of a request forwarded by the gateway is the one it
+	// forwards for, any other caller could name a new one on every call.
+	if gateway.forwarded(ctx) {
+		if values := metadata.ValueFromIncomingContext(ctx, forwardedForMetadataKey); len(values) > 0 {
+			forwarded := strings.Split(values[len(values)-1], ",")
+			host = strings.TrimSpace(forwarded[len(forwarded)-1])
+		}
+	}
+
+	return "ip:" + host
+}
+
+func retryAfter(wait time.Duration) metadata.MD {
+	return metadata.Pairs(RetryAfterMetadataKey, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
+
+func rateLimitError(wait time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, "rate limit exceeded").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	return st.Err()
+}
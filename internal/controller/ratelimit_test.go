@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/ratelimit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const getAuthorBooksMethod = "/library.Library/GetAuthorBooks"
+
+// fakeTransportStream records the headers a unary handler sets.
+type fakeTransportStream struct {
+	header metadata.MD
+}
+
+func (f *fakeTransportStream) Method() string {
+	return getBookInfoMethod
+}
+
+func (f *fakeTransportStream) SetHeader(md metadata.MD) error {
+	f.header = metadata.Join(f.header, md)
+	return nil
+}
+
+func (f *fakeTransportStream) SendHeader(md metadata.MD) error {
+	return f.SetHeader(md)
+}
+
+func (f *fakeTransportStream) SetTrailer(metadata.MD) error {
+	return nil
+}
+
+func withPeer(ctx context.Context, address string) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(address), Port: 50000}})
+}
+
+func withForwardedFor(ctx context.Context, addresses string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return metadata.NewIncomingContext(ctx, metadata.Join(md, metadata.Pairs(forwardedForMetadataKey, addresses)))
+}
+
+// withGateway attaches the credentials the gateway sends.
+func withGateway(ctx context.Context, gateway *GatewayCredentials) context.Context {
+	credentials, _ := gateway.GetRequestMetadata(ctx)
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return metadata.NewIncomingContext(ctx, metadata.Join(md, metadata.New(credentials)))
+}
+
+// requireThrottled checks that err rejects a request for about a second and
+// the header tells the client when to retry.
+func requireThrottled(t *testing.T, err error, header metadata.MD) {
+	t.Helper()
+
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+	require.Equal(t, []string{"1"}, header.Get(RetryAfterMetadataKey))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+
+	retryInfo, ok := details[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.InDelta(t, time.Second, retryInfo.GetRetryDelay().AsDuration(), float64(100*time.Millisecond))
+}
+
+func TestRateLimitUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	gateway := NewGatewayCredentials()
+	interceptor := NewRateLimitUnaryInterceptor(ratelimit.New(ratelimit.Limit{Rate: 1, Burst: 1}, nil, nil), gateway)
+	info := &grpc.UnaryServerInfo{FullMethod: getBookInfoMethod}
+	calls := 0
+
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		return calls, nil
+	}
+
+	call := func(ctx context.Context) (metadata.MD, error) {
+		stream := &fakeTransportStream{}
+		_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, handler)
+
+		return stream.header, err
+	}
+
+	alice := entity.WithPrincipal(withPeer(context.Background(), "192.0.2.1"), entity.Principal{Subject: "alice"})
+
+	_, err := call(alice)
+	require.NoError(t, err)
+
+	header, err := call(alice)
+	requireThrottled(t, err, header)
+	require.Equal(t, 1, calls)
+
+	// Another caller from the same address has a bucket of its own.
+	_, err = call(withPeer(context.Background(), "192.0.2.1"))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// A local caller can not pass for new clients by naming them.
+	loopback := withPeer(context.Background(), "127.0.0.1")
+
+	_, err = call(withForwardedFor(loopback, "198.51.100.7"))
+	require.NoError(t, err)
+
+	header, err = call(withForwardedFor(loopback, "198.51.100.8"))
+	requireThrottled(t, err, header)
+
+	// The clients the gateway forwards for have buckets of their own.
+	for _, forwarded := range []string{"198.51.100.7", "198.51.100.8"} {
+		_, err = call(withGateway(withForwardedFor(loopback, forwarded), gateway))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 5, calls)
+
+	// Health checks are never throttled.
+	for range 3 {
+		_, err = interceptor(alice, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimitStreamInterceptor(t *testing.T) {
+	t.Parallel()
+
+	interceptor := NewRateLimitStreamInterceptor(ratelimit.New(
+		ratelimit.Limit{},
+		map[string]ratelimit.Limit{getBookInfoMethod: {Rate: 1, Burst: 1}},
+		map[string]int{getAuthorBooksMethod: 1},
+	), nil)
+	ctx := entity.WithPrincipal(context.Background(), entity.Principal{Subject: "alice"})
+	calls := 0
+
+	handler := func(any, grpc.ServerStream) error {
+		calls++
+		return nil
+	}
+
+	call := func(method string, next grpc.StreamHandler) (metadata.MD, error) {
+		stream := &fakeServerStream{ctx: ctx}
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: method}, next)
+
+		return stream.header, err
+	}
+
+	_, err := call(getBookInfoMethod, handler)
+	require.NoError(t, err)
+
+	header, err := call(getBookInfoMethod, handler)
+	requireThrottled(t, err, header)
+
+	// A second stream is refused while the first one is open, and accepted
+	// once it has ended.
+	_, err = call(getAuthorBooksMethod, func(srv any, stream grpc.ServerStream) error {
+		_, concurrentErr := call(getAuthorBooksMethod, handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(concurrentErr))
+
+		return handler(srv, stream)
+	})
+	require.NoError(t, err)
+
+	_, err = call(getAuthorBooksMethod, handler)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestRateLimitClient(t *testing.T) {
+	t.Parallel()
+
+	gateway := NewGatewayCredentials()
+	forwardedFor := withForwardedFor(context.Background(), "203.0.113.9, 198.51.100.7")
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		client string
+	}{
+		{
+			name:   "principal",
+			ctx:    entity.WithPrincipal(withPeer(context.Background(), "192.0.2.1"), entity.Principal{Subject: "alice"}),
+			client: "principal:alice",
+		},
+		{
+			name:   "peer",
+			ctx:    withPeer(context.Background(), "192.0.2.1"),
+			client: "ip:192.0.2.1",
+		},
+		{
+			name: "forwarded by a remote peer",
+			ctx: withPeer(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(forwardedForMetadataKey, "198.51.100.7")), "192.0.2.1"),
+			client: "ip:192.0.2.1",
+		},
+		{
+			name:   "forwarded by a loopback peer",
+			ctx:    withPeer(forwardedFor, "127.0.0.1"),
+			client: "ip:127.0.0.1",
+		},
+		{
+			name:   "forwarded with another secret",
+			ctx:    withGateway(withPeer(forwardedFor, "127.0.0.1"), NewGatewayCredentials()),
+			client: "ip:127.0.0.1",
+		},
+		{
+			name:   "forwarded by the gateway",
+			ctx:    withGateway(withPeer(forwardedFor, "127.0.0.1"), gateway),
+			client: "ip:198.51.100.7",
+		},
+		{
+			name:   "no peer",
+			ctx:    context.Background(),
+			client: "ip:",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.client, rateLimitClient(test.ctx, gateway))
+		})
+	}
+}
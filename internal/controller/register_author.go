@@ -1 +1,29 @@
 package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) RegisterAuthor(
+	ctx context.Context,
+	req *generated.RegisterAuthorRequest,
+) (*generated.RegisterAuthorResponse, error) {
+	i.logger.Info("received RegisterAuthor request", zap.String("name", req.GetName()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.RegisterAuthor(ctx, req.GetName(), toAuthorProfile(req))
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.RegisterAuthorResponse{
+		Id: author.ID,
+	}, nil
+}
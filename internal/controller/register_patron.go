@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) RegisterPatron(
+	ctx context.Context,
+	req *generated.RegisterPatronRequest,
+) (*generated.RegisterPatronResponse, error) {
+	// The personal data of the patron is not logged.
+	i.logger.Info("received RegisterPatron request",
+		zap.String("card_number", req.GetCardNumber()),
+		zap.Stringer("membership_type", req.GetMembershipType()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patron, err := i.patronsUseCase.RegisterPatron(ctx, entity.Patron{
+		CardNumber: req.GetCardNumber(),
+		Name:       entity.Sensitive(req.GetName()),
+		PatronContact: entity.PatronContact{
+			Email:   entity.Sensitive(req.GetEmail()),
+			Phone:   entity.Sensitive(req.GetPhone()),
+			Address: entity.Sensitive(req.GetAddress()),
+		},
+		MembershipType: toMembershipType(req.GetMembershipType()),
+		ExpiresOn:      req.GetExpiresOn(),
+	})
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.RegisterPatronResponse{
+		Patron: toProtoPatron(patron),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ReinstatePatron(
+	ctx context.Context,
+	req *generated.ReinstatePatronRequest,
+) (*generated.ReinstatePatronResponse, error) {
+	i.logger.Info("received ReinstatePatron request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patron, err := i.patronsUseCase.ReinstatePatron(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.ReinstatePatronResponse{
+		Patron: toProtoPatron(patron),
+	}, nil
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) RenewLoan(ctx context.Context, req *generated.RenewLoanRequest) (*generated.RenewLoanResponse, error) {
+	i.logger.Info("received RenewLoan request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	loan, err := i.loansUseCase.RenewLoan(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.RenewLoanResponse{
+		Loan: toProtoLoan(loan),
+	}, nil
+}
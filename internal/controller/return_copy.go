@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) ReturnCopy(ctx context.Context, req *generated.ReturnCopyRequest) (*generated.ReturnCopyResponse, error) {
+	i.logger.Info("received ReturnCopy request", zap.String("copy_id", req.GetCopyId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	loan, err := i.loansUseCase.ReturnCopy(ctx, req.GetCopyId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.ReturnCopyResponse{
+		Loan: toProtoLoan(loan),
+	}, nil
+}
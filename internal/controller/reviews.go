@@ -0,0 +1,26 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoReview(review entity.Review) *generated.Review {
+	return &generated.Review{
+		Id:        review.ID,
+		BookId:    review.BookID,
+		PatronId:  review.PatronID,
+		Rating:    int32(review.Rating),
+		Text:      review.Text,
+		CreatedAt: timestamppb.New(review.CreatedAt),
+		UpdatedAt: timestamppb.New(review.UpdatedAt),
+	}
+}
+
+func toProtoBookRating(rating entity.BookRating) *generated.BookRating {
+	return &generated.BookRating{
+		Average: rating.Average(),
+		Count:   int32(rating.Count),
+	}
+}
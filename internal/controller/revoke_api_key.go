@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) RevokeApiKey(
+	ctx context.Context,
+	req *generated.RevokeApiKeyRequest,
+) (*generated.RevokeApiKeyResponse, error) {
+	i.logger.Info("received RevokeApiKey request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	key, err := i.apiKeysUseCase.Revoke(ctx, req.GetId())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.RevokeApiKeyResponse{ApiKey: toProtoAPIKey(key)}, nil
+}
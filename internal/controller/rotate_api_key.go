@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) RotateApiKey(
+	ctx context.Context,
+	req *generated.RotateApiKeyRequest,
+) (*generated.RotateApiKeyResponse, error) {
+	i.logger.Info("received RotateApiKey request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+
+	if req.GetExpiresAt() != nil {
+		moment := req.GetExpiresAt().AsTime()
+		expiresAt = &moment
+	}
+
+	key, secret, err := i.apiKeysUseCase.Rotate(ctx, req.GetId(), expiresAt)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.RotateApiKeyResponse{
+		ApiKey: toProtoAPIKey(key),
+		Secret: secret,
+	}, nil
+}
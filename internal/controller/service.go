@@ -1 +1,63 @@
 package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/library"
+	"go.uber.org/zap"
+)
+
+var _ generated.LibraryServer = (*implementation)(nil)
+
+type implementation struct {
+	logger          *zap.Logger
+	booksUseCase    library.BooksUseCase
+	subjectsUseCase library.SubjectsUseCase
+	worksUseCase    library.WorksUseCase
+	authorUseCase   library.AuthorUseCase
+	copiesUseCase   library.CopiesUseCase
+	patronsUseCase  library.PatronsUseCase
+	loansUseCase    library.LoansUseCase
+	holdsUseCase    library.HoldsUseCase
+	reviewsUseCase  library.ReviewsUseCase
+	finesUseCase    library.FinesUseCase
+	historyUseCase  library.HistoryUseCase
+	catalogUseCase  library.CatalogUseCase
+	apiKeysUseCase  apikey.APIKeys
+}
+
+// Deps are the use cases the service delegates to.
+type Deps struct {
+	BooksUseCase    library.BooksUseCase
+	SubjectsUseCase library.SubjectsUseCase
+	WorksUseCase    library.WorksUseCase
+	AuthorUseCase   library.AuthorUseCase
+	CopiesUseCase   library.CopiesUseCase
+	PatronsUseCase  library.PatronsUseCase
+	LoansUseCase    library.LoansUseCase
+	HoldsUseCase    library.HoldsUseCase
+	ReviewsUseCase  library.ReviewsUseCase
+	FinesUseCase    library.FinesUseCase
+	HistoryUseCase  library.HistoryUseCase
+	CatalogUseCase  library.CatalogUseCase
+	APIKeysUseCase  apikey.APIKeys
+}
+
+func New(logger *zap.Logger, deps Deps) *implementation {
+	return &implementation{
+		logger:          logger,
+		booksUseCase:    deps.BooksUseCase,
+		subjectsUseCase: deps.SubjectsUseCase,
+		worksUseCase:    deps.WorksUseCase,
+		authorUseCase:   deps.AuthorUseCase,
+		copiesUseCase:   deps.CopiesUseCase,
+		patronsUseCase:  deps.PatronsUseCase,
+		loansUseCase:    deps.LoansUseCase,
+		holdsUseCase:    deps.HoldsUseCase,
+		reviewsUseCase:  deps.ReviewsUseCase,
+		finesUseCase:    deps.FinesUseCase,
+		historyUseCase:  deps.HistoryUseCase,
+		catalogUseCase:  deps.CatalogUseCase,
+		apiKeysUseCase:  deps.APIKeysUseCase,
+	}
+}
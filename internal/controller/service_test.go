@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/apikey"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testLoanPolicy = entity.LoanPolicy{
+	Periods: map[entity.MembershipType]time.Duration{
+		entity.MembershipTypeStandard: 21 * 24 * time.Hour,
+	},
+	MaxActiveLoans: 2,
+	MaxRenewals:    1,
+	PickupWindow:   7 * 24 * time.Hour,
+	Fines: entity.FinePolicy{
+		DailyRate:  25,
+		Cap:        100,
+		MaxBalance: 50,
+	},
+}
+
+type testClients struct {
+	library generated.LibraryClient
+	apiKeys generated.ApiKeysClient
+}
+
+// newTestClients serves the controller backed by the in-memory repository
+// and returns clients connected to it.
+func newTestClients(t *testing.T) testClients {
+	t.Helper()
+
+	repo := repository.NewInMemoryRepository()
+	useCases := library.New(zap.NewNop(), library.Deps{
+		AuthorRepository:   repo,
+		BooksRepository:    repo,
+		SubjectsRepository: repo,
+		WorksRepository:    repo,
+		CopiesRepository:   repo,
+		PatronsRepository:  repo,
+		LoansRepository:    repo,
+		HoldsRepository:    repo,
+		ReviewsRepository:  repo,
+		FinesRepository:    repo,
+		CatalogRepository:  repo,
+		AuditRepository:    repo,
+		OutboxRepository:   repo,
+		BlobStore:          repo,
+		Transactor:         repo,
+		LoanPolicy:         testLoanPolicy,
+		MaxCoverSize:       64 << 10,
+	})
+	service := New(zap.NewNop(), Deps{
+		BooksUseCase:    useCases,
+		SubjectsUseCase: useCases,
+		WorksUseCase:    useCases,
+		AuthorUseCase:   useCases,
+		CopiesUseCase:   useCases,
+		PatronsUseCase:  useCases,
+		LoansUseCase:    useCases,
+		HoldsUseCase:    useCases,
+		ReviewsUseCase:  useCases,
+		FinesUseCase:    useCases,
+		HistoryUseCase:  useCases,
+		CatalogUseCase:  useCases,
+		APIKeysUseCase:  apikey.New(zap.NewNop(), repo),
+	})
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(ActorUnaryInterceptor),
+		grpc.ChainStreamInterceptor(ActorStreamInterceptor))
+	generated.RegisterLibraryServer(server, service)
+	generated.RegisterApiKeysServer(server, service)
+
+	listener := bufconn.Listen(1 << 20)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return testClients{
+		library: generated.NewLibraryClient(conn),
+		apiKeys: generated.NewApiKeysClient(conn),
+	}
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	t.Helper()
+
+	require.Equal(t, code, status.Code(err), err)
+}
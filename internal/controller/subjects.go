@@ -0,0 +1,34 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultTagLimit = 100
+
+func toProtoSubject(subject entity.Subject) *generated.Subject {
+	return &generated.Subject{
+		Id:        subject.ID,
+		Name:      subject.Name,
+		ParentId:  subject.ParentID,
+		CreatedAt: timestamppb.New(subject.CreatedAt),
+		UpdatedAt: timestamppb.New(subject.UpdatedAt),
+	}
+}
+
+// bookFilterRequest is implemented by the requests selecting books.
+type bookFilterRequest interface {
+	GetSubjectId() string
+	GetTag() string
+	GetTitle() string
+}
+
+func toBookFilter(req bookFilterRequest) entity.BookFilter {
+	return entity.BookFilter{
+		SubjectID: req.GetSubjectId(),
+		Tag:       req.GetTag(),
+		Title:     req.GetTitle(),
+	}
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) SuspendPatron(
+	ctx context.Context,
+	req *generated.SuspendPatronRequest,
+) (*generated.SuspendPatronResponse, error) {
+	i.logger.Info("received SuspendPatron request", zap.String("id", req.GetId()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patron, err := i.patronsUseCase.SuspendPatron(ctx, req.GetId(), req.GetReason())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.SuspendPatronResponse{
+		Patron: toProtoPatron(patron),
+	}, nil
+}
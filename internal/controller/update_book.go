@@ -1 +1,47 @@
 package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) UpdateBook(
+	ctx context.Context,
+	req *generated.UpdateBookRequest,
+) (*generated.UpdateBookResponse, error) {
+	i.logger.Info("received UpdateBook request",
+		zap.String("id", req.GetId()),
+		zap.String("name", req.GetName()),
+		zap.Strings("author_ids", req.GetAuthorIds()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()),
+		zap.Strings("add_author_ids", req.GetAddAuthorIds()),
+		zap.Strings("remove_author_ids", req.GetRemoveAuthorIds()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := bookPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(ctx, req.ExpectedVersion)
+
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := i.booksUseCase.UpdateBook(ctx, req.GetId(), patch, version)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.UpdateBookResponse{
+		Version: book.Version,
+	}, nil
+}
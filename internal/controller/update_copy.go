@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) UpdateCopy(
+	ctx context.Context,
+	req *generated.UpdateCopyRequest,
+) (*generated.UpdateCopyResponse, error) {
+	i.logger.Info("received UpdateCopy request",
+		zap.String("id", req.GetId()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := copyPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bookCopy, err := i.copiesUseCase.UpdateCopy(ctx, req.GetId(), patch)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.UpdateCopyResponse{
+		Copy: toProtoCopy(bookCopy),
+	}, nil
+}
@@ -0,0 +1,423 @@
+package controller
+
+import (
+	"maps"
+	"slices"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	namePath         = "name"
+	authorIDsPath    = "author_ids"
+	contributorsPath = "contributors"
+	// fullMask stands for every field, like an empty update mask.
+	fullMask = "*"
+)
+
+// replacesAll reports whether the update mask paths replace the whole
+// entity.
+func replacesAll(paths []string) bool {
+	return len(paths) == 0 || slices.Equal(paths, []string{fullMask})
+}
+
+// bookMetadataSetters fill a patch with the metadata fields of the
+// update mask.
+var bookMetadataSetters = map[string]func(patch *entity.BookPatch, metadata *entity.BookMetadata){
+	"isbn": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.ISBN = &metadata.ISBN
+	},
+	"publisher": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.Publisher = &metadata.Publisher
+	},
+	"publication_year": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.PublicationYear = &metadata.PublicationYear
+	},
+	"language": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.Language = &metadata.Language
+	},
+	"page_count": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.PageCount = &metadata.PageCount
+	},
+	"description": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.Description = &metadata.Description
+	},
+	"subject_ids": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.SubjectIDs = &metadata.SubjectIDs
+	},
+	"tags": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.Tags = &metadata.Tags
+	},
+	"work_id": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.WorkID = &metadata.WorkID
+	},
+	"series_id": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.SeriesID = &metadata.SeriesID
+	},
+	"series_position": func(patch *entity.BookPatch, metadata *entity.BookMetadata) {
+		patch.SeriesPosition = &metadata.SeriesPosition
+	},
+}
+
+// bookPatch turns an UpdateBook request into a patch. Without an update mask
+// and incremental author changes the request replaces the whole book.
+func bookPatch(req *generated.UpdateBookRequest) (entity.BookPatch, error) {
+	patch := entity.BookPatch{
+		AddAuthorIDs:    req.GetAddAuthorIds(),
+		RemoveAuthorIDs: req.GetRemoveAuthorIds(),
+	}
+
+	incremental := len(patch.AddAuthorIDs) > 0 || len(patch.RemoveAuthorIDs) > 0
+	metadata := toBookMetadata(req)
+	paths := req.GetUpdateMask().GetPaths()
+
+	if (len(paths) == 0 && !incremental) || slices.Equal(paths, []string{fullMask}) {
+		paths = append([]string{namePath, creditsPath(req)}, slices.Collect(maps.Keys(bookMetadataSetters))...)
+	}
+
+	for _, path := range paths {
+		switch path {
+		case namePath:
+			name := req.GetName()
+			patch.Name = &name
+		case authorIDsPath, contributorsPath:
+			if patch.Contributors != nil {
+				return entity.BookPatch{}, status.Error(codes.InvalidArgument,
+					"author_ids and contributors can not be replaced at once")
+			}
+
+			contributors := replacedCredits(path, req)
+			patch.Contributors = &contributors
+		default:
+			setter, ok := bookMetadataSetters[path]
+
+			if !ok {
+				return entity.BookPatch{}, status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+			}
+
+			setter(&patch, &metadata)
+		}
+	}
+
+	if patch.Contributors != nil && incremental {
+		return entity.BookPatch{}, status.Error(codes.InvalidArgument,
+			"credits can not be replaced and changed incrementally at once")
+	}
+
+	return patch, nil
+}
+
+// creditsPath is the path replacing the credits of a book without an update
+// mask.
+func creditsPath(req *generated.UpdateBookRequest) string {
+	if len(req.GetContributors()) > 0 {
+		return contributorsPath
+	}
+
+	return authorIDsPath
+}
+
+func replacedCredits(path string, req *generated.UpdateBookRequest) []entity.Contributor {
+	if path == contributorsPath {
+		return toContributors(req.GetContributors())
+	}
+
+	return entity.AuthorContributors(req.GetAuthorIds())
+}
+
+// authorProfileSetters fill a patch with the profile fields of the
+// update mask.
+var authorProfileSetters = map[string]func(patch *entity.AuthorPatch, profile *entity.AuthorProfile){
+	"biography": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.Biography = &profile.Biography
+	},
+	"birth_date": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.BirthDate = &profile.BirthDate
+	},
+	"death_date": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.DeathDate = &profile.DeathDate
+	},
+	"country": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.Country = &profile.Country
+	},
+	"aliases": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.Aliases = &profile.Aliases
+	},
+	"external_ids": func(patch *entity.AuthorPatch, profile *entity.AuthorProfile) {
+		patch.ExternalIDs = &profile.ExternalIDs
+	},
+}
+
+// externalIDPaths are the nested update mask paths of single external ids,
+// which PATCH requests setting some of them are masked with.
+var externalIDPaths = map[string]struct {
+	scheme entity.ExternalIDScheme
+	get    func(*generated.ExternalIds) string
+}{
+	"external_ids.viaf":     {scheme: entity.ExternalIDSchemeVIAF, get: (*generated.ExternalIds).GetViaf},
+	"external_ids.isni":     {scheme: entity.ExternalIDSchemeISNI, get: (*generated.ExternalIds).GetIsni},
+	"external_ids.wikidata": {scheme: entity.ExternalIDSchemeWikidata, get: (*generated.ExternalIds).GetWikidata},
+}
+
+// authorPatch turns a ChangeAuthorInfo request into a patch. Without an
+// update mask the request replaces the whole author.
+func authorPatch(req *generated.ChangeAuthorInfoRequest) (entity.AuthorPatch, error) {
+	var patch entity.AuthorPatch
+
+	profile := toAuthorProfile(req)
+	paths := req.GetUpdateMask().GetPaths()
+
+	if replacesAll(paths) {
+		paths = append([]string{namePath}, slices.Collect(maps.Keys(authorProfileSetters))...)
+	}
+
+	for _, path := range paths {
+		if err := setAuthorPath(&patch, &profile, req, path); err != nil {
+			return entity.AuthorPatch{}, err
+		}
+	}
+
+	// The name may only be empty in the request when it is not updated.
+	if patch.Name != nil && *patch.Name == "" {
+		return entity.AuthorPatch{}, status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	return patch, nil
+}
+
+func setAuthorPath(
+	patch *entity.AuthorPatch,
+	profile *entity.AuthorProfile,
+	req *generated.ChangeAuthorInfoRequest,
+	path string,
+) error {
+	if path == namePath {
+		name := req.GetName()
+		patch.Name = &name
+
+		return nil
+	}
+
+	if externalID, ok := externalIDPaths[path]; ok {
+		if patch.ExternalIDUpdates == nil {
+			patch.ExternalIDUpdates = make(map[entity.ExternalIDScheme]string)
+		}
+
+		patch.ExternalIDUpdates[externalID.scheme] = externalID.get(req.GetExternalIds())
+
+		return nil
+	}
+
+	setter, ok := authorProfileSetters[path]
+
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+	}
+
+	setter(patch, profile)
+
+	return nil
+}
+
+// copySetters fill a patch with the fields of the update mask.
+var copySetters = map[string]func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest){
+	"barcode": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		barcode := req.GetBarcode()
+		patch.Barcode = &barcode
+	},
+	"branch": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		branch := req.GetBranch()
+		patch.Branch = &branch
+	},
+	"location": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		location := req.GetLocation()
+		patch.Location = &location
+	},
+	"condition": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		condition := toCopyCondition(req.GetCondition())
+		patch.Condition = &condition
+	},
+	"acquired_on": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		acquiredOn := req.GetAcquiredOn()
+		patch.AcquiredOn = &acquiredOn
+	},
+	"status": func(patch *entity.CopyPatch, req *generated.UpdateCopyRequest) {
+		status := toCopyStatus(req.GetStatus())
+		patch.Status = &status
+	},
+}
+
+// copyPatch turns an UpdateCopy request into a patch. Without an update mask
+// the request replaces the whole copy.
+func copyPatch(req *generated.UpdateCopyRequest) (entity.CopyPatch, error) {
+	var patch entity.CopyPatch
+
+	paths := req.GetUpdateMask().GetPaths()
+
+	if replacesAll(paths) {
+		paths = slices.Collect(maps.Keys(copySetters))
+	}
+
+	for _, path := range paths {
+		setter, ok := copySetters[path]
+
+		if !ok {
+			return entity.CopyPatch{}, status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+		}
+
+		setter(&patch, req)
+	}
+
+	// The barcode may only be empty in the request when it is not updated.
+	if patch.Barcode != nil && *patch.Barcode == "" {
+		return entity.CopyPatch{}, status.Error(codes.InvalidArgument, "barcode must not be empty")
+	}
+
+	return patch, nil
+}
+
+// patronSetters fill a patch with the fields of the update mask.
+var patronSetters = map[string]func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest){
+	"card_number": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		cardNumber := req.GetCardNumber()
+		patch.CardNumber = &cardNumber
+	},
+	"name": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		name := entity.Sensitive(req.GetName())
+		patch.Name = &name
+	},
+	"email": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		email := entity.Sensitive(req.GetEmail())
+		patch.Email = &email
+	},
+	"phone": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		phone := entity.Sensitive(req.GetPhone())
+		patch.Phone = &phone
+	},
+	"address": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		address := entity.Sensitive(req.GetAddress())
+		patch.Address = &address
+	},
+	"membership_type": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		membershipType := toMembershipType(req.GetMembershipType())
+		patch.MembershipType = &membershipType
+	},
+	"expires_on": func(patch *entity.PatronPatch, req *generated.UpdatePatronRequest) {
+		expiresOn := req.GetExpiresOn()
+		patch.ExpiresOn = &expiresOn
+	},
+}
+
+// patronPatch turns an UpdatePatron request into a patch. Without an update
+// mask the request replaces the whole patron.
+func patronPatch(req *generated.UpdatePatronRequest) (entity.PatronPatch, error) {
+	var patch entity.PatronPatch
+
+	paths := req.GetUpdateMask().GetPaths()
+
+	if replacesAll(paths) {
+		paths = slices.Collect(maps.Keys(patronSetters))
+	}
+
+	for _, path := range paths {
+		setter, ok := patronSetters[path]
+
+		if !ok {
+			return entity.PatronPatch{}, status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+		}
+
+		setter(&patch, req)
+	}
+
+	// The card number and the name may only be empty in the request when
+	// they are not updated.
+	if patch.CardNumber != nil && *patch.CardNumber == "" {
+		return entity.PatronPatch{}, status.Error(codes.InvalidArgument, "card_number must not be empty")
+	}
+
+	if patch.Name != nil && *patch.Name == "" {
+		return entity.PatronPatch{}, status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	return patch, nil
+}
+
+// subjectSetters fill a patch with the fields of the update mask.
+var subjectSetters = map[string]func(patch *entity.SubjectPatch, req *generated.UpdateSubjectRequest){
+	"name": func(patch *entity.SubjectPatch, req *generated.UpdateSubjectRequest) {
+		name := req.GetName()
+		patch.Name = &name
+	},
+	"parent_id": func(patch *entity.SubjectPatch, req *generated.UpdateSubjectRequest) {
+		parentID := req.GetParentId()
+		patch.ParentID = &parentID
+	},
+}
+
+// subjectPatch turns an UpdateSubject request into a patch. Without an update
+// mask the request replaces the whole subject.
+func subjectPatch(req *generated.UpdateSubjectRequest) (entity.SubjectPatch, error) {
+	var patch entity.SubjectPatch
+
+	paths := req.GetUpdateMask().GetPaths()
+
+	if replacesAll(paths) {
+		paths = slices.Collect(maps.Keys(subjectSetters))
+	}
+
+	for _, path := range paths {
+		setter, ok := subjectSetters[path]
+
+		if !ok {
+			return entity.SubjectPatch{}, status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+		}
+
+		setter(&patch, req)
+	}
+
+	// The name may only be empty in the request when it is not updated.
+	if patch.Name != nil && *patch.Name == "" {
+		return entity.SubjectPatch{}, status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	return patch, nil
+}
+
+// reviewSetters fill a patch with the fields of the update mask.
+var reviewSetters = map[string]func(patch *entity.ReviewPatch, req *generated.UpdateReviewRequest){
+	"rating": func(patch *entity.ReviewPatch, req *generated.UpdateReviewRequest) {
+		rating := int(req.GetRating())
+		patch.Rating = &rating
+	},
+	"text": func(patch *entity.ReviewPatch, req *generated.UpdateReviewRequest) {
+		text := req.GetText()
+		patch.Text = &text
+	},
+}
+
+// reviewPatch turns an UpdateReview request into a patch. Without an update
+// mask the request replaces the whole review.
+func reviewPatch(req *generated.UpdateReviewRequest) (entity.ReviewPatch, error) {
+	var patch entity.ReviewPatch
+
+	paths := req.GetUpdateMask().GetPaths()
+
+	if replacesAll(paths) {
+		paths = slices.Collect(maps.Keys(reviewSetters))
+	}
+
+	for _, path := range paths {
+		setter, ok := reviewSetters[path]
+
+		if !ok {
+			return entity.ReviewPatch{}, status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+		}
+
+		setter(&patch, req)
+	}
+
+	return patch, nil
+}
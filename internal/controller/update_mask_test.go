@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// patchRequest decodes the body of a PATCH request into req and masks it
+// with the fields present in the body other than the id, as the gateway
+// does.
+func patchRequest[T proto.Message](t *testing.T, body string, req T) T {
+	t.Helper()
+
+	require.NoError(t, protojson.Unmarshal([]byte(body), req))
+
+	present, err := runtime.FieldMaskFromRequestBody(strings.NewReader(body), req)
+	require.NoError(t, err)
+
+	present.Paths = slices.DeleteFunc(present.GetPaths(), func(path string) bool { return path == "id" })
+	field := req.ProtoReflect().Descriptor().Fields().ByName("update_mask")
+	req.ProtoReflect().Set(field, protoreflect.ValueOfMessage(present.ProtoReflect()))
+
+	return req
+}
+
+func mask(paths ...string) *fieldmaskpb.FieldMask {
+	return &fieldmaskpb.FieldMask{Paths: paths}
+}
+
+func requireInvalidArgument(t *testing.T, err error, msgAndArgs ...any) {
+	t.Helper()
+
+	require.Equal(t, codes.InvalidArgument, status.Code(err), msgAndArgs...)
+}
+
+func TestBookPatch(t *testing.T) {
+	t.Parallel()
+
+	full := &generated.UpdateBookRequest{
+		Name:      "Dune",
+		AuthorIds: []string{"a"},
+		Isbn:      "9780441013593",
+	}
+
+	for _, paths := range [][]string{nil, {fullMask}} {
+		full.UpdateMask = mask(paths...)
+
+		patch, err := bookPatch(full)
+		require.NoError(t, err)
+		require.Equal(t, "Dune", *patch.Name)
+		require.Equal(t, entity.AuthorContributors([]string{"a"}), *patch.Contributors)
+		require.Equal(t, "9780441013593", *patch.ISBN)
+		// Fields missing from the request are cleared.
+		require.Empty(t, *patch.Publisher)
+		require.Empty(t, *patch.Tags)
+	}
+
+	// A PATCH leaves the fields missing from its body as they are.
+	patch, err := bookPatch(patchRequest(t, `{"id": "b", "publisher": "Chilton", "seriesPosition": 1}`,
+		&generated.UpdateBookRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.BookPatch{Publisher: ptr("Chilton"), SeriesPosition: ptr(1)}, patch)
+
+	// Incremental credit changes alone touch nothing else.
+	patch, err = bookPatch(&generated.UpdateBookRequest{AddAuthorIds: []string{"c"}})
+	require.NoError(t, err)
+	require.Equal(t, entity.BookPatch{AddAuthorIDs: []string{"c"}}, patch)
+
+	for name, req := range map[string]*generated.UpdateBookRequest{
+		"unknown field":           {UpdateMask: mask("title")},
+		"nested path":             {UpdateMask: mask("name.first")},
+		"wildcard with a field":   {UpdateMask: mask(fullMask, namePath)},
+		"both credits":            {UpdateMask: mask(authorIDsPath, contributorsPath)},
+		"replaced and changed":    {UpdateMask: mask(authorIDsPath), AddAuthorIds: []string{"c"}},
+		"wildcard and changed":    {UpdateMask: mask(fullMask), RemoveAuthorIds: []string{"a"}},
+		"expected version masked": {UpdateMask: mask("expected_version")},
+	} {
+		_, err = bookPatch(req)
+		requireInvalidArgument(t, err, name)
+	}
+}
+
+func TestAuthorPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := authorPatch(patchRequest(t,
+		`{"id": "a", "biography": "Wrote Dune.", "externalIds": {"isni": "0000 0001 2146 438X"}}`,
+		&generated.ChangeAuthorInfoRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.AuthorPatch{
+		Biography:         ptr("Wrote Dune."),
+		ExternalIDUpdates: map[entity.ExternalIDScheme]string{entity.ExternalIDSchemeISNI: "0000 0001 2146 438X"},
+	}, patch)
+
+	// Replacing all identifiers clears the ones missing from the request.
+	patch, err = authorPatch(&generated.ChangeAuthorInfoRequest{
+		UpdateMask:  mask("external_ids"),
+		ExternalIds: &generated.ExternalIds{Viaf: "12345"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.AuthorPatch{ExternalIDs: &entity.ExternalIDs{VIAF: "12345"}}, patch)
+
+	patch, err = authorPatch(&generated.ChangeAuthorInfoRequest{UpdateMask: mask(fullMask), Name: "Frank Herbert"})
+	require.NoError(t, err)
+	require.Equal(t, "Frank Herbert", *patch.Name)
+	require.Empty(t, *patch.Aliases)
+	require.Equal(t, entity.ExternalIDs{}, *patch.ExternalIDs)
+
+	for name, req := range map[string]*generated.ChangeAuthorInfoRequest{
+		"empty name":          {UpdateMask: mask(namePath)},
+		"empty name replaced": {},
+		"wildcard empty name": {UpdateMask: mask(fullMask)},
+		"unknown field":       {UpdateMask: mask("nationality")},
+		"unknown identifier":  {UpdateMask: mask("external_ids.orcid")},
+		"nested scalar":       {UpdateMask: mask("country.code")},
+	} {
+		_, err = authorPatch(req)
+		requireInvalidArgument(t, err, name)
+	}
+}
+
+func TestCopyPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := copyPatch(patchRequest(t, `{"id": "c", "location": "Shelf 4"}`, &generated.UpdateCopyRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyPatch{Location: ptr("Shelf 4")}, patch)
+
+	patch, err = copyPatch(&generated.UpdateCopyRequest{
+		UpdateMask: mask(fullMask),
+		Barcode:    "0001",
+		Status:     generated.CopyStatus_COPY_STATUS_AVAILABLE,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0001", *patch.Barcode)
+	require.Equal(t, entity.CopyStatusAvailable, *patch.Status)
+	require.Empty(t, *patch.Branch)
+
+	for _, req := range []*generated.UpdateCopyRequest{
+		{UpdateMask: mask("barcode")},
+		{UpdateMask: mask("shelf")},
+		{},
+	} {
+		_, err = copyPatch(req)
+		requireInvalidArgument(t, err)
+	}
+}
+
+func TestPatronPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := patronPatch(patchRequest(t, `{"id": "p", "phone": "+1 555 0100"}`, &generated.UpdatePatronRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.PatronPatch{Phone: ptr(entity.Sensitive("+1 555 0100"))}, patch)
+
+	for _, req := range []*generated.UpdatePatronRequest{
+		{UpdateMask: mask("card_number"), Name: "Ada"},
+		{UpdateMask: mask(fullMask), CardNumber: "1"},
+		{UpdateMask: mask("status")},
+	} {
+		_, err = patronPatch(req)
+		requireInvalidArgument(t, err)
+	}
+}
+
+func TestSubjectPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := subjectPatch(patchRequest(t, `{"id": "s", "parentId": ""}`, &generated.UpdateSubjectRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.SubjectPatch{ParentID: ptr("")}, patch)
+
+	_, err = subjectPatch(&generated.UpdateSubjectRequest{UpdateMask: mask(fullMask)})
+	requireInvalidArgument(t, err)
+
+	_, err = subjectPatch(&generated.UpdateSubjectRequest{UpdateMask: mask("parent")})
+	requireInvalidArgument(t, err)
+}
+
+func TestReviewPatch(t *testing.T) {
+	t.Parallel()
+
+	patch, err := reviewPatch(patchRequest(t, `{"id": "r", "text": "Dense."}`, &generated.UpdateReviewRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, entity.ReviewPatch{Text: ptr("Dense.")}, patch)
+
+	patch, err = reviewPatch(&generated.UpdateReviewRequest{UpdateMask: mask(fullMask), Rating: 4})
+	require.NoError(t, err)
+	require.Equal(t, 4, *patch.Rating)
+	require.Empty(t, *patch.Text)
+
+	_, err = reviewPatch(&generated.UpdateReviewRequest{UpdateMask: mask("stars")})
+	requireInvalidArgument(t, err)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) UpdatePatron(
+	ctx context.Context,
+	req *generated.UpdatePatronRequest,
+) (*generated.UpdatePatronResponse, error) {
+	i.logger.Info("received UpdatePatron request",
+		zap.String("id", req.GetId()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := patronPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	patron, err := i.patronsUseCase.UpdatePatron(ctx, req.GetId(), patch)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.UpdatePatronResponse{
+		Patron: toProtoPatron(patron),
+	}, nil
+}
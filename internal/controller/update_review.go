@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) UpdateReview(
+	ctx context.Context,
+	req *generated.UpdateReviewRequest,
+) (*generated.UpdateReviewResponse, error) {
+	i.logger.Info("received UpdateReview request",
+		zap.String("id", req.GetId()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := reviewPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := i.reviewsUseCase.UpdateReview(ctx, req.GetId(), patch)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.UpdateReviewResponse{
+		Review: toProtoReview(review),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) UpdateSubject(
+	ctx context.Context,
+	req *generated.UpdateSubjectRequest,
+) (*generated.UpdateSubjectResponse, error) {
+	i.logger.Info("received UpdateSubject request",
+		zap.String("id", req.GetId()),
+		zap.Strings("update_mask", req.GetUpdateMask().GetPaths()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	patch, err := subjectPatch(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := i.subjectsUseCase.UpdateSubject(ctx, req.GetId(), patch)
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.UpdateSubjectResponse{
+		Subject: toProtoSubject(subject),
+	}, nil
+}
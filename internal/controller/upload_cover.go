@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"errors"
+	"io"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errCoverInfoExpected = status.Error(codes.InvalidArgument, "the cover info must be sent in the first message only")
+
+func (i *implementation) UploadCover(server generated.Library_UploadCoverServer) error {
+	first, err := server.Recv()
+
+	if err != nil {
+		return err
+	}
+
+	info := first.GetInfo()
+
+	if info == nil {
+		return errCoverInfoExpected
+	}
+
+	i.logger.Info("received UploadCover request",
+		zap.String("book_id", info.GetBookId()),
+		zap.String("content_type", info.GetContentType()))
+
+	if err = validate(info); err != nil {
+		return err
+	}
+
+	reader := &coverReader{server: server}
+	book, err := i.booksUseCase.UploadCover(server.Context(), info.GetBookId(),
+		entity.CoverContentType(info.GetContentType()), reader)
+
+	// Errors of the stream are reported as they are, not as failures of the
+	// use case.
+	if reader.err != nil {
+		return reader.err
+	}
+
+	if err != nil {
+		return i.convertErr(err)
+	}
+
+	return server.SendAndClose(&generated.UploadCoverResponse{Book: toProtoBook(book)})
+}
+
+// coverReader reads the image from the chunks of an UploadCover stream.
+type coverReader struct {
+	server generated.Library_UploadCoverServer
+	chunk  []byte
+	err    error
+}
+
+func (c *coverReader) Read(p []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		message, err := c.server.Recv()
+
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+
+		if err == nil && message.GetInfo() != nil {
+			err = errCoverInfoExpected
+		}
+
+		if err != nil {
+			c.err = err
+			return 0, err
+		}
+
+		c.chunk = message.GetChunk()
+	}
+
+	n := copy(p, c.chunk)
+	c.chunk = c.chunk[n:]
+
+	return n, nil
+}
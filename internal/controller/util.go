@@ -1 +1,237 @@
 package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (i *implementation) convertErr(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrAuthorNotFound),
+		errors.Is(err, entity.ErrBookNotFound),
+		errors.Is(err, entity.ErrCopyNotFound),
+		errors.Is(err, entity.ErrPatronNotFound),
+		errors.Is(err, entity.ErrLoanNotFound),
+		errors.Is(err, entity.ErrHoldNotFound),
+		errors.Is(err, entity.ErrSubjectNotFound),
+		errors.Is(err, entity.ErrWorkNotFound),
+		errors.Is(err, entity.ErrSeriesNotFound),
+		errors.Is(err, entity.ErrReviewNotFound),
+		errors.Is(err, entity.ErrAPIKeyNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, entity.ErrVersionMismatch):
+		return versionMismatchStatus(err)
+	case errors.Is(err, entity.ErrExternalIDTaken),
+		errors.Is(err, entity.ErrISBNTaken),
+		errors.Is(err, entity.ErrBarcodeTaken),
+		errors.Is(err, entity.ErrCardNumberTaken),
+		errors.Is(err, entity.ErrHoldExists),
+		errors.Is(err, entity.ErrSubjectNameTaken),
+		errors.Is(err, entity.ErrReviewExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, entity.ErrInvalidDate),
+		errors.Is(err, entity.ErrInvalidLifeDates),
+		errors.Is(err, entity.ErrInvalidExternalID),
+		errors.Is(err, entity.ErrInvalidISBN),
+		errors.Is(err, entity.ErrInvalidLanguage),
+		errors.Is(err, entity.ErrInvalidContributorRole),
+		errors.Is(err, entity.ErrInvalidCopyStatus),
+		errors.Is(err, entity.ErrInvalidCopyCondition),
+		errors.Is(err, entity.ErrInvalidMembershipType),
+		errors.Is(err, entity.ErrInvalidPatronStatus),
+		errors.Is(err, entity.ErrInvalidAmount),
+		errors.Is(err, entity.ErrInvalidTag),
+		errors.Is(err, entity.ErrInvalidSeriesPosition),
+		errors.Is(err, entity.ErrCoverTooLarge),
+		errors.Is(err, entity.ErrUnsupportedContentType),
+		errors.Is(err, entity.ErrInvalidCover),
+		errors.Is(err, entity.ErrInvalidRating),
+		errors.Is(err, entity.ErrInvalidMerge):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entity.ErrPatronAlreadySuspended),
+		errors.Is(err, entity.ErrPatronNotSuspended),
+		errors.Is(err, entity.ErrCopyNotAvailable),
+		errors.Is(err, entity.ErrCopyNotOnLoan),
+		errors.Is(err, entity.ErrCopyOnLoan),
+		errors.Is(err, entity.ErrCopyHasLoans),
+		errors.Is(err, entity.ErrLoanReturned),
+		errors.Is(err, entity.ErrLoanLimitReached),
+		errors.Is(err, entity.ErrRenewalLimitReached),
+		errors.Is(err, entity.ErrPatronSuspended),
+		errors.Is(err, entity.ErrMembershipExpired),
+		errors.Is(err, entity.ErrHoldNotActive),
+		errors.Is(err, entity.ErrCopyHeld),
+		errors.Is(err, entity.ErrHoldsWaiting),
+		errors.Is(err, entity.ErrAmountExceedsBalance),
+		errors.Is(err, entity.ErrFinesOutstanding),
+		errors.Is(err, entity.ErrSubjectCycle),
+		errors.Is(err, entity.ErrSubjectInUse),
+		errors.Is(err, entity.ErrAPIKeyRevoked),
+		errors.Is(err, entity.ErrAPIKeyExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entity.ErrNotOwner):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+type validator interface {
+	ValidateAll() error
+}
+
+func validate(request validator) error {
+	if err := request.ValidateAll(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
+
+// asOf converts an optional point in time of a request.
+// A missing timestamp means the current state and is returned as nil.
+func asOf(timestamp *timestamppb.Timestamp) (*time.Time, error) {
+	var moment *time.Time
+
+	if timestamp != nil {
+		if err := timestamp.CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		converted := timestamp.AsTime()
+		moment = &converted
+	}
+
+	return moment, nil
+}
+
+func toProtoBook(book entity.Book) *generated.Book {
+	isbn10, _ := entity.ISBN10(book.ISBN)
+
+	return &generated.Book{
+		Id:              book.ID,
+		Name:            book.Name,
+		AuthorId:        book.AuthorIDs,
+		CreatedAt:       timestamppb.New(book.CreatedAt),
+		UpdatedAt:       timestamppb.New(book.UpdatedAt),
+		Version:         book.Version,
+		Isbn:            book.ISBN,
+		Isbn_10:         isbn10,
+		Publisher:       book.Publisher,
+		PublicationYear: int32(book.PublicationYear),
+		Language:        book.Language,
+		PageCount:       int32(book.PageCount),
+		Description:     book.Description,
+		Contributors:    toProtoContributors(book.Contributors),
+		SubjectIds:      book.SubjectIDs,
+		Tags:            book.Tags,
+		WorkId:          book.WorkID,
+		SeriesId:        book.SeriesID,
+		SeriesPosition:  int32(book.SeriesPosition),
+		Cover:           toProtoCover(book.Cover),
+	}
+}
+
+func toProtoCover(cover entity.Cover) *generated.Cover {
+	if cover == (entity.Cover{}) {
+		return nil
+	}
+
+	return &generated.Cover{
+		OriginalUrl: cover.OriginalURL,
+		SmallUrl:    cover.SmallURL,
+		MediumUrl:   cover.MediumURL,
+		LargeUrl:    cover.LargeURL,
+	}
+}
+
+// bookMetadataRequest is implemented by the requests carrying the
+// bibliographic metadata of a book.
+type bookMetadataRequest interface {
+	GetIsbn() string
+	GetPublisher() string
+	GetPublicationYear() int32
+	GetLanguage() string
+	GetPageCount() int32
+	GetDescription() string
+	GetSubjectIds() []string
+	GetTags() []string
+	GetWorkId() string
+	GetSeriesId() string
+	GetSeriesPosition() int32
+}
+
+func toBookMetadata(req bookMetadataRequest) entity.BookMetadata {
+	return entity.BookMetadata{
+		ISBN:            req.GetIsbn(),
+		Publisher:       req.GetPublisher(),
+		PublicationYear: int(req.GetPublicationYear()),
+		Language:        req.GetLanguage(),
+		PageCount:       int(req.GetPageCount()),
+		Description:     req.GetDescription(),
+		SubjectIDs:      req.GetSubjectIds(),
+		Tags:            req.GetTags(),
+		WorkID:          req.GetWorkId(),
+		SeriesID:        req.GetSeriesId(),
+		SeriesPosition:  int(req.GetSeriesPosition()),
+	}
+}
+
+func toProtoAuthor(author entity.Author) *generated.Author {
+	return &generated.Author{
+		Id:          author.ID,
+		Name:        author.Name,
+		Version:     author.Version,
+		Biography:   author.Biography,
+		BirthDate:   author.BirthDate,
+		DeathDate:   author.DeathDate,
+		Country:     author.Country,
+		Aliases:     author.Aliases,
+		ExternalIds: toProtoExternalIDs(author.ExternalIDs),
+	}
+}
+
+// authorProfileRequest is implemented by the requests carrying the profile
+// of an author.
+type authorProfileRequest interface {
+	GetBiography() string
+	GetBirthDate() string
+	GetDeathDate() string
+	GetCountry() string
+	GetAliases() []string
+	GetExternalIds() *generated.ExternalIds
+}
+
+func toAuthorProfile(req authorProfileRequest) entity.AuthorProfile {
+	return entity.AuthorProfile{
+		Biography: req.GetBiography(),
+		BirthDate: req.GetBirthDate(),
+		DeathDate: req.GetDeathDate(),
+		Country:   req.GetCountry(),
+		Aliases:   req.GetAliases(),
+		ExternalIDs: entity.ExternalIDs{
+			VIAF:     req.GetExternalIds().GetViaf(),
+			ISNI:     req.GetExternalIds().GetIsni(),
+			Wikidata: req.GetExternalIds().GetWikidata(),
+		},
+	}
+}
+
+func toProtoExternalIDs(ids entity.ExternalIDs) *generated.ExternalIds {
+	return &generated.ExternalIds{
+		Viaf:     ids.VIAF,
+		Isni:     ids.ISNI,
+		Wikidata: ids.Wikidata,
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"context"
+
+	generated "github.com/project/library/generated/api/library"
+	"go.uber.org/zap"
+)
+
+func (i *implementation) WaiveFine(
+	ctx context.Context,
+	req *generated.WaiveFineRequest,
+) (*generated.WaiveFineResponse, error) {
+	i.logger.Info("received WaiveFine request",
+		zap.String("patron_id", req.GetPatronId()),
+		zap.Int64("amount_cents", req.GetAmountCents()))
+
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+
+	balance, err := i.finesUseCase.WaiveFine(ctx, req.GetPatronId(), req.GetAmountCents(), req.GetReason())
+
+	if err != nil {
+		return nil, i.convertErr(err)
+	}
+
+	return &generated.WaiveFineResponse{
+		BalanceCents: balance,
+	}, nil
+}
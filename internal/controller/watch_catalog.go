@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"strconv"
+
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (i *implementation) WatchCatalog(
+	req *generated.WatchCatalogRequest,
+	server generated.Library_WatchCatalogServer,
+) error {
+	i.logger.Info("received WatchCatalog request",
+		zap.String("author_id", req.GetAuthorId()),
+		zap.String("since", req.GetSince()))
+
+	if err := validate(req); err != nil {
+		return err
+	}
+
+	var since *uint64
+
+	if req.GetSince() != "" {
+		cursor, err := strconv.ParseUint(req.GetSince(), 10, 64)
+
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		since = &cursor
+	}
+
+	err := i.catalogUseCase.WatchCatalog(server.Context(), req.GetAuthorId(), since,
+		func(event entity.CatalogEvent) error {
+			return server.Send(toProtoCatalogEvent(event))
+		})
+
+	if err != nil {
+		return i.convertErr(err)
+	}
+
+	return nil
+}
+
+func toProtoCatalogEvent(event entity.CatalogEvent) *generated.CatalogEvent {
+	result := &generated.CatalogEvent{
+		Cursor:     strconv.FormatUint(event.Cursor, 10),
+		Kind:       toProtoCatalogEventKind(event.Kind),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+
+	switch {
+	case event.Book != nil:
+		result.Entity = &generated.CatalogEvent_Book{Book: toProtoBook(*event.Book)}
+	case event.Author != nil:
+		result.Entity = &generated.CatalogEvent_Author{Author: toProtoAuthor(*event.Author)}
+	}
+
+	return result
+}
+
+func toProtoCatalogEventKind(kind entity.CatalogEventKind) generated.CatalogEvent_Kind {
+	switch kind {
+	case entity.CatalogEventCreated:
+		return generated.CatalogEvent_KIND_CREATED
+	case entity.CatalogEventUpdated:
+		return generated.CatalogEvent_KIND_UPDATED
+	case entity.CatalogEventDeleted:
+		return generated.CatalogEvent_KIND_DELETED
+	default:
+		return generated.CatalogEvent_KIND_UNSPECIFIED
+	}
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	generated "github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoWork(work entity.Work) *generated.Work {
+	return &generated.Work{
+		Id:        work.ID,
+		Title:     work.Title,
+		CreatedAt: timestamppb.New(work.CreatedAt),
+	}
+}
+
+func toProtoSeries(series entity.Series) *generated.Series {
+	return &generated.Series{
+		Id:        series.ID,
+		Name:      series.Name,
+		CreatedAt: timestamppb.New(series.CreatedAt),
+	}
+}
+
+func toProtoBooks(books []entity.Book) []*generated.Book {
+	result := make([]*generated.Book, 0, len(books))
+
+	for _, book := range books {
+		result = append(result, toProtoBook(book))
+	}
+
+	return result
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "context"
+
+// AnonymousActor is recorded for changes made without a known actor.
+const AnonymousActor = "anonymous"
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns who is making the request: the subject of the
+// authenticated principal, the actor named by the request or AnonymousActor.
+func ActorFromContext(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.Subject != "" {
+		return principal.Subject
+	}
+
+	actor, ok := ctx.Value(actorKey{}).(string)
+
+	if !ok || actor == "" {
+		return AnonymousActor
+	}
+
+	return actor
+}
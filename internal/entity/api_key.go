@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// The scopes of API keys name the method groups of the authorization
+// policy they grant.
+const (
+	APIKeyScopeRead  = "read"
+	APIKeyScopeWrite = "write"
+)
+
+// APIKey lets a service call the API without a bearer token. Only a salted
+// hash of its secret is kept.
+type APIKey struct {
+	ID     string
+	Name   string
+	Scopes []string
+	Salt   []byte
+	Hash   []byte
+
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyRevoked  = errors.New("api key is revoked")
+	ErrAPIKeyExpired  = errors.New("api key has expired")
+)
+
+// Active reports whether the key is accepted at the moment.
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// Principal returns the caller authenticated with the key.
+func (k APIKey) Principal() Principal {
+	return Principal{Subject: "api-key:" + k.ID, Scopes: k.Scopes}
+}
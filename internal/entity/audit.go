@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditOperationRegisterAuthor   AuditOperation = "RegisterAuthor"
+	AuditOperationChangeAuthorInfo AuditOperation = "ChangeAuthorInfo"
+	AuditOperationMergeAuthors     AuditOperation = "MergeAuthors"
+	AuditOperationRegisterBook     AuditOperation = "RegisterBook"
+	AuditOperationUpdateBook       AuditOperation = "UpdateBook"
+	AuditOperationUploadCover      AuditOperation = "UploadCover"
+	AuditOperationCreateSubject    AuditOperation = "CreateSubject"
+	AuditOperationUpdateSubject    AuditOperation = "UpdateSubject"
+	AuditOperationDeleteSubject    AuditOperation = "DeleteSubject"
+	AuditOperationCreateWork       AuditOperation = "CreateWork"
+	AuditOperationCreateSeries     AuditOperation = "CreateSeries"
+	AuditOperationAddCopy          AuditOperation = "AddCopy"
+	AuditOperationUpdateCopy       AuditOperation = "UpdateCopy"
+	AuditOperationDeleteCopy       AuditOperation = "DeleteCopy"
+	AuditOperationRegisterPatron   AuditOperation = "RegisterPatron"
+	AuditOperationUpdatePatron     AuditOperation = "UpdatePatron"
+	AuditOperationSuspendPatron    AuditOperation = "SuspendPatron"
+	AuditOperationReinstatePatron  AuditOperation = "ReinstatePatron"
+	AuditOperationCheckoutCopy     AuditOperation = "CheckoutCopy"
+	AuditOperationRenewLoan        AuditOperation = "RenewLoan"
+	AuditOperationReturnCopy       AuditOperation = "ReturnCopy"
+	AuditOperationPlaceHold        AuditOperation = "PlaceHold"
+	AuditOperationCancelHold       AuditOperation = "CancelHold"
+	// AuditOperationChangeHold records the changes of holds made by
+	// circulation: putting a copy aside, fulfillment and expiry.
+	AuditOperationChangeHold   AuditOperation = "ChangeHold"
+	AuditOperationPayFine      AuditOperation = "PayFine"
+	AuditOperationWaiveFine    AuditOperation = "WaiveFine"
+	AuditOperationCreateReview AuditOperation = "CreateReview"
+	AuditOperationUpdateReview AuditOperation = "UpdateReview"
+	AuditOperationDeleteReview AuditOperation = "DeleteReview"
+)
+
+type AuditEntityKind string
+
+const (
+	AuditEntityAuthor  AuditEntityKind = "author"
+	AuditEntityBook    AuditEntityKind = "book"
+	AuditEntitySubject AuditEntityKind = "subject"
+	AuditEntityWork    AuditEntityKind = "work"
+	AuditEntitySeries  AuditEntityKind = "series"
+	AuditEntityCopy    AuditEntityKind = "copy"
+	AuditEntityPatron  AuditEntityKind = "patron"
+	AuditEntityLoan    AuditEntityKind = "loan"
+	AuditEntityHold    AuditEntityKind = "hold"
+	AuditEntityReview  AuditEntityKind = "review"
+)
+
+type AuditRecord struct {
+	ID         uint64
+	Actor      string
+	Operation  AuditOperation
+	EntityKind AuditEntityKind
+	EntityID   string
+	// Before and After are JSON objects holding the changed fields only.
+	// Before is nil when the entity has been created, After is empty when
+	// it has been deleted.
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+}
@@ -1 +1,45 @@
 package entity
+
+import "errors"
+
+type Author struct {
+	ID   string
+	Name string
+	AuthorProfile
+	Version uint64
+}
+
+// AuthorProfile holds the optional details of an author. Empty strings are
+// unknown values.
+type AuthorProfile struct {
+	Biography string
+	// BirthDate and DeathDate are formatted as YYYY-MM-DD.
+	BirthDate   string
+	DeathDate   string
+	Country     string
+	Aliases     []string
+	ExternalIDs ExternalIDs
+}
+
+// AuthorPatch describes a partial author update. Nil fields are left as is.
+type AuthorPatch struct {
+	Name        *string
+	Biography   *string
+	BirthDate   *string
+	DeathDate   *string
+	Country     *string
+	Aliases     *[]string
+	ExternalIDs *ExternalIDs
+	// ExternalIDUpdates replace single identifiers, an empty one is
+	// removed. They apply on top of ExternalIDs, or else of the current
+	// identifiers.
+	ExternalIDUpdates map[ExternalIDScheme]string
+}
+
+var (
+	ErrAuthorNotFound = errors.New("author not found")
+	ErrInvalidDate    = errors.New("invalid date")
+	// ErrInvalidLifeDates is returned when an author would die before being
+	// born.
+	ErrInvalidLifeDates = errors.New("death date is before birth date")
+)
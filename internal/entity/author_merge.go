@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// AuthorMerge is the outbox payload of a merge of duplicate authors. The
+// sources no longer exist, their ids resolve to the target.
+type AuthorMerge struct {
+	TargetID  string
+	SourceIDs []string
+}
+
+// AuthorDuplicate is a pair of authors whose names are similar enough to
+// be the same person.
+type AuthorDuplicate struct {
+	Author    Author
+	Duplicate Author
+	// Similarity is the trigram similarity of the names, from 0 to 1.
+	Similarity float64
+}
+
+// ErrInvalidMerge is returned when an author is merged into itself or no
+// author is merged at all.
+var ErrInvalidMerge = errors.New("authors must be merged into another author")
+
+// NameSimilarity returns the trigram similarity of the names the way the
+// pg_trgm similarity function does: the share of the distinct trigrams of
+// their lowercased words that both names have. Word order and punctuation
+// do not matter, so "Tolstoy, Leo" is similar to "Leo Tolstoy".
+func NameSimilarity(a string, b string) float64 {
+	first, second := nameTrigrams(a), nameTrigrams(b)
+
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+
+	shared := 0
+
+	for trigram := range first {
+		if _, ok := second[trigram]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(first)+len(second)-shared)
+}
+
+func nameTrigrams(name string) map[string]struct{} {
+	trigrams := make(map[string]struct{})
+
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		// Every word is padded with two spaces in front and one behind.
+		padded := []rune("  " + word + " ")
+
+		for i := 0; i+3 <= len(padded); i++ {
+			trigrams[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+
+	return trigrams
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 1.0, NameSimilarity("Tolstoy, Leo", "Leo Tolstoy"), 1e-9)
+	// The example of the pg_trgm documentation.
+	require.InDelta(t, 4.0/11, NameSimilarity("word", "two words"), 1e-9)
+	require.Less(t, NameSimilarity("Leo Tolstoy", "Fyodor Dostoevsky"), 0.2)
+	require.InDelta(t, 0.0, NameSimilarity("", "Leo Tolstoy"), 1e-9)
+}
@@ -1 +1,71 @@
 package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type Book struct {
+	ID   string
+	Name string
+	// AuthorIDs are the sorted ids of every author credited on the book,
+	// whatever the role.
+	AuthorIDs    []string
+	Contributors []Contributor
+	BookMetadata
+	Cover     Cover
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   uint64
+}
+
+// BookMetadata holds the bibliographic details of a book. Empty strings and
+// zeros are unknown values.
+type BookMetadata struct {
+	// ISBN is a bare ISBN-13, see NormalizeISBN.
+	ISBN            string
+	Publisher       string
+	PublicationYear int
+	// Language is a canonical BCP 47 tag, see NormalizeLanguage.
+	Language    string
+	PageCount   int
+	Description string
+	// SubjectIDs are the sorted subjects the book is classified under.
+	SubjectIDs []string
+	// Tags are sorted free-form labels, see NormalizeTags.
+	Tags []string
+	// WorkID is the work the book is an edition of.
+	WorkID   string
+	SeriesID string
+	// SeriesPosition is the reading order of the book within its series,
+	// starting at 1. Zero is unnumbered.
+	SeriesPosition int
+}
+
+// BookPatch describes a partial book update. Nil fields are left as is.
+// Contributors replaces the whole credits, while RemoveAuthorIDs and then
+// AddAuthorIDs change them incrementally. Removed authors lose every role,
+// added ones are credited as authors after the current contributors. Taking
+// a book out of its series without a new SeriesPosition unnumbers it.
+type BookPatch struct {
+	Name            *string
+	Contributors    *[]Contributor
+	AddAuthorIDs    []string
+	RemoveAuthorIDs []string
+	ISBN            *string
+	Publisher       *string
+	PublicationYear *int
+	Language        *string
+	PageCount       *int
+	Description     *string
+	SubjectIDs      *[]string
+	Tags            *[]string
+	WorkID          *string
+	SeriesID        *string
+	SeriesPosition  *int
+	Cover           *Cover
+}
+
+var (
+	ErrBookNotFound = errors.New("book not found")
+)
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrBookExists is returned when a book with the same ISBN, or with the
+// same title and authors, is already in the catalog.
+var ErrBookExists = errors.New("book already exists")
+
+// DuplicateBookError is ErrBookExists naming the book already in the
+// catalog. It is ErrISBNTaken as well when the book has the same ISBN.
+type DuplicateBookError struct {
+	BookID    string
+	ISBNTaken bool
+}
+
+func (e *DuplicateBookError) Error() string {
+	return ErrBookExists.Error() + ": " + e.BookID
+}
+
+func (e *DuplicateBookError) Unwrap() []error {
+	if e.ISBNTaken {
+		return []error{ErrBookExists, ErrISBNTaken}
+	}
+
+	return []error{ErrBookExists}
+}
+
+// NormalizeTitle returns the key duplicate books are found and listed by
+// title with. Compatibility characters are replaced with their canonical
+// equivalents (NFKC), letters are lowercased, punctuation and symbols are
+// dropped and whitespace is collapsed, so "The Lord of the Rings: Part I"
+// and "the lord of the rings — part Ⅰ" are the same title.
+func NormalizeTitle(title string) string {
+	title = strings.ToLower(norm.NFKC.String(title))
+
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return ' '
+		}
+
+		return r
+	}, title)
+
+	return strings.Join(strings.Fields(title), " ")
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		title    string
+		expected string
+	}{
+		{title: "The Lord of the Rings: Part I", expected: "the lord of the rings part i"},
+		{title: "  the lord of the rings — part Ⅰ ", expected: "the lord of the rings part i"},
+		{title: "ＦＵＬＬ　ＷＩＤＴＨ", expected: "full width"},
+		{title: "Catch-22", expected: "catch 22"},
+		{title: "Война и мир!", expected: "война и мир"},
+		{title: "...", expected: ""},
+	} {
+		require.Equal(t, test.expected, NormalizeTitle(test.title), test.title)
+	}
+}
+
+func TestDuplicateBookError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &DuplicateBookError{BookID: "id"}
+
+	require.ErrorIs(t, err, ErrBookExists)
+	require.NotErrorIs(t, err, ErrISBNTaken)
+	require.Equal(t, "book already exists: id", err.Error())
+
+	err = &DuplicateBookError{BookID: "id", ISBNTaken: true}
+	require.ErrorIs(t, err, ErrBookExists)
+	require.ErrorIs(t, err, ErrISBNTaken)
+}
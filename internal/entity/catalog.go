@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type CatalogEventKind int
+
+const (
+	CatalogEventUndefined CatalogEventKind = iota
+	CatalogEventCreated
+	CatalogEventUpdated
+	CatalogEventDeleted
+)
+
+// CatalogEvent is a single entry of the catalog change feed. Exactly one of
+// Book and Author is set. AuthorIDs holds every author the event is relevant
+// to, including authors unlinked from a book by an update.
+type CatalogEvent struct {
+	Cursor     uint64
+	Kind       CatalogEventKind
+	Book       *Book
+	Author     *Author
+	AuthorIDs  []string
+	OccurredAt time.Time
+}
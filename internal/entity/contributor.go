@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+)
+
+// ContributorRole is the part an author had in a book. The values match the
+// contributor_role database type, an empty role stands for any role in
+// filters.
+type ContributorRole string
+
+const (
+	ContributorRoleAuthor      ContributorRole = "author"
+	ContributorRoleTranslator  ContributorRole = "translator"
+	ContributorRoleEditor      ContributorRole = "editor"
+	ContributorRoleIllustrator ContributorRole = "illustrator"
+)
+
+// Contributor credits an author with a role on a book.
+type Contributor struct {
+	AuthorID string          `json:"author_id"`
+	Role     ContributorRole `json:"role"`
+	// Position is the credited order, starting at 0.
+	Position int `json:"position"`
+}
+
+var ErrInvalidContributorRole = errors.New("invalid contributor role")
+
+// Valid reports whether the role is a known one.
+func (r ContributorRole) Valid() bool {
+	switch r {
+	case ContributorRoleAuthor, ContributorRoleTranslator, ContributorRoleEditor, ContributorRoleIllustrator:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuthorContributors credits the authors with the author role in the given
+// order.
+func AuthorContributors(authorIDs []string) []Contributor {
+	contributors := make([]Contributor, 0, len(authorIDs))
+
+	for _, authorID := range authorIDs {
+		contributors = append(contributors, Contributor{AuthorID: authorID, Role: ContributorRoleAuthor})
+	}
+
+	return CompactContributors(contributors)
+}
+
+// NormalizeContributors credits contributors without a role as authors and
+// compacts the list, see CompactContributors.
+func NormalizeContributors(contributors []Contributor) ([]Contributor, error) {
+	contributors = slices.Clone(contributors)
+
+	for i := range contributors {
+		if contributors[i].Role == "" {
+			contributors[i].Role = ContributorRoleAuthor
+		}
+
+		if !contributors[i].Role.Valid() {
+			return nil, ErrInvalidContributorRole
+		}
+	}
+
+	return CompactContributors(contributors), nil
+}
+
+// CompactContributors drops the repeated credits of an author in the same
+// role, keeping the first one, and numbers the positions in the order of
+// the list.
+func CompactContributors(contributors []Contributor) []Contributor {
+	compacted := make([]Contributor, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		if contributor.CreditedIn(compacted) {
+			continue
+		}
+
+		contributor.Position = len(compacted)
+		compacted = append(compacted, contributor)
+	}
+
+	return compacted
+}
+
+// CreditedIn reports whether the list credits the same author in the same
+// role.
+func (c Contributor) CreditedIn(contributors []Contributor) bool {
+	return slices.ContainsFunc(contributors, func(other Contributor) bool {
+		return other.AuthorID == c.AuthorID && other.Role == c.Role
+	})
+}
+
+// ContributorAuthorIDs returns the sorted ids of the credited authors.
+func ContributorAuthorIDs(contributors []Contributor) []string {
+	authorIDs := make([]string, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		authorIDs = append(authorIDs, contributor.AuthorID)
+	}
+
+	slices.Sort(authorIDs)
+
+	return slices.Compact(authorIDs)
+}
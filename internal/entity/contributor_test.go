@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeContributors(t *testing.T) {
+	t.Parallel()
+
+	contributors, err := NormalizeContributors([]Contributor{
+		{AuthorID: "b"},
+		{AuthorID: "a", Role: ContributorRoleTranslator, Position: 7},
+		{AuthorID: "b", Role: ContributorRoleAuthor},
+		{AuthorID: "b", Role: ContributorRoleIllustrator},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Contributor{
+		{AuthorID: "b", Role: ContributorRoleAuthor, Position: 0},
+		{AuthorID: "a", Role: ContributorRoleTranslator, Position: 1},
+		{AuthorID: "b", Role: ContributorRoleIllustrator, Position: 2},
+	}, contributors)
+	require.Equal(t, []string{"a", "b"}, ContributorAuthorIDs(contributors))
+
+	_, err = NormalizeContributors([]Contributor{{AuthorID: "a", Role: "narrator"}})
+	require.ErrorIs(t, err, ErrInvalidContributorRole)
+}
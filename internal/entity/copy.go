@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// CopyStatus is the circulation state of a copy. The values match the
+// copy_status database type.
+type CopyStatus string
+
+const (
+	CopyStatusAvailable CopyStatus = "available"
+	CopyStatusOnLoan    CopyStatus = "on_loan"
+	// CopyStatusOnHold copies wait for pickup by the patron holding them.
+	CopyStatusOnHold    CopyStatus = "on_hold"
+	CopyStatusLost      CopyStatus = "lost"
+	CopyStatusWithdrawn CopyStatus = "withdrawn"
+)
+
+// CopyCondition is the physical state of a copy. The values match the
+// copy_condition database type.
+type CopyCondition string
+
+const (
+	CopyConditionNew     CopyCondition = "new"
+	CopyConditionGood    CopyCondition = "good"
+	CopyConditionFair    CopyCondition = "fair"
+	CopyConditionPoor    CopyCondition = "poor"
+	CopyConditionDamaged CopyCondition = "damaged"
+)
+
+// Copy is a physical item of a book held by the library.
+type Copy struct {
+	ID     string
+	BookID string
+	// Barcode is unique among all copies.
+	Barcode string
+	Branch  string
+	// Location is the shelf mark of the copy within its branch.
+	Location  string
+	Condition CopyCondition
+	// AcquiredOn is a YYYY-MM-DD date, empty when unknown.
+	AcquiredOn string
+	Status     CopyStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// CopyPatch describes a partial copy update. Nil fields are left as is.
+type CopyPatch struct {
+	Barcode    *string
+	Branch     *string
+	Location   *string
+	Condition  *CopyCondition
+	AcquiredOn *string
+	Status     *CopyStatus
+}
+
+// Availability summarizes the copies of a book by status.
+type Availability struct {
+	Total     int
+	Available int
+	OnLoan    int
+	OnHold    int
+	Lost      int
+	Withdrawn int
+}
+
+var (
+	ErrCopyNotFound = errors.New("copy not found")
+	// ErrBarcodeTaken is returned when a barcode already belongs to another
+	// copy.
+	ErrBarcodeTaken         = errors.New("barcode belongs to another copy")
+	ErrInvalidCopyStatus    = errors.New("invalid copy status")
+	ErrInvalidCopyCondition = errors.New("invalid copy condition")
+)
+
+// Valid reports whether the status is a known one.
+func (s CopyStatus) Valid() bool {
+	switch s {
+	case CopyStatusAvailable, CopyStatusOnLoan, CopyStatusOnHold, CopyStatusLost, CopyStatusWithdrawn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Valid reports whether the condition is a known one.
+func (c CopyCondition) Valid() bool {
+	switch c {
+	case CopyConditionNew, CopyConditionGood, CopyConditionFair, CopyConditionPoor, CopyConditionDamaged:
+		return true
+	default:
+		return false
+	}
+}
+
+// Count adds a copy in the status to the summary.
+func (a *Availability) Count(status CopyStatus) {
+	a.Total++
+
+	switch status {
+	case CopyStatusAvailable:
+		a.Available++
+	case CopyStatusOnLoan:
+		a.OnLoan++
+	case CopyStatusOnHold:
+		a.OnHold++
+	case CopyStatusLost:
+		a.Lost++
+	case CopyStatusWithdrawn:
+		a.Withdrawn++
+	}
+}
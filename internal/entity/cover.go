@@ -0,0 +1,58 @@
+package entity
+
+import "errors"
+
+// CoverContentType values are the accepted image types of covers.
+type CoverContentType string
+
+const (
+	CoverContentTypeJPEG CoverContentType = "image/jpeg"
+	CoverContentTypePNG  CoverContentType = "image/png"
+)
+
+// Cover links the uploaded cover image of a book and its thumbnails. Empty
+// URLs mean the book has no cover.
+type Cover struct {
+	OriginalURL string `json:"original_url,omitempty"`
+	SmallURL    string `json:"small_url,omitempty"`
+	MediumURL   string `json:"medium_url,omitempty"`
+	LargeURL    string `json:"large_url,omitempty"`
+}
+
+// CoverThumbnail is a size thumbnails of covers are scaled down to, so that
+// the longer side fits MaxSide pixels.
+type CoverThumbnail struct {
+	Name    string
+	MaxSide int
+}
+
+// CoverThumbnails are generated for every cover, largest first.
+var CoverThumbnails = []CoverThumbnail{
+	{Name: "large", MaxSide: 600},
+	{Name: "medium", MaxSide: 300},
+	{Name: "small", MaxSide: 100},
+}
+
+var (
+	ErrCoverTooLarge          = errors.New("cover image is too large")
+	ErrUnsupportedContentType = errors.New("cover must be a JPEG or PNG image")
+	ErrInvalidCover           = errors.New("cover is not a valid image")
+	ErrBlobNotFound           = errors.New("blob not found")
+)
+
+// Valid reports whether covers of the content type are accepted.
+func (c CoverContentType) Valid() bool {
+	return c == CoverContentTypeJPEG || c == CoverContentTypePNG
+}
+
+// SetThumbnailURL links the thumbnail of the size from the cover.
+func (c *Cover) SetThumbnailURL(thumbnail CoverThumbnail, url string) {
+	switch thumbnail.Name {
+	case "large":
+		c.LargeURL = url
+	case "medium":
+		c.MediumURL = url
+	case "small":
+		c.SmallURL = url
+	}
+}
@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ExternalIDs identify an author in authority files.
+type ExternalIDs struct {
+	VIAF     string
+	ISNI     string
+	Wikidata string
+}
+
+type ExternalIDScheme int
+
+const (
+	ExternalIDSchemeUndefined ExternalIDScheme = iota
+	ExternalIDSchemeVIAF
+	ExternalIDSchemeISNI
+	ExternalIDSchemeWikidata
+)
+
+const (
+	isniLength   = 16
+	isniModulus  = 11
+	isniCheckTen = 'X'
+)
+
+var (
+	viafPattern     = regexp.MustCompile(`^[1-9][0-9]{0,21}$`)
+	wikidataPattern = regexp.MustCompile(`^Q[1-9][0-9]*$`)
+)
+
+var (
+	ErrInvalidExternalID = errors.New("invalid external id")
+	// ErrExternalIDTaken is returned when an external ID already belongs to
+	// another author.
+	ErrExternalIDTaken = errors.New("external id belongs to another author")
+)
+
+// With returns the identifiers with the one of the scheme replaced.
+func (ids ExternalIDs) With(scheme ExternalIDScheme, value string) ExternalIDs {
+	switch scheme {
+	case ExternalIDSchemeVIAF:
+		ids.VIAF = value
+	case ExternalIDSchemeISNI:
+		ids.ISNI = value
+	case ExternalIDSchemeWikidata:
+		ids.Wikidata = value
+	default:
+	}
+
+	return ids
+}
+
+// NormalizeExternalIDs normalizes every known identifier, see
+// NormalizeExternalID.
+func NormalizeExternalIDs(ids ExternalIDs) (ExternalIDs, error) {
+	var err error
+
+	for _, id := range []struct {
+		scheme ExternalIDScheme
+		value  *string
+	}{
+		{scheme: ExternalIDSchemeVIAF, value: &ids.VIAF},
+		{scheme: ExternalIDSchemeISNI, value: &ids.ISNI},
+		{scheme: ExternalIDSchemeWikidata, value: &ids.Wikidata},
+	} {
+		if *id.value == "" {
+			continue
+		}
+
+		if *id.value, err = NormalizeExternalID(id.scheme, *id.value); err != nil {
+			return ExternalIDs{}, err
+		}
+	}
+
+	return ids, nil
+}
+
+// NormalizeExternalID validates an identifier of the scheme and brings it to
+// the stored form.
+func NormalizeExternalID(scheme ExternalIDScheme, value string) (string, error) {
+	switch scheme {
+	case ExternalIDSchemeVIAF:
+		return matchExternalID(viafPattern, value)
+	case ExternalIDSchemeISNI:
+		return normalizeISNI(value)
+	case ExternalIDSchemeWikidata:
+		return matchExternalID(wikidataPattern, value)
+	default:
+		return "", ErrInvalidExternalID
+	}
+}
+
+func matchExternalID(pattern *regexp.Regexp, value string) (string, error) {
+	if !pattern.MatchString(value) {
+		return "", ErrInvalidExternalID
+	}
+
+	return value, nil
+}
+
+// normalizeISNI strips the spaces from an ISNI and verifies its ISO 7064
+// MOD 11-2 check character.
+func normalizeISNI(isni string) (string, error) {
+	isni = strings.ToUpper(strings.ReplaceAll(isni, " ", ""))
+
+	if len(isni) != isniLength {
+		return "", ErrInvalidExternalID
+	}
+
+	total := 0
+
+	for _, digit := range isni[:isniLength-1] {
+		if digit < '0' || digit > '9' {
+			return "", ErrInvalidExternalID
+		}
+
+		total = (total + int(digit-'0')) * 2
+	}
+
+	check := (isniModulus + 1 - total%isniModulus) % isniModulus
+	expected := byte('0' + check)
+
+	if check == 10 {
+		expected = isniCheckTen
+	}
+
+	if isni[isniLength-1] != expected {
+		return "", ErrInvalidExternalID
+	}
+
+	return isni, nil
+}
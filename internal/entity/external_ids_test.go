@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeExternalID(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		scheme   ExternalIDScheme
+		value    string
+		expected string
+		err      error
+	}{
+		{scheme: ExternalIDSchemeISNI, value: "0000 0001 2103 2683", expected: "0000000121032683"},
+		{scheme: ExternalIDSchemeISNI, value: "000000012146438x", expected: "000000012146438X"},
+		{scheme: ExternalIDSchemeISNI, value: "0000000121032684", err: ErrInvalidExternalID},
+		{scheme: ExternalIDSchemeISNI, value: "00000001210326", err: ErrInvalidExternalID},
+		{scheme: ExternalIDSchemeISNI, value: "000000012103268A", err: ErrInvalidExternalID},
+		{scheme: ExternalIDSchemeVIAF, value: "75121530", expected: "75121530"},
+		{scheme: ExternalIDSchemeVIAF, value: "0751", err: ErrInvalidExternalID},
+		{scheme: ExternalIDSchemeWikidata, value: "Q937", expected: "Q937"},
+		{scheme: ExternalIDSchemeWikidata, value: "937", err: ErrInvalidExternalID},
+		{scheme: ExternalIDSchemeUndefined, value: "1", err: ErrInvalidExternalID},
+	} {
+		normalized, err := NormalizeExternalID(tc.scheme, tc.value)
+		require.ErrorIs(t, err, tc.err, tc.value)
+		require.Equal(t, tc.expected, normalized, tc.value)
+	}
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// LedgerAccount values match the ledger_account database type. Every fines
+// ledger transaction debits one account and credits another by the same
+// amount.
+type LedgerAccount string
+
+const (
+	// LedgerAccountPatron holds what the patron owes the library.
+	LedgerAccountPatron LedgerAccount = "patron"
+	// LedgerAccountFineIncome is credited when a fine accrues.
+	LedgerAccountFineIncome LedgerAccount = "fine_income"
+	// LedgerAccountCash is debited when a patron pays.
+	LedgerAccountCash LedgerAccount = "cash"
+	// LedgerAccountWaived is debited when a librarian forgives a fine.
+	LedgerAccountWaived LedgerAccount = "waived"
+)
+
+// LedgerEntryKind values match the ledger_entry_kind database type.
+type LedgerEntryKind string
+
+const (
+	LedgerEntryAccrual LedgerEntryKind = "accrual"
+	LedgerEntryPayment LedgerEntryKind = "payment"
+	LedgerEntryWaiver  LedgerEntryKind = "waiver"
+)
+
+// LedgerTransaction moves Amount from the Credit account to the Debit one.
+// It is stored as a debit and a credit entry of the fines ledger.
+type LedgerTransaction struct {
+	ID       string
+	Kind     LedgerEntryKind
+	PatronID string
+	// LoanID is set for accruals, fines are charged per loan.
+	LoanID string
+	Debit  LedgerAccount
+	Credit LedgerAccount
+	// Amount is in cents, always positive.
+	Amount    int64
+	Note      string
+	CreatedAt time.Time
+}
+
+// FinePolicy holds the rules of overdue fines. Amounts are in cents.
+type FinePolicy struct {
+	// DailyRate is charged for every day a copy is kept past its due date.
+	DailyRate int64
+	// Cap limits the fine of a single loan, zero means no limit.
+	Cap int64
+	// MaxBalance is the balance up to which a patron may still borrow.
+	MaxBalance int64
+}
+
+var (
+	ErrInvalidAmount = errors.New("amount must be positive")
+	// ErrAmountExceedsBalance is returned when more than the patron owes
+	// is paid or waived.
+	ErrAmountExceedsBalance = errors.New("amount exceeds the balance of the patron")
+	ErrFinesOutstanding     = errors.New("patron owes fines over the limit")
+)
+
+// NewFineTransaction returns the transaction of the kind from the patron
+// account.
+func NewFineTransaction(kind LedgerEntryKind, patronID string, amount int64) LedgerTransaction {
+	transaction := LedgerTransaction{
+		Kind:     kind,
+		PatronID: patronID,
+		Debit:    LedgerAccountPatron,
+		Credit:   LedgerAccountFineIncome,
+		Amount:   amount,
+	}
+
+	switch kind {
+	case LedgerEntryPayment:
+		transaction.Debit, transaction.Credit = LedgerAccountCash, LedgerAccountPatron
+	case LedgerEntryWaiver:
+		transaction.Debit, transaction.Credit = LedgerAccountWaived, LedgerAccountPatron
+	case LedgerEntryAccrual:
+	}
+
+	return transaction
+}
+
+// Fine returns the fine of the loan at now: the daily rate for every
+// calendar day, in UTC, between the due date and the return, or now while
+// the loan is active, up to the cap.
+func (p FinePolicy) Fine(loan Loan, now time.Time) int64 {
+	end := now
+
+	if loan.ReturnedAt != nil {
+		end = *loan.ReturnedAt
+	}
+
+	days := int64(startOfDay(end).Sub(startOfDay(loan.DueAt)) / (24 * time.Hour))
+
+	if days <= 0 {
+		return 0
+	}
+
+	fine := days * p.DailyRate
+
+	if p.Cap > 0 {
+		fine = min(fine, p.Cap)
+	}
+
+	return fine
+}
+
+func startOfDay(moment time.Time) time.Time {
+	year, month, day := moment.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
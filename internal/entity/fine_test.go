@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinePolicyFine(t *testing.T) {
+	t.Parallel()
+
+	policy := FinePolicy{DailyRate: 25, Cap: 100}
+	dueAt := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+	returnedAt := dueAt.Add(30 * time.Hour)
+
+	tests := []struct {
+		name string
+		loan Loan
+		now  time.Time
+		want int64
+	}{
+		{"not due", Loan{DueAt: dueAt}, dueAt.Add(-time.Hour), 0},
+		{"due day", Loan{DueAt: dueAt}, dueAt.Add(5 * time.Hour), 0},
+		{"next day", Loan{DueAt: dueAt}, dueAt.Add(7 * time.Hour), 25},
+		{"capped", Loan{DueAt: dueAt}, dueAt.Add(10 * 24 * time.Hour), 100},
+		{"returned", Loan{DueAt: dueAt, ReturnedAt: &returnedAt}, dueAt.Add(10 * 24 * time.Hour), 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, test.want, policy.Fine(test.loan, test.now))
+		})
+	}
+
+	uncapped := FinePolicy{DailyRate: 25}
+	require.Equal(t, int64(250), uncapped.Fine(Loan{DueAt: dueAt}, dueAt.Add(10*24*time.Hour)))
+}
+
+func TestNewFineTransaction(t *testing.T) {
+	t.Parallel()
+
+	accrual := NewFineTransaction(LedgerEntryAccrual, "p", 10)
+	require.Equal(t, LedgerAccountPatron, accrual.Debit)
+	require.Equal(t, LedgerAccountFineIncome, accrual.Credit)
+
+	payment := NewFineTransaction(LedgerEntryPayment, "p", 10)
+	require.Equal(t, LedgerAccountCash, payment.Debit)
+	require.Equal(t, LedgerAccountPatron, payment.Credit)
+
+	waiver := NewFineTransaction(LedgerEntryWaiver, "p", 10)
+	require.Equal(t, LedgerAccountWaived, waiver.Debit)
+	require.Equal(t, LedgerAccountPatron, waiver.Credit)
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// HoldStatus values match the hold_status database type.
+type HoldStatus string
+
+const (
+	// HoldStatusWaiting holds are queued for a copy of the book.
+	HoldStatusWaiting HoldStatus = "waiting"
+	// HoldStatusReady holds have a copy put aside for pickup.
+	HoldStatusReady     HoldStatus = "ready"
+	HoldStatusFulfilled HoldStatus = "fulfilled"
+	HoldStatusCancelled HoldStatus = "cancelled"
+	// HoldStatusExpired holds were not picked up in time.
+	HoldStatusExpired HoldStatus = "expired"
+)
+
+// Hold is a request of a patron to borrow the next copy of a book that
+// becomes available. Holds of a book are served first come, first served.
+type Hold struct {
+	ID       string
+	BookID   string
+	PatronID string
+	Status   HoldStatus
+	// CopyID is the copy put aside for the hold, empty while it waits.
+	CopyID   string
+	PlacedAt time.Time
+	// PickupBy is set once a copy is put aside for the hold.
+	PickupBy *time.Time
+	// ClosedAt is set once the hold is no longer active.
+	ClosedAt *time.Time
+}
+
+// HoldFilter selects holds, empty fields match any value.
+type HoldFilter struct {
+	BookID   string
+	PatronID string
+	// ActiveOnly keeps the waiting and ready holds.
+	ActiveOnly bool
+}
+
+// HoldEventKind values name the hold state changes sent through the outbox.
+type HoldEventKind string
+
+const (
+	HoldEventPlaced    HoldEventKind = "placed"
+	HoldEventReady     HoldEventKind = "ready"
+	HoldEventFulfilled HoldEventKind = "fulfilled"
+	HoldEventCancelled HoldEventKind = "cancelled"
+	HoldEventExpired   HoldEventKind = "expired"
+)
+
+// HoldEvent is the outbox payload of a hold state change.
+type HoldEvent struct {
+	Kind HoldEventKind
+	Hold Hold
+}
+
+var (
+	ErrHoldNotFound = errors.New("hold not found")
+	// ErrHoldExists is returned when the patron already has an active hold
+	// of the book.
+	ErrHoldExists    = errors.New("patron already holds the book")
+	ErrHoldNotActive = errors.New("hold is no longer active")
+	// ErrCopyHeld is returned when a copy put aside for a hold is lent to
+	// another patron, or changed other than by circulation.
+	ErrCopyHeld = errors.New("copy is held for a patron")
+	// ErrHoldsWaiting is returned when a loan of a book other patrons wait
+	// for is renewed.
+	ErrHoldsWaiting = errors.New("other patrons wait for the book")
+)
+
+// Active reports whether the hold is waiting or ready.
+func (h Hold) Active() bool {
+	return h.Status == HoldStatusWaiting || h.Status == HoldStatusReady
+}
+
+// Matches reports whether the filter selects the hold.
+func (f HoldFilter) Matches(hold Hold) bool {
+	return (f.BookID == "" || f.BookID == hold.BookID) &&
+		(f.PatronID == "" || f.PatronID == hold.PatronID) &&
+		(!f.ActiveOnly || hold.Active())
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// IdempotencyRecord remembers the response to a request made with an
+// idempotency key, so a retry of the request gets it again instead of
+// repeating the change.
+type IdempotencyRecord struct {
+	Key string
+	// Fingerprint identifies the request the key was first used with.
+	Fingerprint []byte
+	// Token identifies the reservation of the key, a retry taking over a
+	// stale reservation gets another one.
+	Token string
+	// Response is set once the request has completed.
+	Response  []byte
+	Completed bool
+	ExpiresAt time.Time
+}
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is used with a request
+	// other than the one it was first used with.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with another request")
+	// ErrIdempotencyKeyInUse is returned while the first request with the
+	// key is still running.
+	ErrIdempotencyKeyInUse = errors.New("request with the idempotency key is in progress")
+)
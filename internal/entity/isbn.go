@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	isbn10Length  = 10
+	isbn13Length  = 13
+	isbn10Modulus = 11
+	isbn13Modulus = 10
+	isbn13Prefix  = "978"
+)
+
+var (
+	ErrInvalidISBN = errors.New("invalid ISBN")
+	// ErrISBNTaken is returned when an ISBN already belongs to another book.
+	ErrISBNTaken = errors.New("ISBN belongs to another book")
+)
+
+// NormalizeISBN verifies the check digit of an ISBN-10 or ISBN-13, written
+// with or without hyphens and spaces, and returns it as a bare ISBN-13.
+func NormalizeISBN(isbn string) (string, error) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+
+	switch len(isbn) {
+	case isbn10Length:
+		if !validISBN10(isbn) {
+			return "", ErrInvalidISBN
+		}
+
+		body := isbn13Prefix + isbn[:isbn10Length-1]
+
+		return body + isbn13CheckDigit(body), nil
+	case isbn13Length:
+		if !isDigits(isbn) || isbn13CheckDigit(isbn[:isbn13Length-1]) != isbn[isbn13Length-1:] {
+			return "", ErrInvalidISBN
+		}
+
+		return isbn, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// ISBN10 converts a normalized ISBN-13 to the ISBN-10 form. Only ISBNs with
+// the 978 prefix have one.
+func ISBN10(isbn13 string) (string, bool) {
+	if len(isbn13) != isbn13Length || !strings.HasPrefix(isbn13, isbn13Prefix) {
+		return "", false
+	}
+
+	body := isbn13[len(isbn13Prefix) : isbn13Length-1]
+
+	return body + isbn10CheckDigit(body), true
+}
+
+func validISBN10(isbn string) bool {
+	body := isbn[:isbn10Length-1]
+
+	return isDigits(body) && isbn10CheckDigit(body) == isbn[isbn10Length-1:]
+}
+
+func isbn10CheckDigit(body string) string {
+	sum := 0
+
+	for i, digit := range body {
+		sum += (isbn10Length - i) * int(digit-'0')
+	}
+
+	check := (isbn10Modulus - sum%isbn10Modulus) % isbn10Modulus
+
+	if check == isbn10Length {
+		return "X"
+	}
+
+	return string(rune('0' + check))
+}
+
+func isbn13CheckDigit(body string) string {
+	sum := 0
+
+	for i, digit := range body {
+		weight := 1
+
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += weight * int(digit-'0')
+	}
+
+	return string(rune('0' + (isbn13Modulus-sum%isbn13Modulus)%isbn13Modulus))
+}
+
+func isDigits(value string) bool {
+	for _, digit := range value {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+
+	return true
+}
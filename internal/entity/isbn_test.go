@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		isbn     string
+		expected string
+		err      error
+	}{
+		{isbn: "978-0-306-40615-7", expected: "9780306406157"},
+		{isbn: "0-306-40615-2", expected: "9780306406157"},
+		{isbn: "0 8044 2957 x", expected: "9780804429573"},
+		{isbn: "979-10-90636-07-1", expected: "9791090636071"},
+		{isbn: "978-0-306-40615-8", err: ErrInvalidISBN},
+		{isbn: "0-306-40615-3", err: ErrInvalidISBN},
+		{isbn: "X-306-40615-2", err: ErrInvalidISBN},
+		{isbn: "12345", err: ErrInvalidISBN},
+	} {
+		normalized, err := NormalizeISBN(tc.isbn)
+		require.ErrorIs(t, err, tc.err, tc.isbn)
+		require.Equal(t, tc.expected, normalized, tc.isbn)
+	}
+}
+
+func TestISBN10(t *testing.T) {
+	t.Parallel()
+
+	isbn10, ok := ISBN10("9780804429573")
+	require.True(t, ok)
+	require.Equal(t, "080442957X", isbn10)
+
+	_, ok = ISBN10("9791090636071")
+	require.False(t, ok)
+}
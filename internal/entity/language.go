@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"errors"
+
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidLanguage = errors.New("invalid language tag")
+
+// NormalizeLanguage validates a BCP 47 language tag and returns it in the
+// canonical form, e.g. "en-US" for "EN-us".
+func NormalizeLanguage(tag string) (string, error) {
+	parsed, err := language.Parse(tag)
+
+	if err != nil {
+		return "", ErrInvalidLanguage
+	}
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Loan is a copy lent to a patron. At most one loan of a copy is active, that
+// is not returned, at a time.
+type Loan struct {
+	ID           string
+	CopyID       string
+	PatronID     string
+	CheckedOutAt time.Time
+	DueAt        time.Time
+	// Renewals counts how many times the due date has been moved.
+	Renewals int
+	// ReturnedAt is nil while the loan is active.
+	ReturnedAt *time.Time
+}
+
+// LoanPolicy holds the circulation rules.
+type LoanPolicy struct {
+	// Periods maps membership types to the time a copy is lent for, on
+	// checkout and on every renewal.
+	Periods map[MembershipType]time.Duration
+	// MaxActiveLoans limits the loans a patron may have at once.
+	MaxActiveLoans int
+	// MaxRenewals limits how many times a loan may be renewed.
+	MaxRenewals int
+	// PickupWindow is the time a copy put aside for a hold waits for the
+	// patron.
+	PickupWindow time.Duration
+	Fines        FinePolicy
+}
+
+// LoanEventKind values name the loan state changes sent through the outbox.
+type LoanEventKind string
+
+const (
+	LoanEventCheckedOut LoanEventKind = "checked_out"
+	LoanEventRenewed    LoanEventKind = "renewed"
+	LoanEventReturned   LoanEventKind = "returned"
+)
+
+// LoanEvent is the outbox payload of a loan state change.
+type LoanEvent struct {
+	Kind LoanEventKind
+	Loan Loan
+}
+
+var (
+	ErrLoanNotFound = errors.New("loan not found")
+	// ErrCopyNotAvailable is returned on checkout of a copy that is on
+	// loan, lost or withdrawn.
+	ErrCopyNotAvailable = errors.New("copy is not available for loan")
+	ErrCopyNotOnLoan    = errors.New("copy is not on loan")
+	// ErrCopyOnLoan is returned when the status of a copy on loan is
+	// changed other than by returning it, or when it is deleted.
+	ErrCopyOnLoan = errors.New("copy is on loan")
+	// ErrCopyHasLoans is returned when a copy that has ever been lent or
+	// held is deleted, such copies are withdrawn instead.
+	ErrCopyHasLoans        = errors.New("copy has been in circulation, withdraw it instead")
+	ErrLoanReturned        = errors.New("loan has been returned")
+	ErrLoanLimitReached    = errors.New("patron has reached the limit of active loans")
+	ErrRenewalLimitReached = errors.New("loan has reached the limit of renewals")
+	ErrPatronSuspended     = errors.New("patron is suspended")
+	ErrMembershipExpired   = errors.New("membership has expired")
+)
+
+// Active reports whether the loan has not been returned.
+func (l Loan) Active() bool {
+	return l.ReturnedAt == nil
+}
+
+// Period returns the loan period of the membership type, falling back to
+// the one of the standard membership.
+func (p LoanPolicy) Period(membershipType MembershipType) time.Duration {
+	if period, ok := p.Periods[membershipType]; ok {
+		return period
+	}
+
+	return p.Periods[MembershipTypeStandard]
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// MembershipType decides the borrowing terms of a patron. The values match
+// the membership_type database type.
+type MembershipType string
+
+const (
+	MembershipTypeStandard MembershipType = "standard"
+	MembershipTypeStudent  MembershipType = "student"
+	MembershipTypeSenior   MembershipType = "senior"
+	MembershipTypeStaff    MembershipType = "staff"
+)
+
+// PatronStatus values match the patron_status database type.
+type PatronStatus string
+
+const (
+	PatronStatusActive    PatronStatus = "active"
+	PatronStatusSuspended PatronStatus = "suspended"
+)
+
+// Patron is a member of the library who may borrow copies.
+type Patron struct {
+	ID string
+	// CardNumber is unique among all patrons.
+	CardNumber string
+	Name       Sensitive
+	PatronContact
+	MembershipType MembershipType
+	// ExpiresOn is the YYYY-MM-DD date the membership ends on, empty when
+	// it does not expire.
+	ExpiresOn        string
+	Status           PatronStatus
+	SuspensionReason string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PatronContact holds the contact details of a patron, empty ones are
+// unknown.
+type PatronContact struct {
+	Email   Sensitive
+	Phone   Sensitive
+	Address Sensitive
+}
+
+// PatronPatch describes a partial patron update. Nil fields are left as is.
+type PatronPatch struct {
+	CardNumber       *string
+	Name             *Sensitive
+	Email            *Sensitive
+	Phone            *Sensitive
+	Address          *Sensitive
+	MembershipType   *MembershipType
+	ExpiresOn        *string
+	Status           *PatronStatus
+	SuspensionReason *string
+}
+
+// PatronFilter selects patrons, empty fields match any value.
+type PatronFilter struct {
+	Status         PatronStatus
+	MembershipType MembershipType
+}
+
+var (
+	ErrPatronNotFound = errors.New("patron not found")
+	// ErrCardNumberTaken is returned when a card number already belongs to
+	// another patron.
+	ErrCardNumberTaken        = errors.New("card number belongs to another patron")
+	ErrInvalidMembershipType  = errors.New("invalid membership type")
+	ErrInvalidPatronStatus    = errors.New("invalid patron status")
+	ErrPatronAlreadySuspended = errors.New("patron is already suspended")
+	ErrPatronNotSuspended     = errors.New("patron is not suspended")
+)
+
+// Valid reports whether the membership type is a known one.
+func (m MembershipType) Valid() bool {
+	switch m {
+	case MembershipTypeStandard, MembershipTypeStudent, MembershipTypeSenior, MembershipTypeStaff:
+		return true
+	default:
+		return false
+	}
+}
+
+// Valid reports whether the status is a known one.
+func (s PatronStatus) Valid() bool {
+	return s == PatronStatusActive || s == PatronStatusSuspended
+}
+
+// Matches reports whether the filter selects the patron.
+func (f PatronFilter) Matches(patron Patron) bool {
+	return (f.Status == "" || f.Status == patron.Status) &&
+		(f.MembershipType == "" || f.MembershipType == patron.MembershipType)
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"context"
+	"errors"
+)
+
+// Principal is the authenticated caller, the subject of its bearer token or
+// its API key.
+type Principal struct {
+	Subject string
+	// Roles are granted to the caller by the issuer of the token.
+	Roles []string
+	// Scopes are the method groups granted to an API key.
+	Scopes []string
+	// Claims are all the claims of the token, registered ones included.
+	Claims map[string]any
+}
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid bearer token")
+	// ErrVerificationKeyNotFound is returned when a token is signed with a
+	// key that is not in the key set.
+	ErrVerificationKeyNotFound = errors.New("verification key not found")
+	// ErrPermissionDenied is returned when none of the roles of the caller
+	// grants it the method.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNotOwner is returned when the caller is granted the method on its
+	// own records only and the record is another patron's.
+	ErrNotOwner = errors.New("record belongs to another patron")
+)
+
+type (
+	principalKey  struct{}
+	ownRecordsKey struct{}
+)
+
+// WithPrincipal puts the authenticated caller into the context. Its subject
+// becomes the actor of the request.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+
+	return principal, ok
+}
+
+// WithOwnRecordsOnly marks the request as granted its method on the records
+// of the principal only.
+func WithOwnRecordsOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ownRecordsKey{}, true)
+}
+
+// CheckOwner fails with ErrNotOwner when the request is granted its method
+// on the records of the principal only and the patron is not the subject
+// of the principal.
+func CheckOwner(ctx context.Context, patronID string) error {
+	if own, _ := ctx.Value(ownRecordsKey{}).(bool); !own {
+		return nil
+	}
+
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.Subject == patronID {
+		return nil
+	}
+
+	return ErrNotOwner
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// Review is the rating a patron gave a book, with an optional text. A patron
+// reviews a book at most once.
+type Review struct {
+	ID        string
+	BookID    string
+	PatronID  string
+	Rating    int
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReviewPatch describes a partial review update, nil fields are left as is.
+type ReviewPatch struct {
+	Rating *int
+	Text   *string
+}
+
+// ReviewFilter selects reviews, empty fields match any value.
+type ReviewFilter struct {
+	BookID   string
+	PatronID string
+}
+
+// BookRating aggregates the ratings of the reviews of a book.
+type BookRating struct {
+	Count int
+	Sum   int
+}
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewExists is returned when the patron has reviewed the book
+	// already.
+	ErrReviewExists  = errors.New("patron has already reviewed the book")
+	ErrInvalidRating = errors.New("rating must be between 1 and 5")
+)
+
+func CheckRating(rating int) error {
+	if rating < MinRating || rating > MaxRating {
+		return ErrInvalidRating
+	}
+
+	return nil
+}
+
+// Matches reports whether the filter selects the review.
+func (f ReviewFilter) Matches(review Review) bool {
+	return (f.BookID == "" || review.BookID == f.BookID) &&
+		(f.PatronID == "" || review.PatronID == f.PatronID)
+}
+
+// Average returns the mean rating, zero when the book has no reviews.
+func (r BookRating) Average() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+
+	return float64(r.Sum) / float64(r.Count)
+}
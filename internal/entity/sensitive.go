@@ -0,0 +1,32 @@
+package entity
+
+import "encoding/json"
+
+const redacted = "[REDACTED]"
+
+// Sensitive is personal data. It is redacted when formatted or marshaled to
+// JSON, so it stays out of logs, audit records and outbox payloads unless it
+// is read with Reveal. Changes of redacted values are not visible in audit
+// records either.
+type Sensitive string
+
+// Reveal returns the value itself.
+func (s Sensitive) Reveal() string {
+	return string(s)
+}
+
+func (s Sensitive) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func (s Sensitive) GoString() string {
+	return s.String()
+}
+
+func (s Sensitive) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
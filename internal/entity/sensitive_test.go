@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitiveIsRedacted(t *testing.T) {
+	t.Parallel()
+
+	patron := Patron{ID: "id", Name: "Jane Doe", PatronContact: PatronContact{Email: "jane@example.com"}}
+
+	serialized, err := json.Marshal(patron)
+	require.NoError(t, err)
+	require.NotContains(t, string(serialized), "Jane")
+	require.NotContains(t, string(serialized), "jane@example.com")
+	require.Contains(t, string(serialized), `"Phone":""`)
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q"} {
+		require.NotContains(t, fmt.Sprintf(format, patron), "Jane", format)
+	}
+
+	require.Equal(t, "Jane Doe", patron.Name.Reveal())
+}
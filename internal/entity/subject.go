@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxTagLength limits the length of a tag in bytes.
+const maxTagLength = 64
+
+// Subject is a node of the subject taxonomy, e.g. Science Fiction under
+// Fiction.
+type Subject struct {
+	ID   string
+	Name string
+	// ParentID is empty for top-level subjects.
+	ParentID  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SubjectPatch describes a partial subject update. Nil fields are left as is,
+// an empty ParentID moves the subject to the top level.
+type SubjectPatch struct {
+	Name     *string
+	ParentID *string
+}
+
+// BookFilter selects books, empty fields match any book.
+type BookFilter struct {
+	// SubjectID selects the books classified under the subject or any of
+	// its descendants.
+	SubjectID string
+	Tag       string
+	// Title selects the books whose title normalizes to it, see
+	// NormalizeTitle.
+	Title string
+}
+
+// TagCount is the number of books carrying a tag.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+var (
+	ErrSubjectNotFound = errors.New("subject not found")
+	// ErrSubjectNameTaken is returned when a sibling of the subject has the
+	// same name.
+	ErrSubjectNameTaken = errors.New("subject name is taken under the parent")
+	ErrSubjectCycle     = errors.New("subject can not be moved under itself")
+	// ErrSubjectInUse is returned when a subject with subtopics or books is
+	// deleted.
+	ErrSubjectInUse = errors.New("subject has subtopics or books")
+	ErrInvalidTag   = errors.New("invalid tag")
+)
+
+// NormalizeTag lowercases the tag and collapses its whitespace.
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(tag), " "))
+
+	if normalized == "" || len(normalized) > maxTagLength {
+		return "", ErrInvalidTag
+	}
+
+	return normalized, nil
+}
+
+// NormalizeTags normalizes every tag and returns them sorted, without
+// duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		value, err := NormalizeTag(tag)
+
+		if err != nil {
+			return nil, err
+		}
+
+		normalized = append(normalized, value)
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized), nil
+}
+
+// NormalizeSubjectIDs returns the ids sorted, without duplicates.
+func NormalizeSubjectIDs(subjectIDs []string) []string {
+	normalized := append(make([]string, 0, len(subjectIDs)), subjectIDs...)
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
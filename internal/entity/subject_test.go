@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	t.Parallel()
+
+	tags, err := NormalizeTags([]string{" Space  Opera", "classics", "space opera\t", "CLASSICS"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"classics", "space opera"}, tags)
+
+	tags, err = NormalizeTags(nil)
+	require.NoError(t, err)
+	require.NotNil(t, tags)
+	require.Empty(t, tags)
+
+	_, err = NormalizeTags([]string{"fine", "  "})
+	require.ErrorIs(t, err, ErrInvalidTag)
+
+	_, err = NormalizeTag(strings.Repeat("a", maxTagLength+1))
+	require.ErrorIs(t, err, ErrInvalidTag)
+}
@@ -0,0 +1,7 @@
+package entity
+
+import "errors"
+
+var (
+	ErrVersionMismatch = errors.New("version mismatch")
+)
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Work is the creation the books of the catalog are editions or translations
+// of, e.g. War and Peace as opposed to a particular printing of it.
+type Work struct {
+	ID        string
+	Title     string
+	CreatedAt time.Time
+}
+
+// Series groups books meant to be read in order.
+type Series struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+var (
+	ErrWorkNotFound   = errors.New("work not found")
+	ErrSeriesNotFound = errors.New("series not found")
+	// ErrInvalidSeriesPosition is returned for negative positions and for
+	// positions of books outside a series.
+	ErrInvalidSeriesPosition = errors.New("invalid series position")
+)
+
+// CheckSeriesPosition fails with ErrInvalidSeriesPosition unless the book is
+// numbered within its series or not numbered at all.
+func (m BookMetadata) CheckSeriesPosition() error {
+	if m.SeriesPosition < 0 || (m.SeriesPosition > 0 && m.SeriesID == "") {
+		return ErrInvalidSeriesPosition
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	secretSize = 32
+	saltSize   = 16
+	// lastUsedResolution limits the writes recording the use of a busy key.
+	lastUsedResolution = time.Minute
+)
+
+var errMalformedKey = errors.New("malformed api key")
+
+type APIKeys interface {
+	// Create issues a key with the scopes that expires at the moment. The
+	// returned secret is not stored, only its salted hash is.
+	Create(ctx context.Context, name string, scopes []string, expiresAt time.Time) (entity.APIKey, string, error)
+	// List returns the keys oldest first.
+	List(ctx context.Context, includeRevoked bool) ([]entity.APIKey, error)
+	// Rotate replaces the secret of the key, the previous one stops working
+	// at once. A non-nil expiresAt replaces the expiry of the key.
+	Rotate(ctx context.Context, keyID string, expiresAt *time.Time) (entity.APIKey, string, error)
+	Revoke(ctx context.Context, keyID string) (entity.APIKey, error)
+	// Authenticate returns the principal of the key sent by a caller. It
+	// fails with entity.ErrUnauthenticated for a key that is unknown,
+	// revoked or expired.
+	Authenticate(ctx context.Context, secret string) (entity.Principal, error)
+}
+
+var _ APIKeys = (*apiKeysImpl)(nil)
+
+type apiKeysImpl struct {
+	logger            *zap.Logger
+	apiKeysRepository repository.APIKeysRepository
+}
+
+func New(logger *zap.Logger, apiKeysRepository repository.APIKeysRepository) *apiKeysImpl {
+	return &apiKeysImpl{
+		logger:            logger,
+		apiKeysRepository: apiKeysRepository,
+	}
+}
+
+func (a *apiKeysImpl) Create(
+	ctx context.Context,
+	name string,
+	scopes []string,
+	expiresAt time.Time,
+) (entity.APIKey, string, error) {
+	secret, salt, hash, err := newSecret()
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	key, err := a.apiKeysRepository.CreateAPIKey(ctx, entity.APIKey{
+		Name:      name,
+		Scopes:    scopes,
+		Salt:      salt,
+		Hash:      hash,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	})
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	a.logger.Info("api key created",
+		zap.String("id", key.ID),
+		zap.Strings("scopes", key.Scopes),
+		zap.String("actor", entity.ActorFromContext(ctx)))
+
+	return key, formatKey(key.ID, secret), nil
+}
+
+func (a *apiKeysImpl) List(ctx context.Context, includeRevoked bool) ([]entity.APIKey, error) {
+	return a.apiKeysRepository.ListAPIKeys(ctx, includeRevoked)
+}
+
+func (a *apiKeysImpl) Rotate(ctx context.Context, keyID string, expiresAt *time.Time) (entity.APIKey, string, error) {
+	key, err := a.apiKeysRepository.GetAPIKey(ctx, keyID)
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	if expiresAt != nil {
+		key.ExpiresAt = *expiresAt
+	}
+
+	if !key.ExpiresAt.After(time.Now()) {
+		return entity.APIKey{}, "", entity.ErrAPIKeyExpired
+	}
+
+	secret, salt, hash, err := newSecret()
+
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	key.Salt, key.Hash = salt, hash
+
+	if key, err = a.apiKeysRepository.RotateAPIKey(ctx, key); err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	a.logger.Info("api key rotated", zap.String("id", key.ID), zap.String("actor", entity.ActorFromContext(ctx)))
+
+	return key, formatKey(key.ID, secret), nil
+}
+
+func (a *apiKeysImpl) Revoke(ctx context.Context, keyID string) (entity.APIKey, error) {
+	key, err := a.apiKeysRepository.RevokeAPIKey(ctx, keyID, time.Now().UTC())
+
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	a.logger.Info("api key revoked", zap.String("id", key.ID), zap.String("actor", entity.ActorFromContext(ctx)))
+
+	return key, nil
+}
+
+func (a *apiKeysImpl) Authenticate(ctx context.Context, secret string) (entity.Principal, error) {
+	keyID, encoded, found := strings.Cut(secret, ".")
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if !found || err != nil || uuid.Validate(keyID) != nil {
+		return entity.Principal{}, fmt.Errorf("%w: %w", entity.ErrUnauthenticated, errMalformedKey)
+	}
+
+	key, err := a.apiKeysRepository.GetAPIKey(ctx, keyID)
+
+	switch {
+	case errors.Is(err, entity.ErrAPIKeyNotFound):
+		return entity.Principal{}, fmt.Errorf("%w: %w", entity.ErrUnauthenticated, err)
+	case err != nil:
+		return entity.Principal{}, err
+	}
+
+	now := time.Now().UTC()
+
+	if subtle.ConstantTimeCompare(hashSecret(key.Salt, decoded), key.Hash) != 1 || !key.Active(now) {
+		return entity.Principal{}, fmt.Errorf("%w: api key is invalid, revoked or expired", entity.ErrUnauthenticated)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// A failure to record the use does not fail the request.
+		if err = a.apiKeysRepository.TouchAPIKey(ctx, key.ID, now); err != nil {
+			a.logger.Error("can not record api key use", zap.String("id", key.ID), zap.Error(err))
+		}
+	}
+
+	return key.Principal(), nil
+}
+
+// newSecret returns a random secret with its salt and salted hash. The
+// secret has enough entropy for a fast hash to be safe.
+func newSecret() (secret []byte, salt []byte, hash []byte, err error) {
+	secret, salt = make([]byte, secretSize), make([]byte, saltSize)
+
+	if _, err = rand.Read(secret); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if _, err = rand.Read(salt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return secret, salt, hashSecret(salt, secret), nil
+}
+
+func hashSecret(salt []byte, secret []byte) []byte {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write(secret)
+
+	return hash.Sum(nil)
+}
+
+// formatKey returns the key sent by callers: the id of the key to look it up
+// by and the secret.
+func formatKey(keyID string, secret []byte) string {
+	return keyID + "." + base64.RawURLEncoding.EncodeToString(secret)
+}
@@ -0,0 +1,111 @@
+package apikey
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCreateAndAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	keys := New(zap.NewNop(), repo)
+
+	key, secret, err := keys.Create(ctx, "indexer", []string{entity.APIKeyScopeRead}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NotEmpty(t, key.ID)
+	require.NotContains(t, string(key.Hash), secret)
+
+	principal, err := keys.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	require.Equal(t, "api-key:"+key.ID, principal.Subject)
+	require.Equal(t, []string{entity.APIKeyScopeRead}, principal.Scopes)
+
+	stored, err := repo.GetAPIKey(ctx, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastUsedAt)
+
+	keyID, _, _ := strings.Cut(secret, ".")
+
+	for _, invalid := range []string{"", "garbage", keyID + ".", keyID + ".AAAA", "not-a-uuid.AAAA",
+		"00000000-0000-0000-0000-000000000000.AAAA"} {
+		_, err = keys.Authenticate(ctx, invalid)
+		require.ErrorIs(t, err, entity.ErrUnauthenticated, invalid)
+	}
+}
+
+func TestRotateAndRevoke(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	keys := New(zap.NewNop(), repository.NewInMemoryRepository())
+
+	key, secret, err := keys.Create(ctx, "indexer", []string{entity.APIKeyScopeRead}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(2 * time.Hour)
+	rotated, rotatedSecret, err := keys.Rotate(ctx, key.ID, &expiresAt)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, rotated.ID)
+	require.True(t, expiresAt.Equal(rotated.ExpiresAt))
+	require.NotEqual(t, secret, rotatedSecret)
+
+	_, err = keys.Authenticate(ctx, secret)
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	_, err = keys.Authenticate(ctx, rotatedSecret)
+	require.NoError(t, err)
+
+	revoked, err := keys.Revoke(ctx, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, revoked.RevokedAt)
+
+	_, err = keys.Authenticate(ctx, rotatedSecret)
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	_, _, err = keys.Rotate(ctx, key.ID, nil)
+	require.ErrorIs(t, err, entity.ErrAPIKeyRevoked)
+
+	_, _, err = keys.Rotate(ctx, "00000000-0000-0000-0000-000000000000", nil)
+	require.ErrorIs(t, err, entity.ErrAPIKeyNotFound)
+
+	listed, err := keys.List(ctx, false)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	listed, err = keys.List(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+}
+
+func TestExpiredKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	keys := New(zap.NewNop(), repository.NewInMemoryRepository())
+
+	key, secret, err := keys.Create(ctx, "indexer", []string{entity.APIKeyScopeWrite}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = keys.Authenticate(ctx, secret)
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	// An expired key comes back only with a new expiry.
+	_, _, err = keys.Rotate(ctx, key.ID, nil)
+	require.ErrorIs(t, err, entity.ErrAPIKeyExpired)
+
+	expiresAt := time.Now().Add(time.Hour)
+	_, secret, err = keys.Rotate(ctx, key.ID, &expiresAt)
+	require.NoError(t, err)
+
+	_, err = keys.Authenticate(ctx, secret)
+	require.NoError(t, err)
+}
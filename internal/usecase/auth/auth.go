@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+// clockSkew is tolerated between the issuer and this service in the time
+// claims.
+const clockSkew = time.Minute
+
+// signingMethods are the accepted token algorithms, all of them asymmetric.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type Authenticator interface {
+	// Authenticate verifies the signature, issuer, audience and expiry of
+	// the bearer token and returns its principal. It fails with
+	// entity.ErrUnauthenticated when the token is not valid.
+	Authenticate(ctx context.Context, token string) (entity.Principal, error)
+}
+
+var _ Authenticator = (*authenticatorImpl)(nil)
+
+type authenticatorImpl struct {
+	logger           *zap.Logger
+	verificationKeys repository.VerificationKeys
+	parser           *jwt.Parser
+	rolesClaim       string
+}
+
+func New(
+	logger *zap.Logger,
+	verificationKeys repository.VerificationKeys,
+	issuer string,
+	audience string,
+	rolesClaim string,
+) *authenticatorImpl {
+	return &authenticatorImpl{
+		logger:           logger,
+		verificationKeys: verificationKeys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(signingMethods),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(clockSkew),
+		),
+		rolesClaim: rolesClaim,
+	}
+}
+
+func (a *authenticatorImpl) Authenticate(ctx context.Context, token string) (entity.Principal, error) {
+	var (
+		claims  = jwt.MapClaims{}
+		keysErr error
+	)
+
+	_, err := a.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+		key, err := a.verificationKeys.GetVerificationKey(ctx, keyID)
+
+		if err != nil && !errors.Is(err, entity.ErrVerificationKeyNotFound) {
+			keysErr = err
+		}
+
+		return key, err
+	})
+
+	// A key set that can not be fetched is not the fault of the token.
+	if keysErr != nil {
+		a.logger.Error("can not get verification key", zap.Error(keysErr))
+		return entity.Principal{}, keysErr
+	}
+
+	if err != nil {
+		return entity.Principal{}, fmt.Errorf("%w: %w", entity.ErrUnauthenticated, err)
+	}
+
+	subject, err := claims.GetSubject()
+
+	if err != nil || subject == "" {
+		return entity.Principal{}, fmt.Errorf("%w: token has no subject", entity.ErrUnauthenticated)
+	}
+
+	return entity.Principal{Subject: subject, Roles: roles(claims[a.rolesClaim]), Claims: claims}, nil
+}
+
+// roles accepts the claim as a list of strings, or as a space separated
+// string as the scope claim is.
+func roles(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return strings.Fields(claim)
+	case []any:
+		result := make([]string, 0, len(claim))
+
+		for _, role := range claim {
+			if role, ok := role.(string); ok {
+				result = append(result, role)
+			}
+		}
+
+		return result
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	issuer   = "https://issuer.example"
+	audience = "library"
+)
+
+func encode(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func writeJWKS(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+
+	return data
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, keyID string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = keyID
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   issuer,
+		"aud":   audience,
+		"sub":   "alice",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []any{"librarian"},
+	}
+}
+
+func TestAuthenticateURL(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data := writeJWKS(t, map[string]string{
+		"kty": "RSA",
+		"kid": "rsa",
+		"use": "sig",
+		"n":   encode(key.N),
+		"e":   encode(big.NewInt(int64(key.E))),
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	authenticator := New(zap.NewNop(), repository.NewURLJWKS(server.Client(), server.URL), issuer, audience, "roles")
+
+	principal, err := authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodRS256, "rsa", key, validClaims()))
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Subject)
+	require.Equal(t, []string{"librarian"}, principal.Roles)
+
+	rejected := map[string]func(jwt.MapClaims){
+		"issuer":     func(c jwt.MapClaims) { c["iss"] = "https://other.example" },
+		"audience":   func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":    func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":  func(c jwt.MapClaims) { delete(c, "exp") },
+		"no subject": func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+
+	for name, mutate := range rejected {
+		claims := validClaims()
+		mutate(claims)
+
+		_, err = authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodRS256, "rsa", key, claims))
+		require.ErrorIs(t, err, entity.ErrUnauthenticated, name)
+	}
+
+	_, err = authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodRS256, "unknown", key, validClaims()))
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	// Symmetric and unsigned tokens are never accepted.
+	_, err = authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims()))
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	_, err = authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims()))
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	_, err = authenticator.Authenticate(ctx, "not a token")
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+}
+
+func TestAuthenticateFile(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, writeJWKS(t, map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   encode(key.X),
+		"y":   encode(key.Y),
+	}), 0o600))
+
+	ctx := context.Background()
+	authenticator := New(zap.NewNop(), repository.NewFileJWKS(path), issuer, audience, "roles")
+
+	// The only key of the set matches a token without a key id.
+	claims := validClaims()
+	claims["roles"] = "reader librarian"
+
+	principal, err := authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodES256, "", key, claims))
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Subject)
+	require.Equal(t, []string{"reader", "librarian"}, principal.Roles)
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(ctx, sign(t, jwt.SigningMethodES256, "", other, validClaims()))
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+}
+
+func TestAuthenticateKeysUnavailable(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator := New(zap.NewNop(), repository.NewFileJWKS(filepath.Join(t.TempDir(), "missing.json")), issuer, audience, "roles")
+
+	_, err = authenticator.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa", key, validClaims()))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, entity.ErrUnauthenticated)
+}
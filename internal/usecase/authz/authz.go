@@ -0,0 +1,58 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+// PublicRole is held by the callers without credentials.
+const PublicRole = "public"
+
+type Authorizer interface {
+	// Authorize fails with entity.ErrPermissionDenied unless the policy
+	// grants the method to a role or a scope of the principal in the
+	// context, or to PublicRole without one. The returned context is marked with
+	// entity.WithOwnRecordsOnly when the method is granted on the records
+	// of the principal only.
+	Authorize(ctx context.Context, method string) (context.Context, error)
+}
+
+var _ Authorizer = (*authorizerImpl)(nil)
+
+type authorizerImpl struct {
+	logger *zap.Logger
+	policy *Policy
+}
+
+func New(logger *zap.Logger, policy *Policy) *authorizerImpl {
+	return &authorizerImpl{
+		logger: logger,
+		policy: policy,
+	}
+}
+
+func (a *authorizerImpl) Authorize(ctx context.Context, method string) (context.Context, error) {
+	principal, ok := entity.PrincipalFromContext(ctx)
+
+	if !ok {
+		principal.Roles = []string{PublicRole}
+	}
+
+	if a.policy.Allows(principal.Roles, method) || a.policy.ScopesAllow(principal.Scopes, method) {
+		if a.policy.OwnOnly(principal.Roles, principal.Scopes, method) {
+			return entity.WithOwnRecordsOnly(ctx), nil
+		}
+
+		return ctx, nil
+	}
+
+	a.logger.Info("permission denied",
+		zap.String("subject", principal.Subject),
+		zap.Strings("roles", principal.Roles),
+		zap.Strings("scopes", principal.Scopes),
+		zap.String("method", method))
+
+	return ctx, entity.ErrPermissionDenied
+}
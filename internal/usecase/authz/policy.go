@@ -0,0 +1,186 @@
+package authz
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed policy.yaml
+var defaultPolicy []byte
+
+// Policy grants each role the methods of its groups, the methods of no group
+// of the role are denied to it.
+type Policy struct {
+	groups map[string][]string
+	roles  map[string][]string
+	own    map[string]bool
+}
+
+type policyFile struct {
+	// Groups hold full method names, a name ending in /* holds every method
+	// of the service.
+	Groups map[string][]string `yaml:"groups"`
+	// Roles list their groups.
+	Roles map[string][]string `yaml:"roles"`
+	// Own lists the groups granting their methods on the records of the
+	// caller only.
+	Own []string `yaml:"own"`
+}
+
+// DefaultPolicy returns the built-in policy: readers may read the catalog,
+// librarians may edit it and run the circulation, admins may call anything.
+func DefaultPolicy() *Policy {
+	policy, err := ParsePolicy(defaultPolicy)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
+
+// LoadPolicy reads the policy from the file, the built-in policy is used
+// without one.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := ParsePolicy(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
+	var file policyFile
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	for group, methods := range file.Groups {
+		if err := validateMethods(methods); err != nil {
+			return nil, fmt.Errorf("group %s: %w", group, err)
+		}
+	}
+
+	for role, groups := range file.Roles {
+		for _, group := range groups {
+			if _, ok := file.Groups[group]; !ok {
+				return nil, fmt.Errorf("role %s: unknown group %s", role, group)
+			}
+		}
+	}
+
+	own := make(map[string]bool, len(file.Own))
+
+	for _, group := range file.Own {
+		if _, ok := file.Groups[group]; !ok {
+			return nil, fmt.Errorf("own: unknown group %s", group)
+		}
+
+		own[group] = true
+	}
+
+	return &Policy{groups: file.Groups, roles: file.Roles, own: own}, nil
+}
+
+func validateMethods(methods []string) error {
+	for _, method := range methods {
+		service, name, found := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+
+		if !strings.HasPrefix(method, "/") || !found || service == "" || name == "" {
+			return fmt.Errorf("method %q is not /package.Service/Method", method)
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether any of the roles is granted the method, given by
+// its full name.
+func (p *Policy) Allows(roles []string, method string) bool {
+	for _, role := range roles {
+		if p.groupsAllow(p.roles[role], method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScopesAllow reports whether any of the scopes of an API key is granted the
+// method. The scopes name the groups of the policy.
+func (p *Policy) ScopesAllow(scopes []string, method string) bool {
+	return p.groupsAllow(scopes, method)
+}
+
+// OwnOnly reports whether the roles and the scopes are granted the method
+// only by groups listed under own, on the records of the caller then.
+func (p *Policy) OwnOnly(roles []string, scopes []string, method string) bool {
+	for _, role := range roles {
+		if p.groupsAllowAll(p.roles[role], method) {
+			return false
+		}
+	}
+
+	return !p.groupsAllowAll(scopes, method)
+}
+
+func (p *Policy) groupsAllow(groups []string, method string) bool {
+	for _, group := range groups {
+		if p.groupAllows(group, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupsAllowAll reports whether a group not listed under own grants the
+// method.
+func (p *Policy) groupsAllowAll(groups []string, method string) bool {
+	for _, group := range groups {
+		if !p.own[group] && p.groupAllows(group, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Policy) groupAllows(group string, method string) bool {
+	for _, pattern := range p.groups[group] {
+		if matches(pattern, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matches(pattern string, method string) bool {
+	if service, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(method, service+"/")
+	}
+
+	return pattern == method
+}
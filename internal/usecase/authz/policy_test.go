@@ -0,0 +1,149 @@
+package authz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		method  string
+		allowed []string
+		denied  []string
+	}{
+		{"/library.Library/GetBookInfo", []string{"public", "reader", "librarian", "admin"}, []string{"guest"}},
+		{"/library.Library/GetAuthorBooks", []string{"public", "reader", "librarian", "admin"}, []string{"guest"}},
+		{"/library.Library/AddBook", []string{"librarian", "admin"}, []string{"public", "reader"}},
+		{"/library.Library/GetPatron", []string{"librarian", "admin"}, []string{"public", "reader"}},
+		{"/library.Library/CreateReview", []string{"reader", "librarian", "admin"}, []string{"public", "guest"}},
+		{"/library.Library/UpdateReview", []string{"reader", "librarian", "admin"}, []string{"guest"}},
+		{"/library.Library/DeleteReview", []string{"librarian", "admin"}, []string{"reader"}},
+		{"/library.Library/MergeAuthors", []string{"admin"}, []string{"reader", "librarian"}},
+		{"/library.Library/GetBookHistory", []string{"admin"}, []string{"reader", "librarian"}},
+		{"/library.ApiKeys/CreateApiKey", []string{"admin"}, []string{"reader", "librarian"}},
+		{"/other.Library/GetBookInfo", nil, []string{"reader", "librarian", "admin"}},
+	}
+
+	for _, test := range tests {
+		for _, role := range test.allowed {
+			require.True(t, policy.Allows([]string{role}, test.method), "%s %s", role, test.method)
+		}
+
+		for _, role := range test.denied {
+			require.False(t, policy.Allows([]string{role}, test.method), "%s %s", role, test.method)
+		}
+	}
+
+	require.False(t, policy.Allows(nil, "/library.Library/GetBookInfo"))
+	require.True(t, policy.Allows([]string{"guest", "librarian"}, "/library.Library/AddBook"))
+
+	// Readers review as themselves, librarians edit any review.
+	require.True(t, policy.OwnOnly([]string{"reader"}, nil, "/library.Library/CreateReview"))
+	require.True(t, policy.OwnOnly([]string{"reader"}, nil, "/library.Library/UpdateReview"))
+	require.False(t, policy.OwnOnly([]string{"reader", "librarian"}, nil, "/library.Library/UpdateReview"))
+	require.False(t, policy.OwnOnly([]string{"reader"}, []string{"write"}, "/library.Library/CreateReview"))
+	require.False(t, policy.OwnOnly([]string{"reader"}, nil, "/library.Library/GetBookInfo"))
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`
+groups:
+  catalog: [/library.Library/*]
+  health: [/grpc.health.v1.Health/Check]
+roles:
+  cataloger: [catalog]
+  probe: [health]
+`))
+	require.NoError(t, err)
+	require.True(t, policy.Allows([]string{"cataloger"}, "/library.Library/AddBook"))
+	require.False(t, policy.Allows([]string{"cataloger"}, "/library.Library2/AddBook"))
+	require.True(t, policy.Allows([]string{"probe"}, "/grpc.health.v1.Health/Check"))
+	require.False(t, policy.Allows([]string{"probe"}, "/grpc.health.v1.Health/Watch"))
+
+	invalid := []string{
+		"roles: {reader: [missing]}",
+		"groups: {read: [GetBookInfo]}",
+		"groups: {read: [/library.Library/]}",
+		"group: {read: [/library.Library/GetBookInfo]}",
+		"roles: [reader]",
+		"groups: {read: [/library.Library/GetBookInfo]}\nown: [review]",
+	}
+
+	for _, data := range invalid {
+		_, err = ParsePolicy([]byte(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := LoadPolicy("")
+	require.NoError(t, err)
+	require.True(t, policy.Allows([]string{"reader"}, "/library.Library/GetBookInfo"))
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("groups: {read: [/library.Library/ListBooks]}\nroles: {reader: [read]}\n"), 0o600))
+
+	policy, err = LoadPolicy(path)
+	require.NoError(t, err)
+	require.True(t, policy.Allows([]string{"reader"}, "/library.Library/ListBooks"))
+	require.False(t, policy.Allows([]string{"reader"}, "/library.Library/GetBookInfo"))
+
+	_, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Parallel()
+
+	authorizer := New(zap.NewNop(), DefaultPolicy())
+	reader := entity.WithPrincipal(context.Background(), entity.Principal{Subject: "alice", Roles: []string{"reader"}})
+
+	ctx, err := authorizer.Authorize(reader, "/library.Library/GetAuthorBooks")
+	require.NoError(t, err)
+	require.NoError(t, entity.CheckOwner(ctx, "bob"))
+
+	_, err = authorizer.Authorize(reader, "/library.Library/AddBook")
+	require.ErrorIs(t, err, entity.ErrPermissionDenied)
+
+	// The callers without credentials hold the public role.
+	_, err = authorizer.Authorize(context.Background(), "/library.Library/GetBookInfo")
+	require.NoError(t, err)
+
+	_, err = authorizer.Authorize(context.Background(), "/library.Library/AddBook")
+	require.ErrorIs(t, err, entity.ErrPermissionDenied)
+
+	// A reader posts reviews as itself.
+	ctx, err = authorizer.Authorize(reader, "/library.Library/CreateReview")
+	require.NoError(t, err)
+	require.NoError(t, entity.CheckOwner(ctx, "alice"))
+	require.ErrorIs(t, entity.CheckOwner(ctx, "bob"), entity.ErrNotOwner)
+
+	key := entity.WithPrincipal(context.Background(), entity.Principal{Subject: "api-key:1", Scopes: []string{"write"}})
+
+	_, err = authorizer.Authorize(key, "/library.Library/AddBook")
+	require.NoError(t, err)
+
+	ctx, err = authorizer.Authorize(key, "/library.Library/CreateReview")
+	require.NoError(t, err)
+	require.NoError(t, entity.CheckOwner(ctx, "bob"))
+
+	_, err = authorizer.Authorize(key, "/library.Library/GetBookInfo")
+	require.ErrorIs(t, err, entity.ErrPermissionDenied)
+
+	_, err = authorizer.Authorize(key, "/library.ApiKeys/CreateApiKey")
+	require.ErrorIs(t, err, entity.ErrPermissionDenied)
+}
@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+type Idempotency interface {
+	// Begin reserves the key for the request with the fingerprint and
+	// returns the token of the reservation. When the same request has
+	// already completed with the key, its response is returned with replay
+	// set. It fails with entity.ErrIdempotencyKeyReused when the key was
+	// used with another request and with entity.ErrIdempotencyKeyInUse while
+	// the first request still runs.
+	Begin(ctx context.Context, key string, fingerprint []byte) (record entity.IdempotencyRecord, replay bool, err error)
+	// Complete stores the response of the request the key was reserved for
+	// with the token. The response is dropped when a retry has taken the
+	// key over in the meantime. On failure the key stays reserved until the
+	// pending TTL lapses, a retry then runs the request again.
+	Complete(ctx context.Context, key, token string, response []byte) error
+	// Release gives up the reservation of a failed request, so it can be
+	// retried.
+	Release(ctx context.Context, key, token string) error
+	// Prune deletes the expired keys and returns their number.
+	Prune(ctx context.Context) (int64, error)
+}
+
+var _ Idempotency = (*idempotencyImpl)(nil)
+
+type idempotencyImpl struct {
+	logger                *zap.Logger
+	idempotencyRepository repository.IdempotencyRepository
+	ttl                   time.Duration
+	pendingTTL            time.Duration
+}
+
+// New returns the idempotency keys replayed for ttl. A request may run for
+// pendingTTL before a retry takes its key over, so it must be well above
+// the deadline of the requests.
+func New(
+	logger *zap.Logger,
+	idempotencyRepository repository.IdempotencyRepository,
+	ttl time.Duration,
+	pendingTTL time.Duration,
+) *idempotencyImpl {
+	return &idempotencyImpl{
+		logger:                logger,
+		idempotencyRepository: idempotencyRepository,
+		ttl:                   ttl,
+		pendingTTL:            pendingTTL,
+	}
+}
+
+func (i *idempotencyImpl) Begin(
+	ctx context.Context,
+	key string,
+	fingerprint []byte,
+) (entity.IdempotencyRecord, bool, error) {
+	stored, reserved, err := i.idempotencyRepository.ReserveIdempotencyKey(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Token:       uuid.NewString(),
+		ExpiresAt:   time.Now().Add(i.ttl),
+	}, i.pendingTTL)
+
+	switch {
+	case err != nil:
+		i.logger.Error("can not reserve idempotency key", zap.Error(err))
+		return entity.IdempotencyRecord{}, false, err
+	case reserved:
+		return stored, false, nil
+	case !bytes.Equal(stored.Fingerprint, fingerprint):
+		return entity.IdempotencyRecord{}, false, entity.ErrIdempotencyKeyReused
+	case !stored.Completed:
+		return entity.IdempotencyRecord{}, false, entity.ErrIdempotencyKeyInUse
+	default:
+		return stored, true, nil
+	}
+}
+
+func (i *idempotencyImpl) Complete(ctx context.Context, key, token string, response []byte) error {
+	err := i.idempotencyRepository.CompleteIdempotencyKey(ctx, key, token, response)
+
+	if err != nil {
+		i.logger.Error("can not store idempotent response", zap.Error(err))
+	}
+
+	return err
+}
+
+func (i *idempotencyImpl) Release(ctx context.Context, key, token string) error {
+	return i.idempotencyRepository.ReleaseIdempotencyKey(ctx, key, token)
+}
+
+func (i *idempotencyImpl) Prune(ctx context.Context) (int64, error) {
+	return i.idempotencyRepository.PruneIdempotencyKeys(ctx, time.Now())
+}
@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	keys := New(zap.NewNop(), repository.NewInMemoryRepository(), time.Hour, time.Minute)
+
+	reservation, replay, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+	require.False(t, replay)
+	require.NotEmpty(t, reservation.Token)
+
+	// The retry comes while the first request still runs.
+	_, _, err = keys.Begin(ctx, "key", []byte("request"))
+	require.ErrorIs(t, err, entity.ErrIdempotencyKeyInUse)
+
+	require.NoError(t, keys.Complete(ctx, "key", reservation.Token, []byte("response")))
+
+	stored, replay, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+	require.True(t, replay)
+	require.Equal(t, []byte("response"), stored.Response)
+
+	_, _, err = keys.Begin(ctx, "key", []byte("other request"))
+	require.ErrorIs(t, err, entity.ErrIdempotencyKeyReused)
+
+	// An empty response is replayed as well.
+	reservation, _, err = keys.Begin(ctx, "empty", []byte("request"))
+	require.NoError(t, err)
+	require.NoError(t, keys.Complete(ctx, "empty", reservation.Token, nil))
+
+	stored, replay, err = keys.Begin(ctx, "empty", []byte("request"))
+	require.NoError(t, err)
+	require.True(t, replay)
+	require.Empty(t, stored.Response)
+}
+
+func TestReleaseAndExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	keys := New(zap.NewNop(), repo, time.Hour, time.Minute)
+
+	reservation, _, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+	require.NoError(t, keys.Release(ctx, "key", reservation.Token))
+
+	// The failed request is retried with another payload.
+	reservation, replay, err := keys.Begin(ctx, "key", []byte("fixed request"))
+	require.NoError(t, err)
+	require.False(t, replay)
+	require.NoError(t, keys.Complete(ctx, "key", reservation.Token, []byte("response")))
+
+	// Completed keys are not released.
+	require.NoError(t, keys.Release(ctx, "key", reservation.Token))
+
+	_, replay, err = keys.Begin(ctx, "key", []byte("fixed request"))
+	require.NoError(t, err)
+	require.True(t, replay)
+
+	pruned, err := keys.Prune(ctx)
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	expired := New(zap.NewNop(), repo, -time.Minute, time.Minute)
+
+	_, _, err = expired.Begin(ctx, "old", []byte("request"))
+	require.NoError(t, err)
+
+	// An expired key is free for another request.
+	_, replay, err = expired.Begin(ctx, "old", []byte("other request"))
+	require.NoError(t, err)
+	require.False(t, replay)
+
+	pruned, err = keys.Prune(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pruned)
+}
+
+func TestTakeover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	keys := New(zap.NewNop(), repo, time.Hour, time.Minute)
+
+	first, _, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+
+	// A retry takes the key over once the first request is deemed dead.
+	_, reserved, err := repo.ReserveIdempotencyKey(ctx, entity.IdempotencyRecord{
+		Key:         "key",
+		Fingerprint: []byte("request"),
+		Token:       "retry",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, -time.Second)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// The first request neither releases nor completes the retry's key.
+	require.NoError(t, keys.Release(ctx, "key", first.Token))
+	require.NoError(t, keys.Complete(ctx, "key", first.Token, []byte("first response")))
+
+	_, _, err = keys.Begin(ctx, "key", []byte("request"))
+	require.ErrorIs(t, err, entity.ErrIdempotencyKeyInUse)
+
+	require.NoError(t, keys.Complete(ctx, "key", "retry", []byte("retry response")))
+
+	stored, replay, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+	require.True(t, replay)
+	require.Equal(t, []byte("retry response"), stored.Response)
+}
+
+func TestPendingTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	keys := New(zap.NewNop(), repo, time.Hour, time.Hour)
+
+	_, _, err := keys.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+
+	// The retry is refused while the first request may still run.
+	_, _, err = keys.Begin(ctx, "key", []byte("request"))
+	require.ErrorIs(t, err, entity.ErrIdempotencyKeyInUse)
+
+	lapsed := New(zap.NewNop(), repo, time.Hour, -time.Second)
+
+	reservation, replay, err := lapsed.Begin(ctx, "key", []byte("request"))
+	require.NoError(t, err)
+	require.False(t, replay)
+	require.NotEmpty(t, reservation.Token)
+}
@@ -0,0 +1,70 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBookInfoAsOf(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	before := time.Now()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	book, err := l.RegisterBook(ctx, "Draft", entity.AuthorContributors([]string{author.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	drafted := time.Now()
+
+	_, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("Final"), Contributors: &[]entity.Contributor{}}, nil)
+	require.NoError(t, err)
+
+	_, err = l.GetBookInfo(ctx, book.ID, &before)
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+
+	past, err := l.GetBookInfo(ctx, book.ID, &drafted)
+	require.NoError(t, err)
+	require.Equal(t, "Draft", past.Name)
+	require.Equal(t, []string{author.ID}, past.AuthorIDs)
+
+	books, err := l.GetAuthorBooks(ctx, author.ID, "", &drafted)
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+
+	books, err = l.GetAuthorBooks(ctx, author.ID, "", nil)
+	require.NoError(t, err)
+	require.Empty(t, books)
+}
+
+func TestGetAuthorInfoAsOf(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Before", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	registered := time.Now()
+
+	_, err = l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{Name: ptr("After")}, nil)
+	require.NoError(t, err)
+
+	past, err := l.GetAuthorInfo(ctx, author.ID, &registered)
+	require.NoError(t, err)
+	require.Equal(t, "Before", past.Name)
+
+	now := time.Now()
+	current, err := l.GetAuthorInfo(ctx, author.ID, &now)
+	require.NoError(t, err)
+	require.Equal(t, "After", current.Name)
+}
@@ -0,0 +1,104 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) GetBookHistory(
+	ctx context.Context,
+	bookID string,
+	afterID uint64,
+	limit int,
+) ([]entity.AuditRecord, error) {
+	return l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityBook, bookID, afterID, limit)
+}
+
+func (l *libraryImpl) GetAuthorHistory(
+	ctx context.Context,
+	authorID string,
+	afterID uint64,
+	limit int,
+) ([]entity.AuditRecord, error) {
+	return l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityAuthor, authorID, afterID, limit)
+}
+
+// appendAudit records a change made by the actor of ctx. Pass a nil before
+// for created entities.
+func (l *libraryImpl) appendAudit(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	kind entity.AuditEntityKind,
+	entityID string,
+	before any,
+	after any,
+) error {
+	beforeDiff, afterDiff, err := auditDiff(before, after)
+
+	if err != nil {
+		return err
+	}
+
+	return l.auditRepository.AppendAuditRecord(ctx, entity.AuditRecord{
+		Actor:      entity.ActorFromContext(ctx),
+		Operation:  operation,
+		EntityKind: kind,
+		EntityID:   entityID,
+		Before:     beforeDiff,
+		After:      afterDiff,
+	})
+}
+
+// auditDiff keeps only the fields whose JSON representation differs
+// between before and after.
+func auditDiff(before any, after any) (json.RawMessage, json.RawMessage, error) {
+	if before == nil {
+		afterDiff, err := json.Marshal(after)
+		return nil, afterDiff, err
+	}
+
+	beforeFields, err := jsonFields(before)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterFields, err := jsonFields(after)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for name, value := range afterFields {
+		if bytes.Equal(beforeFields[name], value) {
+			delete(beforeFields, name)
+			delete(afterFields, name)
+		}
+	}
+
+	beforeDiff, err := json.Marshal(beforeFields)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterDiff, err := json.Marshal(afterFields)
+
+	return beforeDiff, afterDiff, err
+}
+
+func jsonFields(value any) (map[string]json.RawMessage, error) {
+	serialized, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(serialized, &fields)
+
+	return fields, err
+}
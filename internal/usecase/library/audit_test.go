@@ -0,0 +1,68 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := entity.WithActor(context.Background(), "librarian")
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	_, err = l.ChangeAuthorInfo(context.Background(), author.ID, entity.AuthorPatch{Name: ptr("Renamed")}, nil)
+	require.NoError(t, err)
+
+	records, err := l.GetAuthorHistory(ctx, author.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.Equal(t, "librarian", records[0].Actor)
+	require.Equal(t, entity.AuditOperationRegisterAuthor, records[0].Operation)
+	require.Nil(t, records[0].Before)
+
+	require.Equal(t, entity.AnonymousActor, records[1].Actor)
+	require.Equal(t, entity.AuditOperationChangeAuthorInfo, records[1].Operation)
+	require.JSONEq(t, `{"Name":"Author","Version":1}`, string(records[1].Before))
+	require.JSONEq(t, `{"Name":"Renamed","Version":2}`, string(records[1].After))
+
+	// Paging continues after the last seen record.
+	records, err = l.GetAuthorHistory(ctx, author.ID, records[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, entity.AuditOperationChangeAuthorInfo, records[0].Operation)
+}
+
+func TestBookHistoryKeepsOtherBooksApart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterBook(ctx, "First", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Second", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.UpdateBook(ctx, first.ID, entity.BookPatch{Name: ptr("Renamed")}, nil)
+	require.NoError(t, err)
+
+	records, err := l.GetBookHistory(ctx, first.ID, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, entity.AuditOperationRegisterBook, records[0].Operation)
+
+	records, err = l.GetBookHistory(ctx, first.ID, records[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, entity.AuditOperationUpdateBook, records[0].Operation)
+	require.Contains(t, string(records[0].After), `"Name":"Renamed"`)
+}
@@ -0,0 +1,211 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) MergeAuthors(ctx context.Context, targetID string, sourceIDs []string) (entity.Author, error) {
+	sourceIDs = slices.Clone(sourceIDs)
+	slices.Sort(sourceIDs)
+	sourceIDs = slices.Compact(sourceIDs)
+
+	if len(sourceIDs) == 0 || slices.Contains(sourceIDs, targetID) {
+		return entity.Author{}, entity.ErrInvalidMerge
+	}
+
+	var target entity.Author
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, sources, txErr := l.lockMergedAuthors(ctx, targetID, sourceIDs)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.recreditBooks(ctx, targetID, sourceIDs); txErr != nil {
+			return txErr
+		}
+
+		target, txErr = l.adoptAliases(ctx, previous, sources)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.authorRepository.MergeAuthors(ctx, targetID, sourceIDs); txErr != nil {
+			return txErr
+		}
+
+		return l.recordMergedAuthors(ctx, targetID, sources)
+	})
+
+	if err != nil {
+		l.logger.Error("can not merge authors", zap.Error(err))
+		return entity.Author{}, err
+	}
+
+	return target, nil
+}
+
+// lockMergedAuthors locks the target and the sorted sources in the order of
+// their ids, so concurrent merges of overlapping authors do not deadlock.
+func (l *libraryImpl) lockMergedAuthors(
+	ctx context.Context,
+	targetID string,
+	sourceIDs []string,
+) (entity.Author, []entity.Author, error) {
+	var target entity.Author
+
+	sources := make([]entity.Author, 0, len(sourceIDs))
+	authorIDs := append([]string{targetID}, sourceIDs...)
+	slices.Sort(authorIDs)
+
+	for _, authorID := range authorIDs {
+		author, err := l.authorRepository.LockAuthor(ctx, authorID)
+
+		if err != nil {
+			return entity.Author{}, nil, err
+		}
+
+		if authorID == targetID {
+			target = author
+		} else {
+			sources = append(sources, author)
+		}
+	}
+
+	return target, sources, nil
+}
+
+// recreditBooks credits the target instead of the sources on every book
+// crediting one of them, each book is versioned and audited as an update.
+// Books are updated in the order of their ids, so concurrent merges sharing
+// books do not deadlock.
+func (l *libraryImpl) recreditBooks(ctx context.Context, targetID string, sourceIDs []string) error {
+	books := make(map[string]entity.Book)
+
+	for _, sourceID := range sourceIDs {
+		credited, err := l.authorRepository.GetAuthorBooks(ctx, sourceID, "")
+
+		if err != nil {
+			return err
+		}
+
+		for _, book := range credited {
+			books[book.ID] = book
+		}
+	}
+
+	for _, bookID := range slices.Sorted(maps.Keys(books)) {
+		book := books[bookID]
+		contributors := slices.Clone(book.Contributors)
+
+		for i := range contributors {
+			if slices.Contains(sourceIDs, contributors[i].AuthorID) {
+				contributors[i].AuthorID = targetID
+			}
+		}
+
+		contributors = entity.CompactContributors(contributors)
+		patch := entity.BookPatch{Contributors: &contributors}
+
+		if _, err := l.updateBook(ctx, entity.AuditOperationMergeAuthors, book.ID, patch, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// adoptAliases adds the names and the aliases of the sources to the aliases
+// of the target, so searches for them find the target.
+func (l *libraryImpl) adoptAliases(
+	ctx context.Context,
+	previous entity.Author,
+	sources []entity.Author,
+) (entity.Author, error) {
+	aliases := slices.Clone(previous.Aliases)
+	known := func(name string) bool {
+		return strings.EqualFold(name, previous.Name) || slices.ContainsFunc(aliases, func(alias string) bool {
+			return strings.EqualFold(alias, name)
+		})
+	}
+
+	for _, source := range sources {
+		for _, name := range append([]string{source.Name}, source.Aliases...) {
+			if !known(name) {
+				aliases = append(aliases, name)
+			}
+		}
+	}
+
+	target, err := l.authorRepository.UpdateAuthor(ctx, previous.ID, entity.AuthorPatch{Aliases: &aliases}, nil)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	err = l.appendAudit(ctx, entity.AuditOperationMergeAuthors, entity.AuditEntityAuthor, target.ID, previous, target)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return target, l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+		Kind:      entity.CatalogEventUpdated,
+		Author:    &target,
+		AuthorIDs: []string{target.ID},
+	})
+}
+
+// recordMergedAuthors audits the removal of the merged sources and notifies
+// the subscribers of the catalog and of the outbox.
+func (l *libraryImpl) recordMergedAuthors(ctx context.Context, targetID string, sources []entity.Author) error {
+	sourceIDs := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		err := l.appendAudit(ctx, entity.AuditOperationMergeAuthors, entity.AuditEntityAuthor, source.ID, source, nil)
+
+		if err != nil {
+			return err
+		}
+
+		err = l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+			Kind:      entity.CatalogEventDeleted,
+			Author:    &source,
+			AuthorIDs: []string{source.ID},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		sourceIDs = append(sourceIDs, source.ID)
+	}
+
+	serialized, err := json.Marshal(entity.AuthorMerge{TargetID: targetID, SourceIDs: sourceIDs})
+
+	if err != nil {
+		return err
+	}
+
+	idempotencyKey := repository.OutboxKindAuthorMerge.String() + "_" + strings.Join(sourceIDs, "_")
+
+	return l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindAuthorMerge, serialized)
+}
+
+func (l *libraryImpl) FindDuplicateAuthors(
+	ctx context.Context,
+	minSimilarity float64,
+	limit int,
+) ([]entity.AuthorDuplicate, error) {
+	return l.authorRepository.FindDuplicateAuthors(ctx, minSimilarity, limit)
+}
@@ -0,0 +1,130 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAuthors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	target, err := l.RegisterAuthor(ctx, "Leo Tolstoy", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	source, err := l.RegisterAuthor(ctx, "Tolstoy, Leo", entity.AuthorProfile{Aliases: []string{"Lev Tolstoy"}})
+	require.NoError(t, err)
+
+	translator, err := l.RegisterAuthor(ctx, "Translator", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	// The book credits both as authors, the merged credit is kept once.
+	shared, err := l.RegisterBook(ctx, "War and Peace", []entity.Contributor{
+		{AuthorID: source.ID},
+		{AuthorID: translator.ID, Role: entity.ContributorRoleTranslator},
+		{AuthorID: target.ID},
+	}, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Anna Karenina", entity.AuthorContributors([]string{source.ID}),
+		entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	merged, err := l.MergeAuthors(ctx, target.ID, []string{source.ID})
+	require.NoError(t, err)
+	require.Equal(t, target.ID, merged.ID)
+	require.Equal(t, []string{"Tolstoy, Leo", "Lev Tolstoy"}, merged.Aliases)
+
+	book, err := l.GetBookInfo(ctx, shared.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Contributor{
+		{AuthorID: target.ID, Role: entity.ContributorRoleAuthor, Position: 0},
+		{AuthorID: translator.ID, Role: entity.ContributorRoleTranslator, Position: 1},
+	}, book.Contributors)
+	require.Equal(t, shared.Version+1, book.Version)
+
+	books, err := l.GetAuthorBooks(ctx, target.ID, "", nil)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+
+	books, err = l.GetAuthorBooks(ctx, source.ID, "", nil)
+	require.NoError(t, err)
+	require.Empty(t, books)
+
+	// The merged id resolves to the target.
+	resolved, err := l.GetAuthorInfo(ctx, source.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, merged, resolved)
+
+	messages, err := l.outboxRepository.GetMessages(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	var merge entity.AuthorMerge
+
+	for _, message := range messages {
+		if message.Kind == repository.OutboxKindAuthorMerge {
+			require.NoError(t, json.Unmarshal(message.RawData, &merge))
+		}
+	}
+
+	require.Equal(t, entity.AuthorMerge{TargetID: target.ID, SourceIDs: []string{source.ID}}, merge)
+
+	records, err := l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityAuthor, source.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, entity.AuditOperationMergeAuthors, records[1].Operation)
+}
+
+func TestMergeAuthorsRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	_, err = l.MergeAuthors(ctx, author.ID, []string{author.ID})
+	require.ErrorIs(t, err, entity.ErrInvalidMerge)
+
+	_, err = l.MergeAuthors(ctx, author.ID, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidMerge)
+
+	_, err = l.MergeAuthors(ctx, author.ID, []string{"00000000-0000-0000-0000-000000000000"})
+	require.ErrorIs(t, err, entity.ErrAuthorNotFound)
+}
+
+func TestFindDuplicateAuthors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterAuthor(ctx, "Fyodor Dostoevsky", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	second, err := l.RegisterAuthor(ctx, "Fyodor Dostoyevsky", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	_, err = l.RegisterAuthor(ctx, "Jane Austen", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	duplicates, err := l.FindDuplicateAuthors(ctx, 0.6, 10)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	require.ElementsMatch(t, []string{first.ID, second.ID},
+		[]string{duplicates[0].Author.ID, duplicates[0].Duplicate.ID})
+	require.Greater(t, duplicates[0].Similarity, 0.6)
+
+	duplicates, err = l.FindDuplicateAuthors(ctx, 0.6, 0)
+	require.NoError(t, err)
+	require.Empty(t, duplicates)
+}
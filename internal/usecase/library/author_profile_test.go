@@ -0,0 +1,99 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuthorByExternalID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Albert Einstein", entity.AuthorProfile{
+		BirthDate: "1879-03-14",
+		DeathDate: "1955-04-18",
+		Country:   "DE",
+		ExternalIDs: entity.ExternalIDs{
+			ISNI:     "0000 0001 2103 2683",
+			Wikidata: "Q937",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0000000121032683", author.ExternalIDs.ISNI)
+
+	found, err := l.GetAuthorByExternalID(ctx, entity.ExternalIDSchemeISNI, "0000 0001 2103 2683")
+	require.NoError(t, err)
+	require.Equal(t, author, found)
+
+	found, err = l.GetAuthorByExternalID(ctx, entity.ExternalIDSchemeWikidata, "Q937")
+	require.NoError(t, err)
+	require.Equal(t, author.ID, found.ID)
+
+	_, err = l.GetAuthorByExternalID(ctx, entity.ExternalIDSchemeVIAF, "75121530")
+	require.ErrorIs(t, err, entity.ErrAuthorNotFound)
+
+	_, err = l.GetAuthorByExternalID(ctx, entity.ExternalIDSchemeISNI, "0000000121032684")
+	require.ErrorIs(t, err, entity.ErrInvalidExternalID)
+
+	_, err = l.RegisterAuthor(ctx, "Other", entity.AuthorProfile{
+		ExternalIDs: entity.ExternalIDs{Wikidata: "Q937"},
+	})
+	require.ErrorIs(t, err, entity.ErrExternalIDTaken)
+}
+
+func TestAuthorLifeDates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	_, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{BirthDate: "1900-02-30"})
+	require.ErrorIs(t, err, entity.ErrInvalidDate)
+
+	_, err = l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{BirthDate: "1900-01-02", DeathDate: "1900-01-01"})
+	require.ErrorIs(t, err, entity.ErrInvalidLifeDates)
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{BirthDate: "1900-01-02"})
+	require.NoError(t, err)
+
+	// The patched date is checked against the stored one.
+	_, err = l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{DeathDate: ptr("1899-12-31")}, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidLifeDates)
+
+	changed, err := l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{DeathDate: ptr("1950-06-01")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "1900-01-02", changed.BirthDate)
+	require.Equal(t, "1950-06-01", changed.DeathDate)
+}
+
+func TestChangeSingleExternalID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Albert Einstein", entity.AuthorProfile{
+		ExternalIDs: entity.ExternalIDs{VIAF: "75121530", Wikidata: "Q937"},
+	})
+	require.NoError(t, err)
+
+	// The other identifiers are kept.
+	changed, err := l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{
+		ExternalIDUpdates: map[entity.ExternalIDScheme]string{
+			entity.ExternalIDSchemeISNI:     "0000 0001 2103 2683",
+			entity.ExternalIDSchemeWikidata: "",
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, entity.ExternalIDs{VIAF: "75121530", ISNI: "0000000121032683"}, changed.ExternalIDs)
+
+	_, err = l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{
+		ExternalIDUpdates: map[entity.ExternalIDScheme]string{entity.ExternalIDSchemeISNI: "0000000121032684"},
+	}, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidExternalID)
+}
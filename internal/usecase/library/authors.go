@@ -1 +1,269 @@
 package library
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) RegisterAuthor(
+	ctx context.Context,
+	authorName string,
+	profile entity.AuthorProfile,
+) (entity.Author, error) {
+	profile, err := normalizeAuthorProfile(profile)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	var author entity.Author
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		author, txErr = l.authorRepository.CreateAuthor(ctx, entity.Author{
+			Name:          authorName,
+			AuthorProfile: profile,
+		})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		serialized, txErr := json.Marshal(author)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		idempotencyKey := repository.OutboxKindAuthor.String() + "_" + author.ID
+		txErr = l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindAuthor, serialized)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, entity.AuditOperationRegisterAuthor, entity.AuditEntityAuthor, author.ID, nil, author)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+			Kind:      entity.CatalogEventCreated,
+			Author:    &author,
+			AuthorIDs: []string{author.ID},
+		})
+	})
+
+	if err != nil {
+		l.logger.Error("can not register author", zap.Error(err))
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func (l *libraryImpl) GetAuthorInfo(ctx context.Context, authorID string, asOf *time.Time) (entity.Author, error) {
+	if asOf != nil {
+		return l.authorRepository.GetAuthorAsOf(ctx, authorID, *asOf)
+	}
+
+	authorID, err := l.authorRepository.ResolveAuthorID(ctx, authorID)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return l.authorRepository.GetAuthor(ctx, authorID)
+}
+
+func (l *libraryImpl) ChangeAuthorInfo(
+	ctx context.Context,
+	authorID string,
+	patch entity.AuthorPatch,
+	expectedVersion *uint64,
+) (entity.Author, error) {
+	patch, err := normalizeAuthorPatch(patch)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	var author entity.Author
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// The previous version is audited, it must stay current until the
+		// update.
+		previous, txErr := l.authorRepository.LockAuthor(ctx, authorID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		author, txErr = l.authorRepository.UpdateAuthor(ctx, authorID,
+			withExternalIDUpdates(previous.ExternalIDs, patch), expectedVersion)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, entity.AuditOperationChangeAuthorInfo, entity.AuditEntityAuthor, author.ID,
+			previous, author)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+			Kind:      entity.CatalogEventUpdated,
+			Author:    &author,
+			AuthorIDs: []string{author.ID},
+		})
+	})
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func (l *libraryImpl) GetAuthorBooks(
+	ctx context.Context,
+	authorID string,
+	role entity.ContributorRole,
+	asOf *time.Time,
+) ([]entity.Book, error) {
+	if role != "" && !role.Valid() {
+		return nil, entity.ErrInvalidContributorRole
+	}
+
+	if asOf != nil {
+		return l.authorRepository.GetAuthorBooksAsOf(ctx, authorID, role, *asOf)
+	}
+
+	return l.authorRepository.GetAuthorBooks(ctx, authorID, role)
+}
+
+func (l *libraryImpl) GetAuthorByExternalID(
+	ctx context.Context,
+	scheme entity.ExternalIDScheme,
+	value string,
+) (entity.Author, error) {
+	value, err := entity.NormalizeExternalID(scheme, value)
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return l.authorRepository.GetAuthorByExternalID(ctx, scheme, value)
+}
+
+func normalizeAuthorProfile(profile entity.AuthorProfile) (entity.AuthorProfile, error) {
+	if err := checkDates(profile.BirthDate, profile.DeathDate); err != nil {
+		return entity.AuthorProfile{}, err
+	}
+
+	// Dates formatted as YYYY-MM-DD compare as strings.
+	if profile.BirthDate != "" && profile.DeathDate != "" && profile.DeathDate < profile.BirthDate {
+		return entity.AuthorProfile{}, entity.ErrInvalidLifeDates
+	}
+
+	var err error
+	profile.ExternalIDs, err = entity.NormalizeExternalIDs(profile.ExternalIDs)
+
+	return profile, err
+}
+
+// normalizeAuthorPatch checks the patched fields on their own, the life
+// dates are checked against each other by the repository.
+func normalizeAuthorPatch(patch entity.AuthorPatch) (entity.AuthorPatch, error) {
+	for _, date := range []*string{patch.BirthDate, patch.DeathDate} {
+		if date == nil {
+			continue
+		}
+
+		if err := checkDates(*date); err != nil {
+			return entity.AuthorPatch{}, err
+		}
+	}
+
+	updates, err := normalizeExternalIDUpdates(patch.ExternalIDUpdates)
+
+	if err != nil {
+		return entity.AuthorPatch{}, err
+	}
+
+	patch.ExternalIDUpdates = updates
+
+	if patch.ExternalIDs == nil {
+		return patch, nil
+	}
+
+	externalIDs, err := entity.NormalizeExternalIDs(*patch.ExternalIDs)
+	patch.ExternalIDs = &externalIDs
+
+	return patch, err
+}
+
+func normalizeExternalIDUpdates(updates map[entity.ExternalIDScheme]string) (map[entity.ExternalIDScheme]string, error) {
+	normalized := make(map[entity.ExternalIDScheme]string, len(updates))
+
+	for scheme, value := range updates {
+		if value == "" {
+			normalized[scheme] = value
+			continue
+		}
+
+		var err error
+
+		if normalized[scheme], err = entity.NormalizeExternalID(scheme, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return normalized, nil
+}
+
+// withExternalIDUpdates folds the single identifier updates of the patch
+// into the identifiers it replaces, based on the current ones unless the
+// patch replaces them all.
+func withExternalIDUpdates(current entity.ExternalIDs, patch entity.AuthorPatch) entity.AuthorPatch {
+	if len(patch.ExternalIDUpdates) == 0 {
+		return patch
+	}
+
+	externalIDs := current
+
+	if patch.ExternalIDs != nil {
+		externalIDs = *patch.ExternalIDs
+	}
+
+	for scheme, value := range patch.ExternalIDUpdates {
+		externalIDs = externalIDs.With(scheme, value)
+	}
+
+	patch.ExternalIDs, patch.ExternalIDUpdates = &externalIDs, nil
+
+	return patch
+}
+
+// checkDates verifies that the non-empty dates are valid YYYY-MM-DD dates.
+func checkDates(dates ...string) error {
+	for _, date := range dates {
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return entity.ErrInvalidDate
+		}
+	}
+
+	return nil
+}
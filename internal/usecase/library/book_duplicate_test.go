@@ -0,0 +1,73 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDuplicateBook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterAuthor(ctx, "Brian Kernighan", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	second, err := l.RegisterAuthor(ctx, "Dennis Ritchie", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	book, err := l.RegisterBook(ctx, "The C Programming Language",
+		entity.AuthorContributors([]string{first.ID, second.ID}), entity.BookMetadata{ISBN: "9780131103627"}, false)
+	require.NoError(t, err)
+
+	// The same title differently written, with the authors in other roles.
+	_, err = l.RegisterBook(ctx, "the  C programming language!", []entity.Contributor{
+		{AuthorID: second.ID},
+		{AuthorID: first.ID, Role: entity.ContributorRoleEditor},
+	}, entity.BookMetadata{}, false)
+
+	var duplicate *entity.DuplicateBookError
+
+	require.ErrorAs(t, err, &duplicate)
+	require.Equal(t, book.ID, duplicate.BookID)
+	require.NotErrorIs(t, err, entity.ErrISBNTaken)
+
+	_, err = l.RegisterBook(ctx, "K&R", nil, entity.BookMetadata{ISBN: "0-13-110362-8"}, true)
+	require.ErrorAs(t, err, &duplicate)
+	require.Equal(t, book.ID, duplicate.BookID)
+	require.ErrorIs(t, err, entity.ErrISBNTaken)
+
+	// Other authors, or the override, register a new book.
+	_, err = l.RegisterBook(ctx, "The C Programming Language",
+		entity.AuthorContributors([]string{first.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	copied, err := l.RegisterBook(ctx, "The C Programming Language",
+		entity.AuthorContributors([]string{first.ID, second.ID}), entity.BookMetadata{}, true)
+	require.NoError(t, err)
+	require.NotEqual(t, book.ID, copied.ID)
+
+	// Books without authors are only checked by ISBN.
+	_, err = l.RegisterBook(ctx, "Beowulf", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Beowulf", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	// A renamed book is found by its new title.
+	draft, err := l.RegisterBook(ctx, "Draft", entity.AuthorContributors([]string{second.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	title := "The UNIX Programming Environment"
+	_, err = l.UpdateBook(ctx, draft.ID, entity.BookPatch{Name: &title}, nil)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "The Unix programming environment",
+		entity.AuthorContributors([]string{second.ID}), entity.BookMetadata{}, false)
+	require.ErrorAs(t, err, &duplicate)
+	require.Equal(t, draft.ID, duplicate.BookID)
+}
@@ -0,0 +1,74 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBookByISBN(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "The Go Programming Language", nil, entity.BookMetadata{
+		ISBN:            "0-13-419044-0",
+		Publisher:       "Addison-Wesley",
+		PublicationYear: 2015,
+		Language:        "EN-us",
+		PageCount:       380,
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, "9780134190440", book.ISBN)
+	require.Equal(t, "en-US", book.Language)
+
+	found, err := l.GetBookByISBN(ctx, "978-0-13-419044-0")
+	require.NoError(t, err)
+	require.Equal(t, book, found)
+
+	found, err = l.GetBookByISBN(ctx, "0134190440")
+	require.NoError(t, err)
+	require.Equal(t, book.ID, found.ID)
+
+	_, err = l.GetBookByISBN(ctx, "9780262033848")
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+
+	_, err = l.GetBookByISBN(ctx, "9780134190441")
+	require.ErrorIs(t, err, entity.ErrInvalidISBN)
+
+	_, err = l.RegisterBook(ctx, "Copy", nil, entity.BookMetadata{ISBN: "9780134190440"}, false)
+	require.ErrorIs(t, err, entity.ErrISBNTaken)
+}
+
+func TestUpdateBookMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterBook(ctx, "First", nil, entity.BookMetadata{ISBN: "9780134190440"}, false)
+	require.NoError(t, err)
+
+	second, err := l.RegisterBook(ctx, "Second", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.UpdateBook(ctx, second.ID, entity.BookPatch{ISBN: ptr("0-13-419044-0")}, nil)
+	require.ErrorIs(t, err, entity.ErrISBNTaken)
+
+	_, err = l.UpdateBook(ctx, second.ID, entity.BookPatch{Language: ptr("not a language")}, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidLanguage)
+
+	// Clearing the ISBN frees it for other books.
+	updated, err := l.UpdateBook(ctx, first.ID, entity.BookPatch{ISBN: ptr(""), PageCount: ptr(100)}, nil)
+	require.NoError(t, err)
+	require.Empty(t, updated.ISBN)
+	require.Equal(t, 100, updated.PageCount)
+	require.Equal(t, "First", updated.Name)
+
+	updated, err = l.UpdateBook(ctx, second.ID, entity.BookPatch{ISBN: ptr("9780134190440")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "9780134190440", updated.ISBN)
+}
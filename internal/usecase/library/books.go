@@ -1 +1,338 @@
 package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) RegisterBook(
+	ctx context.Context,
+	name string,
+	contributors []entity.Contributor,
+	metadata entity.BookMetadata,
+	allowDuplicate bool,
+) (entity.Book, error) {
+	contributors, err := entity.NormalizeContributors(contributors)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	metadata, err = normalizeBookMetadata(metadata)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	var book entity.Book
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		txErr := l.checkDuplicateBook(ctx, name, contributors, metadata.ISBN, allowDuplicate)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		book, txErr = l.booksRepository.CreateBook(ctx, entity.Book{
+			Name:         name,
+			Contributors: contributors,
+			BookMetadata: metadata,
+		})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		serialized, txErr := json.Marshal(book)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		idempotencyKey := repository.OutboxKindBook.String() + "_" + book.ID
+		txErr = l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindBook, serialized)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, entity.AuditOperationRegisterBook, entity.AuditEntityBook, book.ID, nil, book)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+			Kind:      entity.CatalogEventCreated,
+			Book:      &book,
+			AuthorIDs: book.AuthorIDs,
+		})
+	})
+
+	if err != nil {
+		l.logger.Error("can not register book", zap.Error(err))
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+// checkDuplicateBook fails with entity.DuplicateBookError when a book with
+// the ISBN is already in the catalog, or unless allowDuplicate is set, a
+// book with the same normalized title and authors. Books without authors
+// are only checked by ISBN, anonymous titles are often shared by unrelated
+// books.
+func (l *libraryImpl) checkDuplicateBook(
+	ctx context.Context,
+	name string,
+	contributors []entity.Contributor,
+	isbn string,
+	allowDuplicate bool,
+) error {
+	if isbn != "" {
+		existing, err := l.booksRepository.GetBookByISBN(ctx, isbn)
+
+		if err == nil {
+			return &entity.DuplicateBookError{BookID: existing.ID, ISBNTaken: true}
+		}
+
+		if !errors.Is(err, entity.ErrBookNotFound) {
+			return err
+		}
+	}
+
+	if allowDuplicate || len(contributors) == 0 {
+		return nil
+	}
+
+	title := entity.NormalizeTitle(name)
+
+	if err := l.booksRepository.LockBookTitle(ctx, title); err != nil {
+		return err
+	}
+
+	existing, err := l.booksRepository.GetBookByTitle(ctx, title, entity.ContributorAuthorIDs(contributors))
+
+	if err == nil {
+		return &entity.DuplicateBookError{BookID: existing.ID}
+	}
+
+	if !errors.Is(err, entity.ErrBookNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *libraryImpl) GetBookInfo(ctx context.Context, bookID string, asOf *time.Time) (entity.Book, error) {
+	if asOf != nil {
+		return l.booksRepository.GetBookAsOf(ctx, bookID, *asOf)
+	}
+
+	return l.booksRepository.GetBook(ctx, bookID)
+}
+
+func (l *libraryImpl) GetBookByISBN(ctx context.Context, isbn string) (entity.Book, error) {
+	isbn, err := entity.NormalizeISBN(isbn)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return l.booksRepository.GetBookByISBN(ctx, isbn)
+}
+
+func (l *libraryImpl) UpdateBook(
+	ctx context.Context,
+	bookID string,
+	patch entity.BookPatch,
+	expectedVersion *uint64,
+) (entity.Book, error) {
+	patch, err := normalizeBookPatch(patch)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return l.updateBook(ctx, entity.AuditOperationUpdateBook, bookID, patch, expectedVersion)
+}
+
+// updateBook applies the normalized patch and records the change as the
+// operation.
+func (l *libraryImpl) updateBook(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	bookID string,
+	patch entity.BookPatch,
+	expectedVersion *uint64,
+) (entity.Book, error) {
+	var book entity.Book
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// The previous version is audited, it must stay current until the
+		// update.
+		previous, txErr := l.booksRepository.LockBook(ctx, bookID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		book, txErr = l.booksRepository.UpdateBook(ctx, bookID, patch, expectedVersion)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, operation, entity.AuditEntityBook, book.ID, previous, book)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		// Authors unlinked by the update still get notified about it.
+		related := slices.Concat(previous.AuthorIDs, book.AuthorIDs)
+		slices.Sort(related)
+
+		return l.catalogRepository.AppendCatalogEvent(ctx, entity.CatalogEvent{
+			Kind:      entity.CatalogEventUpdated,
+			Book:      &book,
+			AuthorIDs: slices.Compact(related),
+		})
+	})
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+func (l *libraryImpl) ListBooks(
+	ctx context.Context,
+	filter entity.BookFilter,
+	afterName string,
+	afterID string,
+	limit int,
+) ([]entity.Book, error) {
+	filter, err := l.normalizeBookFilter(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.booksRepository.ListBooks(ctx, filter, afterName, afterID, limit)
+}
+
+func (l *libraryImpl) ListTags(ctx context.Context, filter entity.BookFilter, limit int) ([]entity.TagCount, error) {
+	filter, err := l.normalizeBookFilter(ctx, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.booksRepository.GetTagCounts(ctx, filter, limit)
+}
+
+// normalizeBookFilter normalizes the tag of the filter and makes sure its
+// subject exists.
+func (l *libraryImpl) normalizeBookFilter(ctx context.Context, filter entity.BookFilter) (entity.BookFilter, error) {
+	if filter.SubjectID != "" {
+		if _, err := l.subjectsRepository.GetSubject(ctx, filter.SubjectID); err != nil {
+			return entity.BookFilter{}, err
+		}
+	}
+
+	if filter.Title != "" {
+		filter.Title = entity.NormalizeTitle(filter.Title)
+	}
+
+	var err error
+	filter.Tag, err = normalizeOptional(filter.Tag, entity.NormalizeTag)
+
+	return filter, err
+}
+
+func normalizeBookMetadata(metadata entity.BookMetadata) (entity.BookMetadata, error) {
+	var err error
+
+	if metadata.ISBN, err = normalizeOptional(metadata.ISBN, entity.NormalizeISBN); err != nil {
+		return entity.BookMetadata{}, err
+	}
+
+	if metadata.Language, err = normalizeOptional(metadata.Language, entity.NormalizeLanguage); err != nil {
+		return entity.BookMetadata{}, err
+	}
+
+	if metadata.Tags, err = entity.NormalizeTags(metadata.Tags); err != nil {
+		return entity.BookMetadata{}, err
+	}
+
+	metadata.SubjectIDs = entity.NormalizeSubjectIDs(metadata.SubjectIDs)
+
+	return metadata, metadata.CheckSeriesPosition()
+}
+
+func normalizeBookPatch(patch entity.BookPatch) (entity.BookPatch, error) {
+	if patch.Contributors != nil {
+		contributors, err := entity.NormalizeContributors(*patch.Contributors)
+
+		if err != nil {
+			return entity.BookPatch{}, err
+		}
+
+		patch.Contributors = &contributors
+	}
+
+	if patch.ISBN != nil {
+		isbn, err := normalizeOptional(*patch.ISBN, entity.NormalizeISBN)
+
+		if err != nil {
+			return entity.BookPatch{}, err
+		}
+
+		patch.ISBN = &isbn
+	}
+
+	if patch.Language != nil {
+		tag, err := normalizeOptional(*patch.Language, entity.NormalizeLanguage)
+
+		if err != nil {
+			return entity.BookPatch{}, err
+		}
+
+		patch.Language = &tag
+	}
+
+	if patch.Tags != nil {
+		tags, err := entity.NormalizeTags(*patch.Tags)
+
+		if err != nil {
+			return entity.BookPatch{}, err
+		}
+
+		patch.Tags = &tags
+	}
+
+	if patch.SubjectIDs != nil {
+		subjectIDs := entity.NormalizeSubjectIDs(*patch.SubjectIDs)
+		patch.SubjectIDs = &subjectIDs
+	}
+
+	return patch, nil
+}
+
+// normalizeOptional keeps empty values, which stand for unknown ones.
+func normalizeOptional(value string, normalize func(string) (string, error)) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	return normalize(value)
+}
@@ -0,0 +1,92 @@
+package library
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+const (
+	catalogEventsBatchSize = 100
+	// catalogPollInterval bounds the delay of a watcher when a change
+	// notification gets lost.
+	catalogPollInterval = 5 * time.Second
+)
+
+func (l *libraryImpl) WatchCatalog(
+	ctx context.Context,
+	authorID string,
+	since *uint64,
+	handle func(event entity.CatalogEvent) error,
+) error {
+	// Subscribe before reading the cursor, so nothing committed in between
+	// is missed.
+	notifications := l.catalogRepository.SubscribeCatalogEvents(ctx)
+
+	cursor, err := l.startCursor(ctx, since)
+
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(catalogPollInterval)
+	defer ticker.Stop()
+
+	for {
+		cursor, err = l.sendCatalogEvents(ctx, authorID, cursor, handle)
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notifications:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *libraryImpl) startCursor(ctx context.Context, since *uint64) (uint64, error) {
+	if since != nil {
+		return *since, nil
+	}
+
+	return l.catalogRepository.GetLastCatalogCursor(ctx)
+}
+
+// sendCatalogEvents drains every event after cursor and returns the cursor
+// of the last one read.
+func (l *libraryImpl) sendCatalogEvents(
+	ctx context.Context,
+	authorID string,
+	cursor uint64,
+	handle func(event entity.CatalogEvent) error,
+) (uint64, error) {
+	for {
+		events, err := l.catalogRepository.GetCatalogEvents(ctx, cursor, catalogEventsBatchSize)
+
+		if err != nil {
+			return cursor, err
+		}
+
+		for _, event := range events {
+			cursor = event.Cursor
+
+			if authorID != "" && !slices.Contains(event.AuthorIDs, authorID) {
+				continue
+			}
+
+			if err = handle(event); err != nil {
+				return cursor, err
+			}
+		}
+
+		if len(events) < catalogEventsBatchSize {
+			return cursor, nil
+		}
+	}
+}
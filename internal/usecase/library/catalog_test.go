@@ -0,0 +1,170 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errStopWatching = errors.New("stop watching")
+
+const testMaxCoverSize = 64 << 10
+
+var testLoanPolicy = entity.LoanPolicy{
+	Periods: map[entity.MembershipType]time.Duration{
+		entity.MembershipTypeStandard: 21 * 24 * time.Hour,
+		entity.MembershipTypeStudent:  28 * 24 * time.Hour,
+	},
+	MaxActiveLoans: 2,
+	MaxRenewals:    1,
+	PickupWindow:   7 * 24 * time.Hour,
+	Fines: entity.FinePolicy{
+		DailyRate:  25,
+		Cap:        100,
+		MaxBalance: 50,
+	},
+}
+
+func newInMemoryLibrary() *libraryImpl {
+	repo := repository.NewInMemoryRepository()
+	return New(zap.NewNop(), Deps{
+		AuthorRepository:   repo,
+		BooksRepository:    repo,
+		SubjectsRepository: repo,
+		WorksRepository:    repo,
+		CopiesRepository:   repo,
+		PatronsRepository:  repo,
+		LoansRepository:    repo,
+		HoldsRepository:    repo,
+		ReviewsRepository:  repo,
+		FinesRepository:    repo,
+		CatalogRepository:  repo,
+		AuditRepository:    repo,
+		OutboxRepository:   repo,
+		BlobStore:          repo,
+		Transactor:         repo,
+		LoanPolicy:         testLoanPolicy,
+		MaxCoverSize:       testMaxCoverSize,
+	})
+}
+
+// collectEvents watches the catalog in the background and returns a function
+// that waits for count events.
+func collectEvents(
+	t *testing.T,
+	l *libraryImpl,
+	authorID string,
+	since *uint64,
+	count int,
+) func() []entity.CatalogEvent {
+	t.Helper()
+
+	events := make(chan entity.CatalogEvent, count)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- l.WatchCatalog(context.Background(), authorID, since, func(event entity.CatalogEvent) error {
+			events <- event
+
+			if len(events) == count {
+				return errStopWatching
+			}
+
+			return nil
+		})
+	}()
+
+	return func() []entity.CatalogEvent {
+		select {
+		case err := <-done:
+			require.ErrorIs(t, err, errStopWatching)
+		case <-time.After(time.Second):
+			require.FailNow(t, "catalog events were not received in time")
+		}
+
+		close(events)
+		result := make([]entity.CatalogEvent, 0, count)
+
+		for event := range events {
+			result = append(result, event)
+		}
+
+		return result
+	}
+}
+
+func TestWatchCatalogFiltersByAuthor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterAuthor(ctx, "First", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	second, err := l.RegisterAuthor(ctx, "Second", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	since, err := l.catalogRepository.GetLastCatalogCursor(ctx)
+	require.NoError(t, err)
+
+	wait := collectEvents(t, l, first.ID, &since, 3)
+
+	book, err := l.RegisterBook(ctx, "Book", entity.AuthorContributors([]string{first.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.ChangeAuthorInfo(ctx, second.ID, entity.AuthorPatch{Name: ptr("Other")}, nil)
+	require.NoError(t, err)
+
+	_, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("Renamed"), Contributors: &[]entity.Contributor{{AuthorID: second.ID}}}, nil)
+	require.NoError(t, err)
+
+	_, err = l.ChangeAuthorInfo(ctx, first.ID, entity.AuthorPatch{Name: ptr("Renamed")}, nil)
+	require.NoError(t, err)
+
+	events := wait()
+
+	require.Equal(t, entity.CatalogEventCreated, events[0].Kind)
+	require.Equal(t, book.ID, events[0].Book.ID)
+
+	// The update unlinks the author, but they still see it.
+	require.Equal(t, entity.CatalogEventUpdated, events[1].Kind)
+	require.Equal(t, "Renamed", events[1].Book.Name)
+
+	require.Equal(t, entity.CatalogEventUpdated, events[2].Kind)
+	require.Equal(t, first.ID, events[2].Author.ID)
+}
+
+func TestWatchCatalogResumesFromCursor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "First", entity.AuthorContributors([]string{author.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Second", entity.AuthorContributors([]string{author.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	since := uint64(0)
+	events := collectEvents(t, l, "", &since, 3)()
+
+	require.Equal(t, author.ID, events[0].Author.ID)
+	require.Equal(t, "First", events[1].Book.Name)
+	require.Equal(t, "Second", events[2].Book.Name)
+
+	since = events[1].Cursor
+	resumed := collectEvents(t, l, "", &since, 1)()
+
+	require.Equal(t, events[2], resumed[0])
+}
@@ -0,0 +1,93 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuthorBooksByRole(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	writer, err := l.RegisterAuthor(ctx, "Writer", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	translator, err := l.RegisterAuthor(ctx, "Translator", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	original, err := l.RegisterBook(ctx, "Original", entity.AuthorContributors([]string{translator.ID}),
+		entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	translated, err := l.RegisterBook(ctx, "Translated", []entity.Contributor{
+		{AuthorID: writer.ID},
+		{AuthorID: translator.ID, Role: entity.ContributorRoleTranslator},
+	}, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{writer.ID, translator.ID}, translated.AuthorIDs)
+	require.Equal(t, []entity.Contributor{
+		{AuthorID: writer.ID, Role: entity.ContributorRoleAuthor, Position: 0},
+		{AuthorID: translator.ID, Role: entity.ContributorRoleTranslator, Position: 1},
+	}, translated.Contributors)
+
+	books, err := l.GetAuthorBooks(ctx, translator.ID, entity.ContributorRoleTranslator, nil)
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	require.Equal(t, translated.ID, books[0].ID)
+
+	books, err = l.GetAuthorBooks(ctx, translator.ID, entity.ContributorRoleAuthor, nil)
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	require.Equal(t, original.ID, books[0].ID)
+
+	books, err = l.GetAuthorBooks(ctx, translator.ID, "", nil)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+
+	_, err = l.GetAuthorBooks(ctx, translator.ID, "narrator", nil)
+	require.ErrorIs(t, err, entity.ErrInvalidContributorRole)
+}
+
+func TestUpdateBookCreditOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterAuthor(ctx, "First", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	second, err := l.RegisterAuthor(ctx, "Second", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	book, err := l.RegisterBook(ctx, "Book", []entity.Contributor{
+		{AuthorID: first.ID},
+		{AuthorID: first.ID, Role: entity.ContributorRoleIllustrator},
+	}, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	// Added authors are credited last and removed ones lose every role.
+	updated, err := l.UpdateBook(ctx, book.ID, entity.BookPatch{
+		AddAuthorIDs:    []string{second.ID, first.ID},
+		RemoveAuthorIDs: []string{first.ID},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Contributor{
+		{AuthorID: second.ID, Role: entity.ContributorRoleAuthor, Position: 0},
+		{AuthorID: first.ID, Role: entity.ContributorRoleAuthor, Position: 1},
+	}, updated.Contributors)
+
+	updated, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{
+		Contributors: &[]entity.Contributor{{AuthorID: first.ID, Role: entity.ContributorRoleEditor}},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Contributor{
+		{AuthorID: first.ID, Role: entity.ContributorRoleEditor, Position: 0},
+	}, updated.Contributors)
+	require.Equal(t, []string{first.ID}, updated.AuthorIDs)
+}
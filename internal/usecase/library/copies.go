@@ -0,0 +1,182 @@
+package library
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) AddCopy(ctx context.Context, bookCopy entity.Copy) (entity.Copy, error) {
+	bookCopy.Condition = cmp.Or(bookCopy.Condition, entity.CopyConditionGood)
+	bookCopy.Status = cmp.Or(bookCopy.Status, entity.CopyStatusAvailable)
+
+	err := checkCopyPatch(entity.CopyPatch{
+		Condition:  &bookCopy.Condition,
+		Status:     &bookCopy.Status,
+		AcquiredOn: &bookCopy.AcquiredOn,
+	})
+
+	if err != nil {
+		return entity.Copy{}, err
+	}
+
+	// Copies go on loan and on hold by circulation only.
+	if bookCopy.Status == entity.CopyStatusOnLoan || bookCopy.Status == entity.CopyStatusOnHold {
+		return entity.Copy{}, entity.ErrInvalidCopyStatus
+	}
+
+	var created entity.Copy
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		created, txErr = l.copiesRepository.CreateCopy(ctx, bookCopy)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, entity.AuditOperationAddCopy, entity.AuditEntityCopy, created.ID, nil, created)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.serveHolds(ctx, created)
+	})
+
+	if err != nil {
+		l.logger.Error("can not add copy", zap.Error(err))
+		return entity.Copy{}, err
+	}
+
+	return created, nil
+}
+
+func (l *libraryImpl) GetCopy(ctx context.Context, copyID string) (entity.Copy, error) {
+	return l.copiesRepository.GetCopy(ctx, copyID)
+}
+
+func (l *libraryImpl) UpdateCopy(ctx context.Context, copyID string, patch entity.CopyPatch) (entity.Copy, error) {
+	if err := checkCopyPatch(patch); err != nil {
+		return entity.Copy{}, err
+	}
+
+	var updated entity.Copy
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// Circulation must not change the status between the check and the
+		// update.
+		previous, txErr := l.copiesRepository.LockCopy(ctx, copyID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if patch.Status != nil {
+			if txErr = checkCirculation(previous, patch.Status); txErr != nil {
+				return txErr
+			}
+		}
+
+		updated, txErr = l.copiesRepository.UpdateCopy(ctx, copyID, patch)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.appendAudit(ctx, entity.AuditOperationUpdateCopy, entity.AuditEntityCopy, copyID, previous, updated)
+
+		if txErr != nil || previous.Status == entity.CopyStatusAvailable {
+			return txErr
+		}
+
+		return l.serveHolds(ctx, updated)
+	})
+
+	if err != nil {
+		return entity.Copy{}, err
+	}
+
+	return updated, nil
+}
+
+func (l *libraryImpl) DeleteCopy(ctx context.Context, copyID string) error {
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.copiesRepository.LockCopy(ctx, copyID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = checkCirculation(previous, nil); txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.copiesRepository.DeleteCopy(ctx, copyID); txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationDeleteCopy, entity.AuditEntityCopy, copyID, previous, nil)
+	})
+}
+
+func (l *libraryImpl) ListBookCopies(ctx context.Context, bookID string) ([]entity.Copy, error) {
+	if _, err := l.booksRepository.GetBook(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	return l.copiesRepository.GetBookCopies(ctx, bookID)
+}
+
+func (l *libraryImpl) GetBookAvailability(ctx context.Context, bookID string) (entity.Availability, error) {
+	return l.copiesRepository.GetBookAvailability(ctx, bookID)
+}
+
+func checkCopyPatch(patch entity.CopyPatch) error {
+	if patch.Condition != nil && !patch.Condition.Valid() {
+		return entity.ErrInvalidCopyCondition
+	}
+
+	if patch.Status != nil && !patch.Status.Valid() {
+		return entity.ErrInvalidCopyStatus
+	}
+
+	if patch.AcquiredOn != nil {
+		return checkDates(*patch.AcquiredOn)
+	}
+
+	return nil
+}
+
+// checkCirculation keeps copies from being taken on or off loan or hold
+// other than by circulation. A nil status stands for the deletion of the
+// copy.
+func checkCirculation(previous entity.Copy, status *entity.CopyStatus) error {
+	if status != nil && *status == previous.Status {
+		return nil
+	}
+
+	switch {
+	case previous.Status == entity.CopyStatusOnLoan:
+		return entity.ErrCopyOnLoan
+	case previous.Status == entity.CopyStatusOnHold:
+		return entity.ErrCopyHeld
+	case status != nil && (*status == entity.CopyStatusOnLoan || *status == entity.CopyStatusOnHold):
+		return entity.ErrInvalidCopyStatus
+	default:
+		return nil
+	}
+}
+
+// serveHolds puts the copy aside for the next hold of its book when it is
+// available.
+func (l *libraryImpl) serveHolds(ctx context.Context, bookCopy entity.Copy) error {
+	if bookCopy.Status != entity.CopyStatusAvailable {
+		return nil
+	}
+
+	return l.assignCopy(ctx, bookCopy.ID, time.Now().UTC())
+}
@@ -0,0 +1,62 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "Book", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	first, err := l.AddCopy(ctx, entity.Copy{BookID: book.ID, Barcode: "B-2", Branch: "Main", AcquiredOn: "2020-01-31"})
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyConditionGood, first.Condition)
+	require.Equal(t, entity.CopyStatusAvailable, first.Status)
+
+	second, err := l.AddCopy(ctx, entity.Copy{BookID: book.ID, Barcode: "B-1", Status: entity.CopyStatusLost})
+	require.NoError(t, err)
+
+	_, err = l.AddCopy(ctx, entity.Copy{BookID: book.ID, Barcode: "B-1"})
+	require.ErrorIs(t, err, entity.ErrBarcodeTaken)
+
+	_, err = l.AddCopy(ctx, entity.Copy{BookID: "unknown", Barcode: "B-3"})
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+
+	_, err = l.AddCopy(ctx, entity.Copy{BookID: book.ID, Barcode: "B-3", AcquiredOn: "2020-02-30"})
+	require.ErrorIs(t, err, entity.ErrInvalidDate)
+
+	copies, err := l.ListBookCopies(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Copy{second, first}, copies)
+
+	status := entity.CopyStatusWithdrawn
+	updated, err := l.UpdateCopy(ctx, first.ID, entity.CopyPatch{Status: &status})
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyStatusWithdrawn, updated.Status)
+	require.Equal(t, "Main", updated.Branch)
+
+	_, err = l.UpdateCopy(ctx, first.ID, entity.CopyPatch{Barcode: ptr("B-1")})
+	require.ErrorIs(t, err, entity.ErrBarcodeTaken)
+
+	availability, err := l.GetBookAvailability(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.Availability{Total: 2, Lost: 1, Withdrawn: 1}, availability)
+
+	require.NoError(t, l.DeleteCopy(ctx, second.ID))
+	require.ErrorIs(t, l.DeleteCopy(ctx, second.ID), entity.ErrCopyNotFound)
+
+	_, err = l.GetCopy(ctx, second.ID)
+	require.ErrorIs(t, err, entity.ErrCopyNotFound)
+
+	_, err = l.ListBookCopies(ctx, "unknown")
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+}
@@ -0,0 +1,95 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) UploadCover(
+	ctx context.Context,
+	bookID string,
+	contentType entity.CoverContentType,
+	content io.Reader,
+) (entity.Book, error) {
+	if !contentType.Valid() {
+		return entity.Book{}, entity.ErrUnsupportedContentType
+	}
+
+	if _, err := l.booksRepository.GetBook(ctx, bookID); err != nil {
+		return entity.Book{}, err
+	}
+
+	original, err := l.readCover(content)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	thumbnails, err := renderThumbnails(original, contentType)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	// Every upload gets new keys, so cached images are never stale. Earlier
+	// covers are kept, the versions of the book in its history link to them.
+	prefix := "covers/" + bookID + "/" + uuid.NewString() + "/"
+	extension := coverExtensions[contentType]
+	cover := entity.Cover{OriginalURL: l.blobStore.BlobURL(prefix + "original" + extension)}
+	keys := []string{prefix + "original" + extension}
+	blobs := [][]byte{original}
+
+	for i, thumbnail := range entity.CoverThumbnails {
+		key := prefix + thumbnail.Name + extension
+		cover.SetThumbnailURL(thumbnail, l.blobStore.BlobURL(key))
+		keys = append(keys, key)
+		blobs = append(blobs, thumbnails[i])
+	}
+
+	for i, key := range keys {
+		if err = l.blobStore.PutBlob(ctx, key, string(contentType), blobs[i]); err != nil {
+			l.deleteBlobs(ctx, keys[:i])
+			return entity.Book{}, err
+		}
+	}
+
+	book, err := l.updateBook(ctx, entity.AuditOperationUploadCover, bookID, entity.BookPatch{Cover: &cover}, nil)
+
+	if err != nil {
+		l.deleteBlobs(ctx, keys)
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+// readCover reads the whole cover, failing with entity.ErrCoverTooLarge as
+// soon as it exceeds the limit.
+func (l *libraryImpl) readCover(content io.Reader) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if _, err := buffer.ReadFrom(io.LimitReader(content, l.maxCoverSize+1)); err != nil {
+		return nil, err
+	}
+
+	if int64(buffer.Len()) > l.maxCoverSize {
+		return nil, entity.ErrCoverTooLarge
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// deleteBlobs removes the blobs of a failed upload. Failures are only
+// logged, the upload has failed already.
+func (l *libraryImpl) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := l.blobStore.DeleteBlob(context.WithoutCancel(ctx), key); err != nil {
+			l.logger.Error("can not delete blob", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
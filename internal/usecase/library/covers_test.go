@@ -0,0 +1,135 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestImage(t *testing.T, width int, height int, contentType entity.CoverContentType) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	if contentType == entity.CoverContentTypePNG {
+		require.NoError(t, png.Encode(&buffer, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buffer, img, nil))
+	}
+
+	return buffer.Bytes()
+}
+
+func TestUploadCover(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	content := encodeTestImage(t, 800, 400, entity.CoverContentTypeJPEG)
+	updated, err := l.UploadCover(ctx, book.ID, entity.CoverContentTypeJPEG, bytes.NewReader(content))
+	require.NoError(t, err)
+	require.Equal(t, book.Version+1, updated.Version)
+
+	found, err := l.GetBookInfo(ctx, book.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, updated.Cover, found.Cover)
+
+	sizes := map[string]image.Point{
+		updated.Cover.OriginalURL: {X: 800, Y: 400},
+		updated.Cover.LargeURL:    {X: 600, Y: 300},
+		updated.Cover.MediumURL:   {X: 300, Y: 150},
+		updated.Cover.SmallURL:    {X: 100, Y: 50},
+	}
+
+	for url, size := range sizes {
+		require.True(t, strings.HasSuffix(url, ".jpg"), url)
+
+		data, contentType, err := l.blobStore.GetBlob(ctx, strings.TrimPrefix(url, "/covers/"))
+		require.NoError(t, err)
+		require.Equal(t, string(entity.CoverContentTypeJPEG), contentType)
+
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, "jpeg", format)
+		require.Equal(t, size, image.Point{X: config.Width, Y: config.Height})
+	}
+}
+
+func TestUploadCoverKeepsSmallImages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	content := encodeTestImage(t, 150, 200, entity.CoverContentTypePNG)
+	updated, err := l.UploadCover(ctx, book.ID, entity.CoverContentTypePNG, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	for url, size := range map[string]image.Point{
+		updated.Cover.LargeURL:  {X: 150, Y: 200},
+		updated.Cover.MediumURL: {X: 150, Y: 200},
+		updated.Cover.SmallURL:  {X: 75, Y: 100},
+	} {
+		data, _, err := l.blobStore.GetBlob(ctx, strings.TrimPrefix(url, "/covers/"))
+		require.NoError(t, err)
+
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, size, image.Point{X: config.Width, Y: config.Height})
+	}
+}
+
+func TestUploadCoverRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	jpegContent := encodeTestImage(t, 10, 10, entity.CoverContentTypeJPEG)
+
+	_, err = l.UploadCover(ctx, book.ID, "image/gif", bytes.NewReader(jpegContent))
+	require.ErrorIs(t, err, entity.ErrUnsupportedContentType)
+
+	_, err = l.UploadCover(ctx, book.ID, entity.CoverContentTypePNG, bytes.NewReader(jpegContent))
+	require.ErrorIs(t, err, entity.ErrUnsupportedContentType)
+
+	_, err = l.UploadCover(ctx, book.ID, entity.CoverContentTypeJPEG, bytes.NewReader(jpegContent[:len(jpegContent)/2]))
+	require.ErrorIs(t, err, entity.ErrInvalidCover)
+
+	_, err = l.UploadCover(ctx, book.ID, entity.CoverContentTypeJPEG, bytes.NewReader(make([]byte, testMaxCoverSize+1)))
+	require.ErrorIs(t, err, entity.ErrCoverTooLarge)
+
+	_, err = l.UploadCover(ctx, "unknown", entity.CoverContentTypeJPEG, bytes.NewReader(jpegContent))
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+
+	found, err := l.GetBookInfo(ctx, book.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, entity.Cover{}, found.Cover)
+	require.Equal(t, book.Version, found.Version)
+}
@@ -0,0 +1,161 @@
+package library
+
+import (
+	"context"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+// overdueLoansBatch is the number of overdue loans read at once.
+const overdueLoansBatch = 100
+
+// fineBalance is what the audit log keeps of a payment or a waiver.
+type fineBalance struct {
+	Balance     int64
+	Transaction *entity.LedgerTransaction `json:",omitempty"`
+}
+
+func (l *libraryImpl) GetPatronBalance(ctx context.Context, patronID string) (int64, error) {
+	return l.finesRepository.GetPatronBalance(ctx, patronID)
+}
+
+func (l *libraryImpl) PayFine(ctx context.Context, patronID string, amount int64, note string) (int64, error) {
+	return l.settleFine(ctx, entity.AuditOperationPayFine, entity.LedgerEntryPayment, patronID, amount, note)
+}
+
+func (l *libraryImpl) WaiveFine(ctx context.Context, patronID string, amount int64, reason string) (int64, error) {
+	return l.settleFine(ctx, entity.AuditOperationWaiveFine, entity.LedgerEntryWaiver, patronID, amount, reason)
+}
+
+// settleFine takes the amount off the balance of the patron and returns the
+// new balance.
+func (l *libraryImpl) settleFine(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	kind entity.LedgerEntryKind,
+	patronID string,
+	amount int64,
+	note string,
+) (int64, error) {
+	if amount <= 0 {
+		return 0, entity.ErrInvalidAmount
+	}
+
+	var balance int64
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.finesRepository.LockPatronBalance(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if amount > previous {
+			return entity.ErrAmountExceedsBalance
+		}
+
+		transaction := entity.NewFineTransaction(kind, patronID, amount)
+		transaction.Note = note
+		transaction.CreatedAt = time.Now().UTC()
+		transaction, txErr = l.finesRepository.PostLedgerTransaction(ctx, transaction)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		balance = previous - amount
+
+		return l.appendAudit(ctx, operation, entity.AuditEntityPatron, patronID,
+			fineBalance{Balance: previous},
+			fineBalance{Balance: balance, Transaction: &transaction},
+		)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (l *libraryImpl) AccrueFines(ctx context.Context) (int, error) {
+	charged := 0
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		locked, txErr := l.finesRepository.TryLockFines(ctx)
+
+		if txErr != nil || !locked {
+			return txErr
+		}
+
+		charged, txErr = l.accrueOverdueFines(ctx, time.Now().UTC())
+
+		return txErr
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return charged, nil
+}
+
+// accrueOverdueFines charges the fines of all overdue loans up to now and
+// returns how many loans were charged.
+func (l *libraryImpl) accrueOverdueFines(ctx context.Context, now time.Time) (int, error) {
+	charged := 0
+	afterID := ""
+
+	for {
+		loans, err := l.finesRepository.GetOverdueLoans(ctx, now, l.loanPolicy.Fines.Cap, afterID, overdueLoansBatch)
+
+		if err != nil {
+			return charged, err
+		}
+
+		for _, loan := range loans {
+			var accrued bool
+
+			if accrued, err = l.accrueFine(ctx, loan.ID, now); err != nil {
+				return charged, err
+			}
+
+			if accrued {
+				charged++
+			}
+		}
+
+		if len(loans) < overdueLoansBatch {
+			return charged, nil
+		}
+
+		afterID = loans[len(loans)-1].ID
+	}
+}
+
+// accrueFine charges the part of the fine of the loan at now that has not
+// been charged yet and reports whether there was any.
+func (l *libraryImpl) accrueFine(ctx context.Context, loanID string, now time.Time) (bool, error) {
+	loan, accrued, err := l.finesRepository.LockLoanFines(ctx, loanID)
+
+	if err != nil {
+		return false, err
+	}
+
+	fine := l.loanPolicy.Fines.Fine(loan, now)
+
+	if fine <= accrued {
+		return false, nil
+	}
+
+	transaction := entity.NewFineTransaction(entity.LedgerEntryAccrual, loan.PatronID, fine-accrued)
+	transaction.LoanID = loan.ID
+	transaction.CreatedAt = now
+
+	if _, err = l.finesRepository.PostLedgerTransaction(ctx, transaction); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,157 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// newOverdueLibrary returns a library lending copies due the given number of
+// days ago.
+func newOverdueLibrary(days int) *libraryImpl {
+	l := newInMemoryLibrary()
+	l.loanPolicy.Periods = map[entity.MembershipType]time.Duration{
+		entity.MembershipTypeStandard: -time.Duration(days) * 24 * time.Hour,
+	}
+
+	return l
+}
+
+func TestAccrueFines(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newOverdueLibrary(3)
+	copies := addCopies(t, l, 2)
+	patron := registerPatrons(t, l, 1)[0]
+
+	_, err := l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.NoError(t, err)
+
+	charged, err := l.AccrueFines(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, charged)
+
+	balance, err := l.GetPatronBalance(ctx, patron.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(75), balance)
+
+	// Fines are charged once a day.
+	charged, err = l.AccrueFines(ctx)
+	require.NoError(t, err)
+	require.Zero(t, charged)
+
+	_, err = l.CheckoutCopy(ctx, copies[1].ID, patron.ID)
+	require.ErrorIs(t, err, entity.ErrFinesOutstanding)
+
+	_, err = l.GetPatronBalance(ctx, "unknown")
+	require.ErrorIs(t, err, entity.ErrPatronNotFound)
+}
+
+func TestAccrueFinesCap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newOverdueLibrary(10)
+	bookCopy := addCopies(t, l, 1)[0]
+	patron := registerPatrons(t, l, 1)[0]
+
+	_, err := l.CheckoutCopy(ctx, bookCopy.ID, patron.ID)
+	require.NoError(t, err)
+
+	charged, err := l.AccrueFines(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, charged)
+
+	balance, err := l.GetPatronBalance(ctx, patron.ID)
+	require.NoError(t, err)
+	require.Equal(t, testLoanPolicy.Fines.Cap, balance)
+
+	// Returning the copy adds nothing over the cap.
+	_, err = l.ReturnCopy(ctx, bookCopy.ID)
+	require.NoError(t, err)
+
+	balance, err = l.GetPatronBalance(ctx, patron.ID)
+	require.NoError(t, err)
+	require.Equal(t, testLoanPolicy.Fines.Cap, balance)
+}
+
+func TestReturnCopyChargesFine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newOverdueLibrary(2)
+	bookCopy := addCopies(t, l, 1)[0]
+	patron := registerPatrons(t, l, 1)[0]
+
+	_, err := l.CheckoutCopy(ctx, bookCopy.ID, patron.ID)
+	require.NoError(t, err)
+
+	_, err = l.ReturnCopy(ctx, bookCopy.ID)
+	require.NoError(t, err)
+
+	balance, err := l.GetPatronBalance(ctx, patron.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), balance)
+
+	// Returned loans are not charged again.
+	charged, err := l.AccrueFines(ctx)
+	require.NoError(t, err)
+	require.Zero(t, charged)
+}
+
+func TestPayAndWaiveFine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newOverdueLibrary(4)
+	copies := addCopies(t, l, 2)
+	patron := registerPatrons(t, l, 1)[0]
+
+	_, err := l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.NoError(t, err)
+
+	_, err = l.ReturnCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+
+	_, err = l.PayFine(ctx, patron.ID, 101, "")
+	require.ErrorIs(t, err, entity.ErrAmountExceedsBalance)
+
+	_, err = l.PayFine(ctx, patron.ID, 0, "")
+	require.ErrorIs(t, err, entity.ErrInvalidAmount)
+
+	_, err = l.WaiveFine(ctx, "unknown", 1, "reason")
+	require.ErrorIs(t, err, entity.ErrPatronNotFound)
+
+	balance, err := l.PayFine(ctx, patron.ID, 60, "cash at the desk")
+	require.NoError(t, err)
+	require.Equal(t, int64(40), balance)
+
+	// The balance is below the limit again.
+	_, err = l.CheckoutCopy(ctx, copies[1].ID, patron.ID)
+	require.NoError(t, err)
+
+	balance, err = l.WaiveFine(ctx, patron.ID, 40, "first offence")
+	require.NoError(t, err)
+	require.Zero(t, balance)
+
+	records, err := l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityPatron, patron.ID, 0, 100)
+	require.NoError(t, err)
+
+	operations := make([]entity.AuditOperation, 0, len(records))
+
+	for _, record := range records {
+		operations = append(operations, record.Operation)
+	}
+
+	require.Equal(t, []entity.AuditOperation{
+		entity.AuditOperationRegisterPatron,
+		entity.AuditOperationPayFine,
+		entity.AuditOperationWaiveFine,
+	}, operations)
+	require.JSONEq(t, `{"Balance":100}`, string(records[1].Before))
+	require.Contains(t, string(records[2].After), `"first offence"`)
+}
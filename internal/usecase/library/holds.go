@@ -0,0 +1,280 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// expiredHoldsBatch limits the holds expired by one ExpireHolds call.
+const expiredHoldsBatch = 100
+
+func (l *libraryImpl) PlaceHold(ctx context.Context, bookID string, patronID string) (entity.Hold, error) {
+	var hold entity.Hold
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		patron, txErr := l.patronsRepository.GetPatron(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		now := time.Now().UTC()
+
+		if txErr = checkBorrower(patron, now); txErr != nil {
+			return txErr
+		}
+
+		hold, txErr = l.holdsRepository.CreateHold(ctx, entity.Hold{
+			BookID:   bookID,
+			PatronID: patronID,
+			Status:   entity.HoldStatusWaiting,
+			PlacedAt: now,
+		})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.recordHoldChange(ctx, entity.AuditOperationPlaceHold, entity.HoldEventPlaced, nil, hold)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		// Copies are normally put aside as soon as they become available,
+		// this serves the hold right away when the book is on the shelf.
+		copies, txErr := l.copiesRepository.GetBookCopies(ctx, bookID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		for _, bookCopy := range copies {
+			if bookCopy.Status != entity.CopyStatusAvailable {
+				continue
+			}
+
+			if txErr = l.assignCopy(ctx, bookCopy.ID, now); txErr != nil {
+				return txErr
+			}
+		}
+
+		hold, txErr = l.holdsRepository.GetHold(ctx, hold.ID)
+
+		return txErr
+	})
+
+	if err != nil {
+		return entity.Hold{}, err
+	}
+
+	return hold, nil
+}
+
+func (l *libraryImpl) CancelHold(ctx context.Context, holdID string) (entity.Hold, error) {
+	var hold entity.Hold
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// A copy put aside for the hold in the meantime would not be passed
+		// on to the next hold.
+		previous, txErr := l.holdsRepository.LockHold(ctx, holdID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		hold, txErr = l.releaseHold(ctx, previous, entity.HoldStatusCancelled, time.Now().UTC())
+
+		return txErr
+	})
+
+	if err != nil {
+		return entity.Hold{}, err
+	}
+
+	return hold, nil
+}
+
+// fulfillHolds closes the active holds of the patron on the book of the copy
+// lent to them. A copy put aside for another patron is not lent.
+func (l *libraryImpl) fulfillHolds(ctx context.Context, bookCopy entity.Copy, patronID string, now time.Time) error {
+	if bookCopy.Status == entity.CopyStatusOnHold {
+		hold, err := l.holdsRepository.GetCopyHold(ctx, bookCopy.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if hold.PatronID != patronID {
+			return entity.ErrCopyHeld
+		}
+	}
+
+	holds, err := l.holdsRepository.ListHolds(ctx, entity.HoldFilter{
+		BookID:     bookCopy.BookID,
+		PatronID:   patronID,
+		ActiveOnly: true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, previous := range holds {
+		hold, closeErr := l.closeHold(ctx, previous, entity.HoldStatusFulfilled, now)
+
+		if closeErr != nil {
+			return closeErr
+		}
+
+		// The patron has taken another copy than the one put aside for them.
+		if previous.Status == entity.HoldStatusReady && hold.CopyID != bookCopy.ID {
+			if closeErr = l.assignCopy(ctx, hold.CopyID, now); closeErr != nil {
+				return closeErr
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *libraryImpl) ListHolds(ctx context.Context, filter entity.HoldFilter) ([]entity.Hold, error) {
+	return l.holdsRepository.ListHolds(ctx, filter)
+}
+
+func (l *libraryImpl) ExpireHolds(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	holds, err := l.holdsRepository.GetExpiredHolds(ctx, now, expiredHoldsBatch)
+
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+
+	for _, hold := range holds {
+		err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+			_, txErr := l.releaseHold(ctx, hold, entity.HoldStatusExpired, now)
+			return txErr
+		})
+
+		// The hold may have been picked up or expired by another replica
+		// in the meantime.
+		if errors.Is(err, entity.ErrHoldNotActive) {
+			continue
+		}
+
+		if err != nil {
+			return expired, err
+		}
+
+		expired++
+	}
+
+	return expired, nil
+}
+
+// releaseHold closes the active hold with the status and passes the copy put
+// aside for it to the next hold of the book.
+func (l *libraryImpl) releaseHold(
+	ctx context.Context,
+	previous entity.Hold,
+	status entity.HoldStatus,
+	now time.Time,
+) (entity.Hold, error) {
+	hold, err := l.closeHold(ctx, previous, status, now)
+
+	if err != nil {
+		return entity.Hold{}, err
+	}
+
+	if previous.Status != entity.HoldStatusReady {
+		return hold, nil
+	}
+
+	return hold, l.assignCopy(ctx, hold.CopyID, now)
+}
+
+var holdClosings = map[entity.HoldStatus]struct {
+	operation entity.AuditOperation
+	event     entity.HoldEventKind
+}{
+	entity.HoldStatusFulfilled: {entity.AuditOperationChangeHold, entity.HoldEventFulfilled},
+	entity.HoldStatusCancelled: {entity.AuditOperationCancelHold, entity.HoldEventCancelled},
+	entity.HoldStatusExpired:   {entity.AuditOperationChangeHold, entity.HoldEventExpired},
+}
+
+// closeHold closes the active hold with the status and records the change.
+// The copy put aside for the hold becomes available.
+func (l *libraryImpl) closeHold(
+	ctx context.Context,
+	previous entity.Hold,
+	status entity.HoldStatus,
+	now time.Time,
+) (entity.Hold, error) {
+	if !previous.Active() {
+		return entity.Hold{}, entity.ErrHoldNotActive
+	}
+
+	hold, err := l.holdsRepository.CloseHold(ctx, previous.ID, status, now)
+
+	if err != nil {
+		return entity.Hold{}, err
+	}
+
+	closing := holdClosings[status]
+
+	return hold, l.recordHoldChange(ctx, closing.operation, closing.event, previous, hold)
+}
+
+// assignCopy puts the available copy aside for the next hold of its book, if
+// there is one.
+func (l *libraryImpl) assignCopy(ctx context.Context, copyID string, now time.Time) error {
+	hold, err := l.holdsRepository.AssignCopy(ctx, copyID, now.Add(l.loanPolicy.PickupWindow))
+
+	if errors.Is(err, entity.ErrHoldNotFound) || errors.Is(err, entity.ErrCopyNotAvailable) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	previous := hold
+	previous.Status = entity.HoldStatusWaiting
+	previous.CopyID = ""
+	previous.PickupBy = nil
+
+	return l.recordHoldChange(ctx, entity.AuditOperationChangeHold, entity.HoldEventReady, previous, hold)
+}
+
+// recordHoldChange audits the change of the hold and notifies the patron
+// through the outbox. Pass a nil previous for new holds.
+func (l *libraryImpl) recordHoldChange(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	kind entity.HoldEventKind,
+	previous any,
+	hold entity.Hold,
+) error {
+	serialized, err := json.Marshal(entity.HoldEvent{Kind: kind, Hold: hold})
+
+	if err != nil {
+		return err
+	}
+
+	// Every change happens at most once per hold.
+	idempotencyKey := repository.OutboxKindHold.String() + "_" + hold.ID + "_" + string(kind)
+	err = l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindHold, serialized)
+
+	if err != nil {
+		return err
+	}
+
+	return l.appendAudit(ctx, operation, entity.AuditEntityHold, hold.ID, previous, hold)
+}
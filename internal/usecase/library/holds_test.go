@@ -0,0 +1,163 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// registerPatrons registers count patrons with distinct card numbers.
+func registerPatrons(t *testing.T, l *libraryImpl, count int) []entity.Patron {
+	t.Helper()
+
+	patrons := make([]entity.Patron, 0, count)
+
+	for i := range count {
+		patron, err := l.RegisterPatron(context.Background(), entity.Patron{CardNumber: "C-" + string(rune('A'+i))})
+		require.NoError(t, err)
+
+		patrons = append(patrons, patron)
+	}
+
+	return patrons
+}
+
+func TestHoldQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	bookCopy := addCopies(t, l, 1)[0]
+	patrons := registerPatrons(t, l, 3)
+
+	loan, err := l.CheckoutCopy(ctx, bookCopy.ID, patrons[0].ID)
+	require.NoError(t, err)
+
+	first, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusWaiting, first.Status)
+
+	second, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[2].ID)
+	require.NoError(t, err)
+
+	_, err = l.PlaceHold(ctx, bookCopy.BookID, patrons[2].ID)
+	require.ErrorIs(t, err, entity.ErrHoldExists)
+
+	holds, err := l.ListHolds(ctx, entity.HoldFilter{BookID: bookCopy.BookID, ActiveOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, []entity.Hold{first, second}, holds)
+
+	_, err = l.RenewLoan(ctx, loan.ID)
+	require.ErrorIs(t, err, entity.ErrHoldsWaiting)
+
+	_, err = l.ReturnCopy(ctx, bookCopy.ID)
+	require.NoError(t, err)
+
+	ready, err := l.holdsRepository.GetHold(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusReady, ready.Status)
+	require.Equal(t, bookCopy.ID, ready.CopyID)
+	require.NotNil(t, ready.PickupBy)
+
+	held, err := l.GetCopy(ctx, bookCopy.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyStatusOnHold, held.Status)
+
+	_, err = l.CheckoutCopy(ctx, bookCopy.ID, patrons[2].ID)
+	require.ErrorIs(t, err, entity.ErrCopyHeld)
+
+	withdrawn := entity.CopyStatusWithdrawn
+	_, err = l.UpdateCopy(ctx, bookCopy.ID, entity.CopyPatch{Status: &withdrawn})
+	require.ErrorIs(t, err, entity.ErrCopyHeld)
+
+	_, err = l.CheckoutCopy(ctx, bookCopy.ID, patrons[1].ID)
+	require.NoError(t, err)
+
+	fulfilled, err := l.holdsRepository.GetHold(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusFulfilled, fulfilled.Status)
+
+	holds, err = l.ListHolds(ctx, entity.HoldFilter{PatronID: patrons[2].ID, ActiveOnly: true})
+	require.NoError(t, err)
+	require.Equal(t, []entity.Hold{second}, holds)
+}
+
+func TestPlaceHoldOnAvailableCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	bookCopy := addCopies(t, l, 1)[0]
+	patrons := registerPatrons(t, l, 2)
+
+	first, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusReady, first.Status)
+	require.Equal(t, bookCopy.ID, first.CopyID)
+
+	second, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusWaiting, second.Status)
+
+	cancelled, err := l.CancelHold(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusCancelled, cancelled.Status)
+
+	_, err = l.CancelHold(ctx, first.ID)
+	require.ErrorIs(t, err, entity.ErrHoldNotActive)
+
+	// The copy goes to the next hold of the queue.
+	ready, err := l.holdsRepository.GetHold(ctx, second.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.HoldStatusReady, ready.Status)
+	require.Equal(t, bookCopy.ID, ready.CopyID)
+
+	_, err = l.PlaceHold(ctx, "unknown", patrons[0].ID)
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+}
+
+func TestExpireHolds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	l.loanPolicy.PickupWindow = -time.Second
+	bookCopy := addCopies(t, l, 1)[0]
+	patrons := registerPatrons(t, l, 2)
+
+	first, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[0].ID)
+	require.NoError(t, err)
+
+	second, err := l.PlaceHold(ctx, bookCopy.BookID, patrons[1].ID)
+	require.NoError(t, err)
+
+	expired, err := l.ExpireHolds(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+
+	holds, err := l.ListHolds(ctx, entity.HoldFilter{BookID: bookCopy.BookID})
+	require.NoError(t, err)
+	require.Len(t, holds, 2)
+	require.Equal(t, first.ID, holds[0].ID)
+	require.Equal(t, entity.HoldStatusExpired, holds[0].Status)
+	require.Equal(t, second.ID, holds[1].ID)
+	require.Equal(t, entity.HoldStatusReady, holds[1].Status)
+
+	messages, err := l.outboxRepository.GetMessages(ctx, 100, time.Minute)
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(messages))
+
+	for _, message := range messages {
+		keys = append(keys, message.IdempotencyKey)
+	}
+
+	require.Subset(t, keys, []string{
+		"hold_" + first.ID + "_ready",
+		"hold_" + first.ID + "_expired",
+		"hold_" + second.ID + "_ready",
+	})
+}
@@ -1 +1,327 @@
 package library
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+type (
+	AuthorUseCase interface {
+		RegisterAuthor(ctx context.Context, authorName string, profile entity.AuthorProfile) (entity.Author, error)
+		// GetAuthorInfo returns the current author, or the version that was
+		// current at asOf when it is set.
+		GetAuthorInfo(ctx context.Context, authorID string, asOf *time.Time) (entity.Author, error)
+		// ChangeAuthorInfo applies patch to the author. It fails with
+		// entity.ErrVersionMismatch when expectedVersion is set and the
+		// author has been changed since.
+		ChangeAuthorInfo(
+			ctx context.Context,
+			authorID string,
+			patch entity.AuthorPatch,
+			expectedVersion *uint64,
+		) (entity.Author, error)
+		// GetAuthorBooks returns the books crediting the author in the role,
+		// or in any role when it is empty. When asOf is set the books
+		// credited then are returned in the versions current then.
+		GetAuthorBooks(
+			ctx context.Context,
+			authorID string,
+			role entity.ContributorRole,
+			asOf *time.Time,
+		) ([]entity.Book, error)
+		GetAuthorByExternalID(ctx context.Context, scheme entity.ExternalIDScheme, value string) (entity.Author, error)
+		// MergeAuthors credits the target instead of the sources on all
+		// their books and deletes the sources. Their ids keep resolving to
+		// the target in GetAuthorInfo.
+		MergeAuthors(ctx context.Context, targetID string, sourceIDs []string) (entity.Author, error)
+		// FindDuplicateAuthors returns up to limit pairs of authors whose
+		// names have at least the trigram similarity, the most similar
+		// first.
+		FindDuplicateAuthors(ctx context.Context, minSimilarity float64, limit int) ([]entity.AuthorDuplicate, error)
+	}
+
+	BooksUseCase interface {
+		// RegisterBook credits the contributors in the order of the list,
+		// contributors without a role as authors. It fails with
+		// entity.DuplicateBookError when a book with the ISBN is already in
+		// the catalog, or one with the same normalized title and authors
+		// unless allowDuplicate is set.
+		RegisterBook(
+			ctx context.Context,
+			name string,
+			contributors []entity.Contributor,
+			metadata entity.BookMetadata,
+			allowDuplicate bool,
+		) (entity.Book, error)
+		// GetBookInfo returns the current book, or the version that was
+		// current at asOf when it is set.
+		GetBookInfo(ctx context.Context, bookID string, asOf *time.Time) (entity.Book, error)
+		// GetBookByISBN accepts both ISBN-10 and ISBN-13.
+		GetBookByISBN(ctx context.Context, isbn string) (entity.Book, error)
+		// UpdateBook applies patch to the book. It fails with
+		// entity.ErrVersionMismatch when expectedVersion is set and the book
+		// has been changed since.
+		UpdateBook(
+			ctx context.Context,
+			bookID string,
+			patch entity.BookPatch,
+			expectedVersion *uint64,
+		) (entity.Book, error)
+		// ListBooks returns up to limit books selected by the filter that
+		// sort after the book named afterName with the id afterID, ordered
+		// by name and id.
+		ListBooks(
+			ctx context.Context,
+			filter entity.BookFilter,
+			afterName string,
+			afterID string,
+			limit int,
+		) ([]entity.Book, error)
+		// ListTags returns up to limit tags of the books selected by the
+		// filter with the number of books carrying them, the most used
+		// first.
+		ListTags(ctx context.Context, filter entity.BookFilter, limit int) ([]entity.TagCount, error)
+		// UploadCover replaces the cover of the book with the image read
+		// from content and generates its thumbnails. It fails with
+		// entity.ErrCoverTooLarge, entity.ErrUnsupportedContentType or
+		// entity.ErrInvalidCover when the image is not accepted.
+		UploadCover(
+			ctx context.Context,
+			bookID string,
+			contentType entity.CoverContentType,
+			content io.Reader,
+		) (entity.Book, error)
+	}
+
+	SubjectsUseCase interface {
+		// CreateSubject puts the new subject under the parent, or at the
+		// top level when parentID is empty.
+		CreateSubject(ctx context.Context, name string, parentID string) (entity.Subject, error)
+		// UpdateSubject renames or moves the subject.
+		UpdateSubject(ctx context.Context, subjectID string, patch entity.SubjectPatch) (entity.Subject, error)
+		// DeleteSubject removes a subject without subtopics or books.
+		DeleteSubject(ctx context.Context, subjectID string) error
+		// ListSubjects returns the subject and all its descendants, or the
+		// whole taxonomy when rootID is empty, ordered by name.
+		ListSubjects(ctx context.Context, rootID string) ([]entity.Subject, error)
+	}
+
+	WorksUseCase interface {
+		CreateWork(ctx context.Context, title string) (entity.Work, error)
+		CreateSeries(ctx context.Context, name string) (entity.Series, error)
+		// GetWorkEditions returns the work with its books ordered by
+		// publication year, unknown years last.
+		GetWorkEditions(ctx context.Context, workID string) (entity.Work, []entity.Book, error)
+		// GetSeriesBooks returns the series with its books in reading order,
+		// unnumbered books last.
+		GetSeriesBooks(ctx context.Context, seriesID string) (entity.Series, []entity.Book, error)
+	}
+
+	CopiesUseCase interface {
+		// AddCopy adds a copy of a book. A copy without a condition is in
+		// good condition, one without a status is available.
+		AddCopy(ctx context.Context, bookCopy entity.Copy) (entity.Copy, error)
+		GetCopy(ctx context.Context, copyID string) (entity.Copy, error)
+		UpdateCopy(ctx context.Context, copyID string, patch entity.CopyPatch) (entity.Copy, error)
+		DeleteCopy(ctx context.Context, copyID string) error
+		// ListBookCopies returns the copies of the book ordered by barcode.
+		ListBookCopies(ctx context.Context, bookID string) ([]entity.Copy, error)
+		GetBookAvailability(ctx context.Context, bookID string) (entity.Availability, error)
+	}
+
+	PatronsUseCase interface {
+		// RegisterPatron registers an active patron. A patron without a
+		// membership type gets the standard one.
+		RegisterPatron(ctx context.Context, patron entity.Patron) (entity.Patron, error)
+		GetPatron(ctx context.Context, patronID string) (entity.Patron, error)
+		// ListPatrons returns up to limit patrons selected by the filter with
+		// card numbers greater than afterCardNumber, ordered by card number.
+		ListPatrons(
+			ctx context.Context,
+			filter entity.PatronFilter,
+			afterCardNumber string,
+			limit int,
+		) ([]entity.Patron, error)
+		// UpdatePatron applies patch to the patron, except for the status
+		// and the suspension reason.
+		UpdatePatron(ctx context.Context, patronID string, patch entity.PatronPatch) (entity.Patron, error)
+		// SuspendPatron fails with entity.ErrPatronAlreadySuspended when the
+		// patron is suspended already.
+		SuspendPatron(ctx context.Context, patronID string, reason string) (entity.Patron, error)
+		// ReinstatePatron fails with entity.ErrPatronNotSuspended when the
+		// patron is not suspended.
+		ReinstatePatron(ctx context.Context, patronID string) (entity.Patron, error)
+	}
+
+	LoansUseCase interface {
+		// CheckoutCopy lends the available copy to the patron for the loan
+		// period of their membership type.
+		CheckoutCopy(ctx context.Context, copyID string, patronID string) (entity.Loan, error)
+		// RenewLoan moves the due date to one loan period from now, unless
+		// other patrons wait for the book.
+		RenewLoan(ctx context.Context, loanID string) (entity.Loan, error)
+		// ReturnCopy ends the active loan of the copy and puts the copy
+		// aside for the next hold of its book.
+		ReturnCopy(ctx context.Context, copyID string) (entity.Loan, error)
+	}
+
+	HoldsUseCase interface {
+		// PlaceHold queues the patron for the book. The hold is ready right
+		// away when a copy is available.
+		PlaceHold(ctx context.Context, bookID string, patronID string) (entity.Hold, error)
+		// CancelHold passes the copy put aside for the hold, if any, to the
+		// next hold of the book.
+		CancelHold(ctx context.Context, holdID string) (entity.Hold, error)
+		// ListHolds returns the holds selected by the filter, oldest first.
+		ListHolds(ctx context.Context, filter entity.HoldFilter) ([]entity.Hold, error)
+		// ExpireHolds closes a batch of the ready holds whose pickup window
+		// has passed and returns how many were closed.
+		ExpireHolds(ctx context.Context) (int, error)
+	}
+
+	ReviewsUseCase interface {
+		// CreateReview fails with entity.ErrReviewExists when the patron has
+		// reviewed the book already.
+		CreateReview(ctx context.Context, review entity.Review) (entity.Review, error)
+		// UpdateReview, as CreateReview, fails with entity.ErrNotOwner when
+		// the caller may review as itself only and the review is another
+		// patron's.
+		UpdateReview(ctx context.Context, reviewID string, patch entity.ReviewPatch) (entity.Review, error)
+		DeleteReview(ctx context.Context, reviewID string) error
+		// ListReviews returns up to limit reviews selected by the filter,
+		// newest first, starting after the review created at afterCreatedAt
+		// with the id afterID when it is set.
+		ListReviews(
+			ctx context.Context,
+			filter entity.ReviewFilter,
+			afterCreatedAt time.Time,
+			afterID string,
+			limit int,
+		) ([]entity.Review, error)
+		// GetBookRating returns the aggregated rating of the reviews of the
+		// book.
+		GetBookRating(ctx context.Context, bookID string) (entity.BookRating, error)
+	}
+
+	FinesUseCase interface {
+		// GetPatronBalance returns what the patron owes, in cents.
+		GetPatronBalance(ctx context.Context, patronID string) (int64, error)
+		// PayFine records a payment of the patron and returns the new
+		// balance. The amount may not exceed the balance.
+		PayFine(ctx context.Context, patronID string, amount int64, note string) (int64, error)
+		// WaiveFine forgives part of the balance of the patron and returns
+		// the new balance. The amount may not exceed the balance.
+		WaiveFine(ctx context.Context, patronID string, amount int64, reason string) (int64, error)
+		// AccrueFines charges the fines of the overdue loans up to now and
+		// returns how many loans were charged. It does nothing while
+		// another replica accrues fines.
+		AccrueFines(ctx context.Context) (int, error)
+	}
+
+	HistoryUseCase interface {
+		// GetBookHistory returns up to limit audit records of the book with
+		// ids greater than afterID, oldest first.
+		GetBookHistory(ctx context.Context, bookID string, afterID uint64, limit int) ([]entity.AuditRecord, error)
+		// GetAuthorHistory returns up to limit audit records of the author
+		// with ids greater than afterID, oldest first.
+		GetAuthorHistory(ctx context.Context, authorID string, afterID uint64, limit int) ([]entity.AuditRecord, error)
+	}
+
+	CatalogUseCase interface {
+		// WatchCatalog calls handle for every catalog event after since, or
+		// after the current end of the feed when since is nil, until ctx is
+		// done or handle fails. A non-empty authorID keeps only the events
+		// relevant to that author.
+		WatchCatalog(
+			ctx context.Context,
+			authorID string,
+			since *uint64,
+			handle func(event entity.CatalogEvent) error,
+		) error
+	}
+)
+
+var _ AuthorUseCase = (*libraryImpl)(nil)
+var _ BooksUseCase = (*libraryImpl)(nil)
+var _ SubjectsUseCase = (*libraryImpl)(nil)
+var _ WorksUseCase = (*libraryImpl)(nil)
+var _ CopiesUseCase = (*libraryImpl)(nil)
+var _ PatronsUseCase = (*libraryImpl)(nil)
+var _ LoansUseCase = (*libraryImpl)(nil)
+var _ HoldsUseCase = (*libraryImpl)(nil)
+var _ ReviewsUseCase = (*libraryImpl)(nil)
+var _ FinesUseCase = (*libraryImpl)(nil)
+var _ HistoryUseCase = (*libraryImpl)(nil)
+var _ CatalogUseCase = (*libraryImpl)(nil)
+
+type libraryImpl struct {
+	logger             *zap.Logger
+	authorRepository   repository.AuthorRepository
+	booksRepository    repository.BooksRepository
+	subjectsRepository repository.SubjectsRepository
+	worksRepository    repository.WorksRepository
+	copiesRepository   repository.CopiesRepository
+	patronsRepository  repository.PatronsRepository
+	loansRepository    repository.LoansRepository
+	holdsRepository    repository.HoldsRepository
+	reviewsRepository  repository.ReviewsRepository
+	finesRepository    repository.FinesRepository
+	catalogRepository  repository.CatalogEventRepository
+	auditRepository    repository.AuditRepository
+	outboxRepository   repository.OutboxRepository
+	blobStore          repository.BlobStore
+	transactor         repository.Transactor
+	loanPolicy         entity.LoanPolicy
+	// maxCoverSize limits the size of uploaded covers in bytes.
+	maxCoverSize int64
+}
+
+// Deps are the repositories and settings the library use cases work with.
+type Deps struct {
+	AuthorRepository   repository.AuthorRepository
+	BooksRepository    repository.BooksRepository
+	SubjectsRepository repository.SubjectsRepository
+	WorksRepository    repository.WorksRepository
+	CopiesRepository   repository.CopiesRepository
+	PatronsRepository  repository.PatronsRepository
+	LoansRepository    repository.LoansRepository
+	HoldsRepository    repository.HoldsRepository
+	ReviewsRepository  repository.ReviewsRepository
+	FinesRepository    repository.FinesRepository
+	CatalogRepository  repository.CatalogEventRepository
+	AuditRepository    repository.AuditRepository
+	OutboxRepository   repository.OutboxRepository
+	BlobStore          repository.BlobStore
+	Transactor         repository.Transactor
+	LoanPolicy         entity.LoanPolicy
+	// MaxCoverSize limits the size of uploaded covers in bytes.
+	MaxCoverSize int64
+}
+
+func New(logger *zap.Logger, deps Deps) *libraryImpl {
+	return &libraryImpl{
+		logger:             logger,
+		authorRepository:   deps.AuthorRepository,
+		booksRepository:    deps.BooksRepository,
+		subjectsRepository: deps.SubjectsRepository,
+		worksRepository:    deps.WorksRepository,
+		copiesRepository:   deps.CopiesRepository,
+		patronsRepository:  deps.PatronsRepository,
+		loansRepository:    deps.LoansRepository,
+		holdsRepository:    deps.HoldsRepository,
+		reviewsRepository:  deps.ReviewsRepository,
+		finesRepository:    deps.FinesRepository,
+		catalogRepository:  deps.CatalogRepository,
+		auditRepository:    deps.AuditRepository,
+		outboxRepository:   deps.OutboxRepository,
+		blobStore:          deps.BlobStore,
+		transactor:         deps.Transactor,
+		loanPolicy:         deps.LoanPolicy,
+		maxCoverSize:       deps.MaxCoverSize,
+	}
+}
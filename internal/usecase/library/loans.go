@@ -0,0 +1,226 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func (l *libraryImpl) CheckoutCopy(ctx context.Context, copyID string, patronID string) (entity.Loan, error) {
+	var loan entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		active, txErr := l.loansRepository.LockPatronLoans(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		patron, txErr := l.patronsRepository.GetPatron(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		now := time.Now().UTC()
+
+		if txErr = checkBorrower(patron, now); txErr != nil {
+			return txErr
+		}
+
+		if active >= l.loanPolicy.MaxActiveLoans {
+			return entity.ErrLoanLimitReached
+		}
+
+		balance, txErr := l.finesRepository.GetPatronBalance(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if balance > l.loanPolicy.Fines.MaxBalance {
+			return entity.ErrFinesOutstanding
+		}
+
+		bookCopy, txErr := l.copiesRepository.GetCopy(ctx, copyID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.fulfillHolds(ctx, bookCopy, patronID, now); txErr != nil {
+			return txErr
+		}
+
+		loan, txErr = l.loansRepository.CreateLoan(ctx, entity.Loan{
+			CopyID:       copyID,
+			PatronID:     patronID,
+			CheckedOutAt: now,
+			DueAt:        now.Add(l.loanPolicy.Period(patron.MembershipType)),
+		})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.recordLoanChange(ctx, entity.AuditOperationCheckoutCopy, entity.LoanEventCheckedOut, nil, loan)
+	})
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+func (l *libraryImpl) RenewLoan(ctx context.Context, loanID string) (entity.Loan, error) {
+	var loan entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.loansRepository.GetLoan(ctx, loanID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if !previous.Active() {
+			return entity.ErrLoanReturned
+		}
+
+		if previous.Renewals >= l.loanPolicy.MaxRenewals {
+			return entity.ErrRenewalLimitReached
+		}
+
+		bookCopy, txErr := l.copiesRepository.GetCopy(ctx, previous.CopyID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		waiting, txErr := l.holdsRepository.CountWaitingHolds(ctx, bookCopy.BookID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if waiting > 0 {
+			return entity.ErrHoldsWaiting
+		}
+
+		patron, txErr := l.patronsRepository.GetPatron(ctx, previous.PatronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		now := time.Now().UTC()
+
+		if txErr = checkBorrower(patron, now); txErr != nil {
+			return txErr
+		}
+
+		dueAt := now.Add(l.loanPolicy.Period(patron.MembershipType))
+		loan, txErr = l.loansRepository.RenewLoan(ctx, loanID, previous.Renewals, dueAt)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.recordLoanChange(ctx, entity.AuditOperationRenewLoan, entity.LoanEventRenewed, previous, loan)
+	})
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+func (l *libraryImpl) ReturnCopy(ctx context.Context, copyID string) (entity.Loan, error) {
+	var loan entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+
+		var txErr error
+		loan, txErr = l.loansRepository.ReturnCopy(ctx, copyID, now)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		previous := loan
+		previous.ReturnedAt = nil
+
+		txErr = l.recordLoanChange(ctx, entity.AuditOperationReturnCopy, entity.LoanEventReturned, previous, loan)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		// The fine of a late return is charged in full right away, the
+		// accrual job only sees active loans.
+		if _, txErr = l.accrueFine(ctx, loan.ID, now); txErr != nil {
+			return txErr
+		}
+
+		return l.assignCopy(ctx, copyID, now)
+	})
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+// recordLoanChange audits the change of the loan and sends it through the
+// outbox. Pass a nil previous for new loans.
+func (l *libraryImpl) recordLoanChange(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	kind entity.LoanEventKind,
+	previous any,
+	loan entity.Loan,
+) error {
+	serialized, err := json.Marshal(entity.LoanEvent{Kind: kind, Loan: loan})
+
+	if err != nil {
+		return err
+	}
+
+	// Renewals are told apart by their number, the other changes happen
+	// once per loan.
+	idempotencyKey := repository.OutboxKindLoan.String() + "_" + loan.ID + "_" + string(kind)
+
+	if kind == entity.LoanEventRenewed {
+		idempotencyKey += "_" + strconv.Itoa(loan.Renewals)
+	}
+
+	err = l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindLoan, serialized)
+
+	if err != nil {
+		return err
+	}
+
+	return l.appendAudit(ctx, operation, entity.AuditEntityLoan, loan.ID, previous, loan)
+}
+
+// checkBorrower verifies that the patron may borrow copies at now.
+func checkBorrower(patron entity.Patron, now time.Time) error {
+	if patron.Status == entity.PatronStatusSuspended {
+		return entity.ErrPatronSuspended
+	}
+
+	// The membership is valid through the day it expires on, dates
+	// formatted as YYYY-MM-DD compare as strings.
+	if patron.ExpiresOn != "" && patron.ExpiresOn < now.Format(time.DateOnly) {
+		return entity.ErrMembershipExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// addCopies registers a book with count copies.
+func addCopies(t *testing.T, l *libraryImpl, count int) []entity.Copy {
+	t.Helper()
+
+	ctx := context.Background()
+	book, err := l.RegisterBook(ctx, "Book", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	copies := make([]entity.Copy, 0, count)
+
+	for i := range count {
+		bookCopy, err := l.AddCopy(ctx, entity.Copy{BookID: book.ID, Barcode: "B-" + strconv.Itoa(i)})
+		require.NoError(t, err)
+
+		copies = append(copies, bookCopy)
+	}
+
+	return copies
+}
+
+func TestCheckoutCopy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	copies := addCopies(t, l, 3)
+
+	patron, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1", MembershipType: entity.MembershipTypeStudent})
+	require.NoError(t, err)
+
+	loan, err := l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.NoError(t, err)
+	require.True(t, loan.Active())
+	require.Equal(t, 28*24*time.Hour, loan.DueAt.Sub(loan.CheckedOutAt))
+
+	bookCopy, err := l.GetCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyStatusOnLoan, bookCopy.Status)
+
+	_, err = l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.ErrorIs(t, err, entity.ErrCopyNotAvailable)
+
+	_, err = l.CheckoutCopy(ctx, "unknown", patron.ID)
+	require.ErrorIs(t, err, entity.ErrCopyNotFound)
+
+	_, err = l.CheckoutCopy(ctx, copies[1].ID, "unknown")
+	require.ErrorIs(t, err, entity.ErrPatronNotFound)
+
+	_, err = l.CheckoutCopy(ctx, copies[1].ID, patron.ID)
+	require.NoError(t, err)
+
+	_, err = l.CheckoutCopy(ctx, copies[2].ID, patron.ID)
+	require.ErrorIs(t, err, entity.ErrLoanLimitReached)
+
+	// Copies go on and off loan by checkout and return only.
+	available := entity.CopyStatusAvailable
+	_, err = l.UpdateCopy(ctx, copies[0].ID, entity.CopyPatch{Status: &available})
+	require.ErrorIs(t, err, entity.ErrCopyOnLoan)
+
+	onLoan := entity.CopyStatusOnLoan
+	_, err = l.UpdateCopy(ctx, copies[2].ID, entity.CopyPatch{Status: &onLoan})
+	require.ErrorIs(t, err, entity.ErrInvalidCopyStatus)
+
+	require.ErrorIs(t, l.DeleteCopy(ctx, copies[0].ID), entity.ErrCopyOnLoan)
+
+	returned, err := l.ReturnCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, loan.ID, returned.ID)
+	require.False(t, returned.Active())
+
+	_, err = l.ReturnCopy(ctx, copies[0].ID)
+	require.ErrorIs(t, err, entity.ErrCopyNotOnLoan)
+
+	require.ErrorIs(t, l.DeleteCopy(ctx, copies[0].ID), entity.ErrCopyHasLoans)
+
+	_, err = l.CheckoutCopy(ctx, copies[2].ID, patron.ID)
+	require.NoError(t, err)
+}
+
+func TestCheckoutCopyChecksPatron(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	copies := addCopies(t, l, 1)
+
+	expired, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1", ExpiresOn: "2000-01-01"})
+	require.NoError(t, err)
+
+	_, err = l.CheckoutCopy(ctx, copies[0].ID, expired.ID)
+	require.ErrorIs(t, err, entity.ErrMembershipExpired)
+
+	suspended, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-2"})
+	require.NoError(t, err)
+
+	_, err = l.SuspendPatron(ctx, suspended.ID, "")
+	require.NoError(t, err)
+
+	_, err = l.CheckoutCopy(ctx, copies[0].ID, suspended.ID)
+	require.ErrorIs(t, err, entity.ErrPatronSuspended)
+
+	bookCopy, err := l.GetCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.CopyStatusAvailable, bookCopy.Status)
+}
+
+func TestRenewLoan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	copies := addCopies(t, l, 1)
+
+	patron, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1"})
+	require.NoError(t, err)
+
+	loan, err := l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.NoError(t, err)
+
+	renewed, err := l.RenewLoan(ctx, loan.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, renewed.Renewals)
+	require.False(t, renewed.DueAt.Before(loan.DueAt))
+
+	_, err = l.RenewLoan(ctx, loan.ID)
+	require.ErrorIs(t, err, entity.ErrRenewalLimitReached)
+
+	_, err = l.RenewLoan(ctx, "unknown")
+	require.ErrorIs(t, err, entity.ErrLoanNotFound)
+
+	_, err = l.ReturnCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+
+	_, err = l.RenewLoan(ctx, loan.ID)
+	require.ErrorIs(t, err, entity.ErrLoanReturned)
+}
+
+func TestLoanOutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	copies := addCopies(t, l, 1)
+
+	patron, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1"})
+	require.NoError(t, err)
+
+	loan, err := l.CheckoutCopy(ctx, copies[0].ID, patron.ID)
+	require.NoError(t, err)
+
+	_, err = l.RenewLoan(ctx, loan.ID)
+	require.NoError(t, err)
+
+	_, err = l.ReturnCopy(ctx, copies[0].ID)
+	require.NoError(t, err)
+
+	messages, err := l.outboxRepository.GetMessages(ctx, 10, time.Minute)
+	require.NoError(t, err)
+
+	kinds := make(map[entity.LoanEventKind]string)
+
+	for _, message := range messages {
+		if message.Kind != repository.OutboxKindLoan {
+			continue
+		}
+
+		var event entity.LoanEvent
+		require.NoError(t, json.Unmarshal(message.RawData, &event))
+		require.Equal(t, loan.ID, event.Loan.ID)
+
+		kinds[event.Kind] = message.IdempotencyKey
+	}
+
+	require.Equal(t, map[entity.LoanEventKind]string{
+		entity.LoanEventCheckedOut: "loan_" + loan.ID + "_checked_out",
+		entity.LoanEventRenewed:    "loan_" + loan.ID + "_renewed_1",
+		entity.LoanEventReturned:   "loan_" + loan.ID + "_returned",
+	}, kinds)
+}
@@ -0,0 +1,66 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestUpdateBookPartially(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterAuthor(ctx, "First", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	second, err := l.RegisterAuthor(ctx, "Second", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	book, err := l.RegisterBook(ctx, "Book", entity.AuthorContributors([]string{first.ID}), entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	// Only the name changes, the authors are kept.
+	updated, err := l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("Renamed")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", updated.Name)
+	require.Equal(t, []string{first.ID}, updated.AuthorIDs)
+
+	updated, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{AddAuthorIDs: []string{second.ID}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Renamed", updated.Name)
+	require.ElementsMatch(t, []string{first.ID, second.ID}, updated.AuthorIDs)
+
+	updated, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{RemoveAuthorIDs: []string{first.ID}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{second.ID}, updated.AuthorIDs)
+
+	updated, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{Contributors: &[]entity.Contributor{}}, nil)
+	require.NoError(t, err)
+	require.Empty(t, updated.AuthorIDs)
+
+	_, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{AddAuthorIDs: []string{"unknown"}}, nil)
+	require.ErrorIs(t, err, entity.ErrAuthorNotFound)
+}
+
+func TestChangeAuthorInfoWithEmptyPatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	changed, err := l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Author", changed.Name)
+	require.Equal(t, author.Version+1, changed.Version)
+}
@@ -0,0 +1,176 @@
+package library
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+func (l *libraryImpl) RegisterPatron(ctx context.Context, patron entity.Patron) (entity.Patron, error) {
+	patron.MembershipType = cmp.Or(patron.MembershipType, entity.MembershipTypeStandard)
+	patron.Status = entity.PatronStatusActive
+
+	err := checkPatronPatch(entity.PatronPatch{
+		MembershipType: &patron.MembershipType,
+		ExpiresOn:      &patron.ExpiresOn,
+	})
+
+	if err != nil {
+		return entity.Patron{}, err
+	}
+
+	var created entity.Patron
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		created, txErr = l.patronsRepository.CreatePatron(ctx, patron)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		// Personal data of the patron is redacted in the payload.
+		serialized, txErr := json.Marshal(created)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		idempotencyKey := repository.OutboxKindPatron.String() + "_" + created.ID
+		txErr = l.outboxRepository.SendMessage(ctx, idempotencyKey, repository.OutboxKindPatron, serialized)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationRegisterPatron, entity.AuditEntityPatron, created.ID, nil, created)
+	})
+
+	if err != nil {
+		l.logger.Error("can not register patron", zap.Error(err))
+		return entity.Patron{}, err
+	}
+
+	return created, nil
+}
+
+func (l *libraryImpl) GetPatron(ctx context.Context, patronID string) (entity.Patron, error) {
+	return l.patronsRepository.GetPatron(ctx, patronID)
+}
+
+func (l *libraryImpl) ListPatrons(
+	ctx context.Context,
+	filter entity.PatronFilter,
+	afterCardNumber string,
+	limit int,
+) ([]entity.Patron, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, entity.ErrInvalidPatronStatus
+	}
+
+	if filter.MembershipType != "" && !filter.MembershipType.Valid() {
+		return nil, entity.ErrInvalidMembershipType
+	}
+
+	return l.patronsRepository.ListPatrons(ctx, filter, afterCardNumber, limit)
+}
+
+func (l *libraryImpl) UpdatePatron(
+	ctx context.Context,
+	patronID string,
+	patch entity.PatronPatch,
+) (entity.Patron, error) {
+	// The status is changed by SuspendPatron and ReinstatePatron only.
+	patch.Status = nil
+	patch.SuspensionReason = nil
+
+	if err := checkPatronPatch(patch); err != nil {
+		return entity.Patron{}, err
+	}
+
+	return l.changePatron(ctx, entity.AuditOperationUpdatePatron, patronID, func(entity.Patron) (entity.PatronPatch, error) {
+		return patch, nil
+	})
+}
+
+func (l *libraryImpl) SuspendPatron(ctx context.Context, patronID string, reason string) (entity.Patron, error) {
+	return l.changePatron(ctx, entity.AuditOperationSuspendPatron, patronID,
+		func(previous entity.Patron) (entity.PatronPatch, error) {
+			if previous.Status == entity.PatronStatusSuspended {
+				return entity.PatronPatch{}, entity.ErrPatronAlreadySuspended
+			}
+
+			status := entity.PatronStatusSuspended
+
+			return entity.PatronPatch{Status: &status, SuspensionReason: &reason}, nil
+		})
+}
+
+func (l *libraryImpl) ReinstatePatron(ctx context.Context, patronID string) (entity.Patron, error) {
+	return l.changePatron(ctx, entity.AuditOperationReinstatePatron, patronID,
+		func(previous entity.Patron) (entity.PatronPatch, error) {
+			if previous.Status != entity.PatronStatusSuspended {
+				return entity.PatronPatch{}, entity.ErrPatronNotSuspended
+			}
+
+			status := entity.PatronStatusActive
+			reason := ""
+
+			return entity.PatronPatch{Status: &status, SuspensionReason: &reason}, nil
+		})
+}
+
+// changePatron updates the patron with the patch made from its current
+// state and records the change.
+func (l *libraryImpl) changePatron(
+	ctx context.Context,
+	operation entity.AuditOperation,
+	patronID string,
+	makePatch func(previous entity.Patron) (entity.PatronPatch, error),
+) (entity.Patron, error) {
+	var patron entity.Patron
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.patronsRepository.GetPatron(ctx, patronID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		patch, txErr := makePatch(previous)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		patron, txErr = l.patronsRepository.UpdatePatron(ctx, patronID, patch)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, operation, entity.AuditEntityPatron, patronID, previous, patron)
+	})
+
+	if err != nil {
+		return entity.Patron{}, err
+	}
+
+	return patron, nil
+}
+
+func checkPatronPatch(patch entity.PatronPatch) error {
+	if patch.MembershipType != nil && !patch.MembershipType.Valid() {
+		return entity.ErrInvalidMembershipType
+	}
+
+	if patch.ExpiresOn != nil {
+		return checkDates(*patch.ExpiresOn)
+	}
+
+	return nil
+}
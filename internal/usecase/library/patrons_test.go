@@ -0,0 +1,139 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatrons(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	first, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-2", Name: "First", ExpiresOn: "2030-12-31"})
+	require.NoError(t, err)
+	require.Equal(t, entity.MembershipTypeStandard, first.MembershipType)
+	require.Equal(t, entity.PatronStatusActive, first.Status)
+
+	second, err := l.RegisterPatron(ctx, entity.Patron{
+		CardNumber:     "C-1",
+		Name:           "Second",
+		MembershipType: entity.MembershipTypeStudent,
+	})
+	require.NoError(t, err)
+
+	_, err = l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1", Name: "Third"})
+	require.ErrorIs(t, err, entity.ErrCardNumberTaken)
+
+	_, err = l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-3", MembershipType: "unknown"})
+	require.ErrorIs(t, err, entity.ErrInvalidMembershipType)
+
+	_, err = l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-3", ExpiresOn: "2030-02-30"})
+	require.ErrorIs(t, err, entity.ErrInvalidDate)
+
+	updated, err := l.UpdatePatron(ctx, first.ID, entity.PatronPatch{Email: ptr(entity.Sensitive("first@example.com"))})
+	require.NoError(t, err)
+	require.Equal(t, entity.Sensitive("first@example.com"), updated.Email)
+	require.Equal(t, entity.Sensitive("First"), updated.Name)
+
+	_, err = l.UpdatePatron(ctx, first.ID, entity.PatronPatch{CardNumber: ptr("C-1")})
+	require.ErrorIs(t, err, entity.ErrCardNumberTaken)
+
+	_, err = l.UpdatePatron(ctx, "unknown", entity.PatronPatch{})
+	require.ErrorIs(t, err, entity.ErrPatronNotFound)
+
+	patrons, err := l.ListPatrons(ctx, entity.PatronFilter{}, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Patron{second, updated}, patrons)
+
+	patrons, err = l.ListPatrons(ctx, entity.PatronFilter{}, "C-1", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Patron{updated}, patrons)
+
+	patrons, err = l.ListPatrons(ctx, entity.PatronFilter{MembershipType: entity.MembershipTypeStudent}, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Patron{second}, patrons)
+
+	_, err = l.ListPatrons(ctx, entity.PatronFilter{Status: "unknown"}, "", 10)
+	require.ErrorIs(t, err, entity.ErrInvalidPatronStatus)
+}
+
+func TestSuspendPatron(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	patron, err := l.RegisterPatron(ctx, entity.Patron{CardNumber: "C-1", Name: "Patron"})
+	require.NoError(t, err)
+
+	_, err = l.ReinstatePatron(ctx, patron.ID)
+	require.ErrorIs(t, err, entity.ErrPatronNotSuspended)
+
+	suspended, err := l.SuspendPatron(ctx, patron.ID, "Lost books")
+	require.NoError(t, err)
+	require.Equal(t, entity.PatronStatusSuspended, suspended.Status)
+	require.Equal(t, "Lost books", suspended.SuspensionReason)
+
+	_, err = l.SuspendPatron(ctx, patron.ID, "Again")
+	require.ErrorIs(t, err, entity.ErrPatronAlreadySuspended)
+
+	// The status is not changed by updates.
+	active := entity.PatronStatusActive
+	updated, err := l.UpdatePatron(ctx, patron.ID, entity.PatronPatch{Status: &active})
+	require.NoError(t, err)
+	require.Equal(t, entity.PatronStatusSuspended, updated.Status)
+
+	patrons, err := l.ListPatrons(ctx, entity.PatronFilter{Status: entity.PatronStatusSuspended}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, patrons, 1)
+
+	reinstated, err := l.ReinstatePatron(ctx, patron.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.PatronStatusActive, reinstated.Status)
+	require.Empty(t, reinstated.SuspensionReason)
+}
+
+func TestPatronPersonalDataIsRedacted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	patron, err := l.RegisterPatron(ctx, entity.Patron{
+		CardNumber: "C-1",
+		Name:       "Jane Doe",
+		PatronContact: entity.PatronContact{
+			Email:   "jane@example.com",
+			Phone:   "+1 555 0100",
+			Address: "1 Main St",
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = l.UpdatePatron(ctx, patron.ID, entity.PatronPatch{Phone: ptr(entity.Sensitive("+1 555 0199"))})
+	require.NoError(t, err)
+
+	messages, err := l.outboxRepository.GetMessages(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	records, err := l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityPatron, patron.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.Contains(t, string(messages[0].RawData), patron.ID)
+
+	persisted := []string{string(messages[0].RawData), string(records[0].After), string(records[1].After)}
+
+	for _, data := range persisted {
+		for _, value := range []string{"Jane Doe", "jane@example.com", "+1 555 0100", "+1 555 0199", "1 Main St"} {
+			require.NotContains(t, data, value)
+		}
+	}
+}
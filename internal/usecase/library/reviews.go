@@ -0,0 +1,107 @@
+package library
+
+import (
+	"context"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) CreateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	if err := entity.CheckRating(review.Rating); err != nil {
+		return entity.Review{}, err
+	}
+
+	if err := entity.CheckOwner(ctx, review.PatronID); err != nil {
+		return entity.Review{}, err
+	}
+
+	var created entity.Review
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		created, txErr = l.reviewsRepository.CreateReview(ctx, review)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationCreateReview, entity.AuditEntityReview, created.ID, nil, created)
+	})
+
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return created, nil
+}
+
+func (l *libraryImpl) UpdateReview(
+	ctx context.Context,
+	reviewID string,
+	patch entity.ReviewPatch,
+) (entity.Review, error) {
+	if patch.Rating != nil {
+		if err := entity.CheckRating(*patch.Rating); err != nil {
+			return entity.Review{}, err
+		}
+	}
+
+	var review entity.Review
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.reviewsRepository.GetReview(ctx, reviewID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = entity.CheckOwner(ctx, previous.PatronID); txErr != nil {
+			return txErr
+		}
+
+		review, txErr = l.reviewsRepository.UpdateReview(ctx, reviewID, patch)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationUpdateReview, entity.AuditEntityReview, reviewID, previous, review)
+	})
+
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (l *libraryImpl) DeleteReview(ctx context.Context, reviewID string) error {
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.reviewsRepository.GetReview(ctx, reviewID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.reviewsRepository.DeleteReview(ctx, reviewID); txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationDeleteReview, entity.AuditEntityReview, reviewID, previous, nil)
+	})
+}
+
+func (l *libraryImpl) ListReviews(
+	ctx context.Context,
+	filter entity.ReviewFilter,
+	afterCreatedAt time.Time,
+	afterID string,
+	limit int,
+) ([]entity.Review, error) {
+	return l.reviewsRepository.ListReviews(ctx, filter, afterCreatedAt, afterID, limit)
+}
+
+func (l *libraryImpl) GetBookRating(ctx context.Context, bookID string) (entity.BookRating, error) {
+	return l.reviewsRepository.GetBookRating(ctx, bookID)
+}
@@ -0,0 +1,165 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookRating(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	patrons := registerPatrons(t, l, 3)
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	reviews := make([]entity.Review, 0, len(patrons))
+
+	for i, patron := range patrons {
+		review, err := l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: patron.ID, Rating: 3 + i})
+		require.NoError(t, err)
+
+		reviews = append(reviews, review)
+	}
+
+	rating, err := l.GetBookRating(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.BookRating{Count: 3, Sum: 12}, rating)
+	require.InDelta(t, 4.0, rating.Average(), 1e-9)
+
+	_, err = l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: patrons[0].ID, Rating: 1})
+	require.ErrorIs(t, err, entity.ErrReviewExists)
+
+	lowest := 1
+	text := "Too long"
+	updated, err := l.UpdateReview(ctx, reviews[2].ID, entity.ReviewPatch{Rating: &lowest, Text: &text})
+	require.NoError(t, err)
+	require.Equal(t, 1, updated.Rating)
+	require.Equal(t, text, updated.Text)
+
+	require.NoError(t, l.DeleteReview(ctx, reviews[0].ID))
+	require.ErrorIs(t, l.DeleteReview(ctx, reviews[0].ID), entity.ErrReviewNotFound)
+
+	rating, err = l.GetBookRating(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.BookRating{Count: 2, Sum: 5}, rating)
+
+	history, err := l.auditRepository.GetAuditRecords(ctx, entity.AuditEntityReview, reviews[2].ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, entity.AuditOperationUpdateReview, history[1].Operation)
+}
+
+func TestReviewRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	patron := registerPatrons(t, l, 1)[0]
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	_, err = l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: patron.ID, Rating: 6})
+	require.ErrorIs(t, err, entity.ErrInvalidRating)
+
+	_, err = l.CreateReview(ctx, entity.Review{BookID: "unknown", PatronID: patron.ID, Rating: 5})
+	require.ErrorIs(t, err, entity.ErrBookNotFound)
+
+	_, err = l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: "unknown", Rating: 5})
+	require.ErrorIs(t, err, entity.ErrPatronNotFound)
+
+	review, err := l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: patron.ID, Rating: 5})
+	require.NoError(t, err)
+
+	zero := 0
+	_, err = l.UpdateReview(ctx, review.ID, entity.ReviewPatch{Rating: &zero})
+	require.ErrorIs(t, err, entity.ErrInvalidRating)
+
+	_, err = l.UpdateReview(ctx, "unknown", entity.ReviewPatch{})
+	require.ErrorIs(t, err, entity.ErrReviewNotFound)
+
+	rating, err := l.GetBookRating(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.BookRating{Count: 1, Sum: 5}, rating)
+}
+
+func TestReviewOwner(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	patrons := registerPatrons(t, l, 2)
+
+	book, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	other, err := l.CreateReview(ctx, entity.Review{BookID: book.ID, PatronID: patrons[1].ID, Rating: 2})
+	require.NoError(t, err)
+
+	// The reader may review as itself only.
+	reader := entity.WithOwnRecordsOnly(entity.WithPrincipal(ctx, entity.Principal{Subject: patrons[0].ID}))
+
+	review, err := l.CreateReview(reader, entity.Review{BookID: book.ID, PatronID: patrons[0].ID, Rating: 4})
+	require.NoError(t, err)
+
+	_, err = l.CreateReview(reader, entity.Review{BookID: book.ID, PatronID: patrons[1].ID, Rating: 5})
+	require.ErrorIs(t, err, entity.ErrNotOwner)
+
+	highest := 5
+	updated, err := l.UpdateReview(reader, review.ID, entity.ReviewPatch{Rating: &highest})
+	require.NoError(t, err)
+	require.Equal(t, 5, updated.Rating)
+
+	_, err = l.UpdateReview(reader, other.ID, entity.ReviewPatch{Rating: &highest})
+	require.ErrorIs(t, err, entity.ErrNotOwner)
+
+	rating, err := l.GetBookRating(ctx, book.ID)
+	require.NoError(t, err)
+	require.Equal(t, entity.BookRating{Count: 2, Sum: 7}, rating)
+}
+
+func TestListReviews(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+	patrons := registerPatrons(t, l, 3)
+
+	first, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	second, err := l.RegisterBook(ctx, "Dune Messiah", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	created := make([]entity.Review, 0, len(patrons))
+
+	for _, patron := range patrons {
+		review, err := l.CreateReview(ctx, entity.Review{BookID: first.ID, PatronID: patron.ID, Rating: 4})
+		require.NoError(t, err)
+
+		created = append(created, review)
+	}
+
+	other, err := l.CreateReview(ctx, entity.Review{BookID: second.ID, PatronID: patrons[0].ID, Rating: 2})
+	require.NoError(t, err)
+
+	filter := entity.ReviewFilter{BookID: first.ID}
+	page, err := l.ListReviews(ctx, filter, time.Time{}, "", 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Review{created[2], created[1]}, page)
+
+	page, err = l.ListReviews(ctx, filter, page[1].CreatedAt, page[1].ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Review{created[0]}, page)
+
+	page, err = l.ListReviews(ctx, entity.ReviewFilter{PatronID: patrons[0].ID}, time.Time{}, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Review{other, created[0]}, page)
+}
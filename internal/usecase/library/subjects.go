@@ -0,0 +1,84 @@
+package library
+
+import (
+	"context"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) CreateSubject(ctx context.Context, name string, parentID string) (entity.Subject, error) {
+	var subject entity.Subject
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		subject, txErr = l.subjectsRepository.CreateSubject(ctx, entity.Subject{Name: name, ParentID: parentID})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationCreateSubject, entity.AuditEntitySubject, subject.ID, nil, subject)
+	})
+
+	if err != nil {
+		return entity.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+func (l *libraryImpl) UpdateSubject(
+	ctx context.Context,
+	subjectID string,
+	patch entity.SubjectPatch,
+) (entity.Subject, error) {
+	var subject entity.Subject
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.subjectsRepository.GetSubject(ctx, subjectID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		subject, txErr = l.subjectsRepository.UpdateSubject(ctx, subjectID, patch)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationUpdateSubject, entity.AuditEntitySubject, subjectID, previous, subject)
+	})
+
+	if err != nil {
+		return entity.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+func (l *libraryImpl) DeleteSubject(ctx context.Context, subjectID string) error {
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		previous, txErr := l.subjectsRepository.GetSubject(ctx, subjectID)
+
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.subjectsRepository.DeleteSubject(ctx, subjectID); txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationDeleteSubject, entity.AuditEntitySubject, subjectID, previous, nil)
+	})
+}
+
+func (l *libraryImpl) ListSubjects(ctx context.Context, rootID string) ([]entity.Subject, error) {
+	if rootID != "" {
+		if _, err := l.subjectsRepository.GetSubject(ctx, rootID); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.subjectsRepository.ListSubjects(ctx, rootID)
+}
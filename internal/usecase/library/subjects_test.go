@@ -0,0 +1,113 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectTree(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	fiction, err := l.CreateSubject(ctx, "Fiction", "")
+	require.NoError(t, err)
+
+	scienceFiction, err := l.CreateSubject(ctx, "Science Fiction", fiction.ID)
+	require.NoError(t, err)
+
+	spaceOpera, err := l.CreateSubject(ctx, "Space Opera", scienceFiction.ID)
+	require.NoError(t, err)
+
+	_, err = l.CreateSubject(ctx, "science fiction", fiction.ID)
+	require.ErrorIs(t, err, entity.ErrSubjectNameTaken)
+
+	_, err = l.CreateSubject(ctx, "Orphan", "unknown")
+	require.ErrorIs(t, err, entity.ErrSubjectNotFound)
+
+	subjects, err := l.ListSubjects(ctx, scienceFiction.ID)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Subject{scienceFiction, spaceOpera}, subjects)
+
+	_, err = l.UpdateSubject(ctx, fiction.ID, entity.SubjectPatch{ParentID: &spaceOpera.ID})
+	require.ErrorIs(t, err, entity.ErrSubjectCycle)
+
+	_, err = l.UpdateSubject(ctx, fiction.ID, entity.SubjectPatch{ParentID: &fiction.ID})
+	require.ErrorIs(t, err, entity.ErrSubjectCycle)
+
+	moved, err := l.UpdateSubject(ctx, spaceOpera.ID, entity.SubjectPatch{ParentID: ptr("")})
+	require.NoError(t, err)
+	require.Empty(t, moved.ParentID)
+
+	require.ErrorIs(t, l.DeleteSubject(ctx, fiction.ID), entity.ErrSubjectInUse)
+	require.NoError(t, l.DeleteSubject(ctx, scienceFiction.ID))
+	require.ErrorIs(t, l.DeleteSubject(ctx, scienceFiction.ID), entity.ErrSubjectNotFound)
+}
+
+func TestListBooksBySubject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	fiction, err := l.CreateSubject(ctx, "Fiction", "")
+	require.NoError(t, err)
+
+	scienceFiction, err := l.CreateSubject(ctx, "Science Fiction", fiction.ID)
+	require.NoError(t, err)
+
+	history, err := l.CreateSubject(ctx, "History", "")
+	require.NoError(t, err)
+
+	dune, err := l.RegisterBook(ctx, "Dune", nil, entity.BookMetadata{
+		SubjectIDs: []string{scienceFiction.ID},
+		Tags:       []string{"Space Opera", "classics"},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"classics", "space opera"}, dune.Tags)
+
+	emma, err := l.RegisterBook(ctx, "Emma", nil, entity.BookMetadata{
+		SubjectIDs: []string{fiction.ID},
+		Tags:       []string{"classics"},
+	}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "SPQR", nil, entity.BookMetadata{SubjectIDs: []string{history.ID}}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Lost", nil, entity.BookMetadata{SubjectIDs: []string{"unknown"}}, false)
+	require.ErrorIs(t, err, entity.ErrSubjectNotFound)
+
+	books, err := l.ListBooks(ctx, entity.BookFilter{SubjectID: fiction.ID}, "", "", 1)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Book{dune}, books)
+
+	books, err = l.ListBooks(ctx, entity.BookFilter{SubjectID: fiction.ID}, dune.Name, dune.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Book{emma}, books)
+
+	books, err = l.ListBooks(ctx, entity.BookFilter{Tag: "Space  opera"}, "", "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Book{dune}, books)
+
+	books, err = l.ListBooks(ctx, entity.BookFilter{Title: "  EMMA!"}, "", "", 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Book{emma}, books)
+
+	books, err = l.ListBooks(ctx, entity.BookFilter{SubjectID: history.ID, Title: "Emma"}, "", "", 10)
+	require.NoError(t, err)
+	require.Empty(t, books)
+
+	_, err = l.ListBooks(ctx, entity.BookFilter{SubjectID: "unknown"}, "", "", 10)
+	require.ErrorIs(t, err, entity.ErrSubjectNotFound)
+
+	tags, err := l.ListTags(ctx, entity.BookFilter{SubjectID: fiction.ID}, 10)
+	require.NoError(t, err)
+	require.Equal(t, []entity.TagCount{{Tag: "classics", Count: 2}, {Tag: "space opera", Count: 1}}, tags)
+
+	require.ErrorIs(t, l.DeleteSubject(ctx, history.ID), entity.ErrSubjectInUse)
+}
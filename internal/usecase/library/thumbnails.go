@@ -0,0 +1,134 @@
+package library
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/project/library/internal/entity"
+)
+
+const (
+	// maxCoverPixels keeps small but huge images from exhausting memory
+	// when they are decoded.
+	maxCoverPixels   = 40_000_000
+	thumbnailQuality = 85
+)
+
+// coverFormats are the names image.Decode reports for the content types.
+var coverFormats = map[entity.CoverContentType]string{
+	entity.CoverContentTypeJPEG: "jpeg",
+	entity.CoverContentTypePNG:  "png",
+}
+
+var coverExtensions = map[entity.CoverContentType]string{
+	entity.CoverContentTypeJPEG: ".jpg",
+	entity.CoverContentTypePNG:  ".png",
+}
+
+// renderThumbnails decodes the cover and encodes its entity.CoverThumbnails
+// in the same format. The cover must be of the declared content type.
+func renderThumbnails(cover []byte, contentType entity.CoverContentType) ([][]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(cover))
+
+	if errors.Is(err, image.ErrFormat) || (err == nil && format != coverFormats[contentType]) {
+		return nil, entity.ErrUnsupportedContentType
+	}
+
+	if err != nil {
+		return nil, entity.ErrInvalidCover
+	}
+
+	if config.Width*config.Height > maxCoverPixels {
+		return nil, entity.ErrCoverTooLarge
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(cover))
+
+	if err != nil {
+		return nil, entity.ErrInvalidCover
+	}
+
+	thumbnails := make([][]byte, 0, len(entity.CoverThumbnails))
+
+	// Thumbnails are largest first, each is scaled down from the previous
+	// one instead of the whole cover.
+	for _, thumbnail := range entity.CoverThumbnails {
+		source = scaleDown(source, thumbnail.MaxSide)
+		encoded, err := encodeImage(source, contentType)
+
+		if err != nil {
+			return nil, err
+		}
+
+		thumbnails = append(thumbnails, encoded)
+	}
+
+	return thumbnails, nil
+}
+
+func encodeImage(img image.Image, contentType entity.CoverContentType) ([]byte, error) {
+	var buffer bytes.Buffer
+	var err error
+
+	if contentType == entity.CoverContentTypePNG {
+		err = png.Encode(&buffer, img)
+	} else {
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: thumbnailQuality})
+	}
+
+	return buffer.Bytes(), err
+}
+
+// scaleDown shrinks the image so that its longer side fits maxSide, keeping
+// the aspect ratio. Every pixel of the result averages the box of source
+// pixels it covers. Smaller images are returned as is.
+func scaleDown(source image.Image, maxSide int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if max(width, height) <= maxSide {
+		return source
+	}
+
+	targetWidth := max(1, width*maxSide/max(width, height))
+	targetHeight := max(1, height*maxSide/max(width, height))
+	target := image.NewRGBA64(image.Rect(0, 0, targetWidth, targetHeight))
+
+	for y := range targetHeight {
+		for x := range targetWidth {
+			box := image.Rect(
+				bounds.Min.X+x*width/targetWidth,
+				bounds.Min.Y+y*height/targetHeight,
+				bounds.Min.X+max((x+1)*width/targetWidth, x*width/targetWidth+1),
+				bounds.Min.Y+max((y+1)*height/targetHeight, y*height/targetHeight+1),
+			)
+			target.SetRGBA64(x, y, averageColor(source, box))
+		}
+	}
+
+	return target
+}
+
+func averageColor(source image.Image, box image.Rectangle) color.RGBA64 {
+	var red, green, blue, alpha uint64
+
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		for x := box.Min.X; x < box.Max.X; x++ {
+			r, g, b, a := source.At(x, y).RGBA()
+			red, green, blue, alpha = red+uint64(r), green+uint64(g), blue+uint64(b), alpha+uint64(a)
+		}
+	}
+
+	count := uint64(box.Dx() * box.Dy())
+
+	return color.RGBA64{
+		R: uint16(red / count),
+		G: uint16(green / count),
+		B: uint16(blue / count),
+		A: uint16(alpha / count),
+	}
+}
@@ -0,0 +1,61 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBookExpectedVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	book, err := l.RegisterBook(ctx, "Book", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), book.Version)
+
+	stale := book.Version
+
+	updated, err := l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("First edit")}, &stale)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), updated.Version)
+
+	_, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("Second edit")}, &stale)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+
+	stored, err := l.GetBookInfo(ctx, book.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "First edit", stored.Name)
+
+	// Unconditional updates always win.
+	updated, err = l.UpdateBook(ctx, book.ID, entity.BookPatch{Name: ptr("Third edit")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), updated.Version)
+}
+
+func TestChangeAuthorInfoExpectedVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	author, err := l.RegisterAuthor(ctx, "Author", entity.AuthorProfile{})
+	require.NoError(t, err)
+
+	stale := author.Version
+
+	changed, err := l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{Name: ptr("First")}, &stale)
+	require.NoError(t, err)
+	require.Equal(t, stale+1, changed.Version)
+
+	_, err = l.ChangeAuthorInfo(ctx, author.ID, entity.AuthorPatch{Name: ptr("Second")}, &stale)
+	require.ErrorIs(t, err, entity.ErrVersionMismatch)
+
+	missing := uint64(1)
+	_, err = l.ChangeAuthorInfo(ctx, "unknown", entity.AuthorPatch{Name: ptr("Name")}, &missing)
+	require.ErrorIs(t, err, entity.ErrAuthorNotFound)
+}
@@ -0,0 +1,81 @@
+package library
+
+import (
+	"context"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) CreateWork(ctx context.Context, title string) (entity.Work, error) {
+	var work entity.Work
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		work, txErr = l.worksRepository.CreateWork(ctx, entity.Work{Title: title})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationCreateWork, entity.AuditEntityWork, work.ID, nil, work)
+	})
+
+	if err != nil {
+		return entity.Work{}, err
+	}
+
+	return work, nil
+}
+
+func (l *libraryImpl) CreateSeries(ctx context.Context, name string) (entity.Series, error) {
+	var series entity.Series
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		series, txErr = l.worksRepository.CreateSeries(ctx, entity.Series{Name: name})
+
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.appendAudit(ctx, entity.AuditOperationCreateSeries, entity.AuditEntitySeries, series.ID, nil, series)
+	})
+
+	if err != nil {
+		return entity.Series{}, err
+	}
+
+	return series, nil
+}
+
+func (l *libraryImpl) GetWorkEditions(ctx context.Context, workID string) (entity.Work, []entity.Book, error) {
+	work, err := l.worksRepository.GetWork(ctx, workID)
+
+	if err != nil {
+		return entity.Work{}, nil, err
+	}
+
+	editions, err := l.worksRepository.GetWorkEditions(ctx, workID)
+
+	if err != nil {
+		return entity.Work{}, nil, err
+	}
+
+	return work, editions, nil
+}
+
+func (l *libraryImpl) GetSeriesBooks(ctx context.Context, seriesID string) (entity.Series, []entity.Book, error) {
+	series, err := l.worksRepository.GetSeries(ctx, seriesID)
+
+	if err != nil {
+		return entity.Series{}, nil, err
+	}
+
+	books, err := l.worksRepository.GetSeriesBooks(ctx, seriesID)
+
+	if err != nil {
+		return entity.Series{}, nil, err
+	}
+
+	return series, books, nil
+}
@@ -0,0 +1,95 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWorkEditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	work, err := l.CreateWork(ctx, "War and Peace")
+	require.NoError(t, err)
+
+	undated, err := l.RegisterBook(ctx, "War and Peace", nil, entity.BookMetadata{WorkID: work.ID}, false)
+	require.NoError(t, err)
+
+	translation, err := l.RegisterBook(ctx, "War and Peace", nil, entity.BookMetadata{
+		WorkID:          work.ID,
+		PublicationYear: 2007,
+		Language:        "en",
+	}, false)
+	require.NoError(t, err)
+
+	original, err := l.RegisterBook(ctx, "Война и мир", nil, entity.BookMetadata{
+		WorkID:          work.ID,
+		PublicationYear: 1869,
+		Language:        "ru",
+	}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Anna Karenina", nil, entity.BookMetadata{}, false)
+	require.NoError(t, err)
+
+	found, editions, err := l.GetWorkEditions(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, work, found)
+	require.Equal(t, []entity.Book{original, translation, undated}, editions)
+
+	_, err = l.RegisterBook(ctx, "Lost", nil, entity.BookMetadata{WorkID: "unknown"}, false)
+	require.ErrorIs(t, err, entity.ErrWorkNotFound)
+
+	_, _, err = l.GetWorkEditions(ctx, "unknown")
+	require.ErrorIs(t, err, entity.ErrWorkNotFound)
+}
+
+func TestGetSeriesBooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	l := newInMemoryLibrary()
+
+	series, err := l.CreateSeries(ctx, "Foundation")
+	require.NoError(t, err)
+
+	second, err := l.RegisterBook(ctx, "Foundation and Empire", nil, entity.BookMetadata{
+		SeriesID:       series.ID,
+		SeriesPosition: 2,
+	}, false)
+	require.NoError(t, err)
+
+	first, err := l.RegisterBook(ctx, "Foundation", nil, entity.BookMetadata{SeriesID: series.ID, SeriesPosition: 1}, false)
+	require.NoError(t, err)
+
+	companion, err := l.RegisterBook(ctx, "Foundation Companion", nil, entity.BookMetadata{SeriesID: series.ID}, false)
+	require.NoError(t, err)
+
+	_, err = l.RegisterBook(ctx, "Standalone", nil, entity.BookMetadata{SeriesPosition: 1}, false)
+	require.ErrorIs(t, err, entity.ErrInvalidSeriesPosition)
+
+	found, books, err := l.GetSeriesBooks(ctx, series.ID)
+	require.NoError(t, err)
+	require.Equal(t, series, found)
+	require.Equal(t, []entity.Book{first, second, companion}, books)
+
+	// Taking a book out of its series unnumbers it.
+	updated, err := l.UpdateBook(ctx, second.ID, entity.BookPatch{SeriesID: ptr("")}, nil)
+	require.NoError(t, err)
+	require.Zero(t, updated.SeriesPosition)
+
+	_, err = l.UpdateBook(ctx, updated.ID, entity.BookPatch{SeriesPosition: ptr(2)}, nil)
+	require.ErrorIs(t, err, entity.ErrInvalidSeriesPosition)
+
+	_, err = l.UpdateBook(ctx, updated.ID, entity.BookPatch{SeriesID: ptr("unknown")}, nil)
+	require.ErrorIs(t, err, entity.ErrSeriesNotFound)
+
+	_, books, err = l.GetSeriesBooks(ctx, series.ID)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Book{first, companion}, books)
+}
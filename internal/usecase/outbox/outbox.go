@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/project/library/internal/usecase/repository"
+	"go.uber.org/zap"
+)
+
+type GlobalHandler = func(kind repository.OutboxKind) (KindHandler, error)
+
+type KindHandler = func(ctx context.Context, data []byte) error
+
+type Outbox interface {
+	// Start runs the workers until ctx is done.
+	Start(ctx context.Context, workers int, batchSize int, waitTime time.Duration, inProgressTTL time.Duration)
+}
+
+var _ Outbox = (*outboxImpl)(nil)
+
+type outboxImpl struct {
+	logger           *zap.Logger
+	outboxRepository repository.OutboxRepository
+	globalHandler    GlobalHandler
+	transactor       repository.Transactor
+}
+
+func New(
+	logger *zap.Logger,
+	outboxRepository repository.OutboxRepository,
+	globalHandler GlobalHandler,
+	transactor repository.Transactor,
+) *outboxImpl {
+	return &outboxImpl{
+		logger:           logger,
+		outboxRepository: outboxRepository,
+		globalHandler:    globalHandler,
+		transactor:       transactor,
+	}
+}
+
+func (o *outboxImpl) Start(
+	ctx context.Context,
+	workers int,
+	batchSize int,
+	waitTime time.Duration,
+	inProgressTTL time.Duration,
+) {
+	wg := new(sync.WaitGroup)
+
+	for workerID := 1; workerID <= workers; workerID++ {
+		wg.Add(1)
+		go o.worker(ctx, wg, batchSize, waitTime, inProgressTTL)
+	}
+
+	wg.Wait()
+}
+
+func (o *outboxImpl) worker(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	batchSize int,
+	waitTime time.Duration,
+	inProgressTTL time.Duration,
+) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(waitTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := o.transactor.WithTx(ctx, func(ctx context.Context) error {
+			messages, err := o.outboxRepository.GetMessages(ctx, batchSize, inProgressTTL)
+
+			if err != nil {
+				return err
+			}
+
+			return o.outboxRepository.MarkAsProcessed(ctx, o.handleMessages(ctx, messages))
+		})
+
+		if err != nil && ctx.Err() == nil {
+			o.logger.Error("can not process outbox batch", zap.Error(err))
+		}
+	}
+}
+
+// handleMessages returns the idempotency keys of the messages that were
+// delivered successfully.
+func (o *outboxImpl) handleMessages(ctx context.Context, messages []repository.OutboxData) []string {
+	successKeys := make([]string, 0, len(messages))
+
+	for _, message := range messages {
+		kindHandler, err := o.globalHandler(message.Kind)
+
+		if err != nil {
+			o.logger.Error("unexpected outbox kind", zap.Error(err))
+			continue
+		}
+
+		if err = kindHandler(ctx, message.RawData); err != nil {
+			o.logger.Error("outbox handler error",
+				zap.String("idempotency_key", message.IdempotencyKey),
+				zap.Error(err))
+			continue
+		}
+
+		successKeys = append(successKeys, message.IdempotencyKey)
+	}
+
+	return successKeys
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets that have refilled are dropped,
+// a full bucket is no different from a new one.
+const sweepInterval = time.Minute
+
+// Limit is a token bucket refilled at Rate tokens per second up to Burst.
+// A zero Rate does not limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Limiter interface {
+	// Allow takes a token from the bucket of the client for the method.
+	// When the bucket is empty it returns false and how long until a token
+	// is available.
+	Allow(client string, method string) (bool, time.Duration)
+	// Acquire takes one of the concurrent calls of the method granted to
+	// the client, release gives it back. It returns false when the client
+	// holds them all.
+	Acquire(client string, method string) (release func(), ok bool)
+}
+
+var _ Limiter = (*limiterImpl)(nil)
+
+type bucketKey struct {
+	client string
+	// method is empty for the bucket shared by the methods without a
+	// limit of their own.
+	method string
+}
+
+type bucket struct {
+	limit     Limit
+	tokens    float64
+	updatedAt time.Time
+}
+
+type limiterImpl struct {
+	defaultLimit Limit
+	methods      map[string]Limit
+	concurrency  map[string]int
+	now          func() time.Time
+
+	mx      *sync.Mutex
+	buckets map[bucketKey]*bucket
+	calls   map[bucketKey]int
+	sweptAt time.Time
+}
+
+// New returns a limiter applying the limits of the methods, given by their
+// full names, and defaultLimit to the other methods. The concurrency caps
+// the concurrent calls of a method per client.
+func New(defaultLimit Limit, methods map[string]Limit, concurrency map[string]int) *limiterImpl {
+	return &limiterImpl{
+		defaultLimit: defaultLimit,
+		methods:      methods,
+		concurrency:  concurrency,
+		now:          time.Now,
+		mx:           new(sync.Mutex),
+		buckets:      make(map[bucketKey]*bucket),
+		calls:        make(map[bucketKey]int),
+	}
+}
+
+func (l *limiterImpl) Allow(client string, method string) (bool, time.Duration) {
+	key := bucketKey{client: client, method: method}
+	limit, ok := l.methods[method]
+
+	if !ok {
+		key.method, limit = "", l.defaultLimit
+	}
+
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := (1 - b.tokens) / limit.Rate
+
+	return false, time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+func (l *limiterImpl) Acquire(client string, method string) (func(), bool) {
+	limit := l.concurrency[method]
+
+	if limit <= 0 {
+		return func() {}, true
+	}
+
+	key := bucketKey{client: client, method: method}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.calls[key] >= limit {
+		return nil, false
+	}
+
+	l.calls[key]++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.mx.Lock()
+			defer l.mx.Unlock()
+
+			if l.calls[key]--; l.calls[key] == 0 {
+				delete(l.calls, key)
+			}
+		})
+	}, true
+}
+
+func (l *limiterImpl) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < sweepInterval {
+		return
+	}
+
+	l.sweptAt = now
+
+	for key, b := range l.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt).Seconds()
+
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.updatedAt = now
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	addBook    = "/library.Library/AddBook"
+	updateBook = "/library.Library/UpdateBook"
+	listBooks  = "/library.Library/ListBooks"
+)
+
+func newTestLimiter(now *time.Time) *limiterImpl {
+	limiter := New(
+		Limit{Rate: 1, Burst: 2},
+		map[string]Limit{listBooks: {Rate: 10, Burst: 1}, updateBook: {}},
+		map[string]int{"/library.Library/GetAuthorBooks": 2},
+	)
+	limiter.now = func() time.Time { return *now }
+
+	return limiter
+}
+
+func TestAllow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+
+	// The methods without a limit of their own share the default bucket.
+	allowed, _ := limiter.Allow("alice", addBook)
+	require.True(t, allowed)
+
+	allowed, _ = limiter.Allow("alice", "/library.Library/RegisterAuthor")
+	require.True(t, allowed)
+
+	allowed, wait := limiter.Allow("alice", addBook)
+	require.False(t, allowed)
+	require.Equal(t, time.Second, wait)
+
+	// Other clients and methods have buckets of their own.
+	allowed, _ = limiter.Allow("bob", addBook)
+	require.True(t, allowed)
+
+	allowed, _ = limiter.Allow("alice", listBooks)
+	require.True(t, allowed)
+
+	allowed, wait = limiter.Allow("alice", listBooks)
+	require.False(t, allowed)
+	require.Equal(t, 100*time.Millisecond, wait)
+
+	// A zero rate does not limit.
+	for range 10 {
+		allowed, _ = limiter.Allow("alice", updateBook)
+		require.True(t, allowed)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+
+	allowed, wait = limiter.Allow("alice", addBook)
+	require.False(t, allowed)
+	require.Equal(t, 500*time.Millisecond, wait)
+
+	now = now.Add(500 * time.Millisecond)
+
+	allowed, _ = limiter.Allow("alice", addBook)
+	require.True(t, allowed)
+}
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+
+	limiter.Allow("alice", addBook)
+	limiter.Allow("bob", addBook)
+	limiter.Allow("bob", addBook)
+	require.Len(t, limiter.buckets, 2)
+
+	// The bucket of alice has refilled, the one of bob was used just now.
+	now = now.Add(sweepInterval)
+	limiter.buckets[bucketKey{client: "bob"}].updatedAt = now
+	limiter.Allow("carol", addBook)
+	require.Len(t, limiter.buckets, 2)
+	require.NotContains(t, limiter.buckets, bucketKey{client: "alice"})
+}
+
+func TestAcquire(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	limiter := newTestLimiter(&now)
+
+	first, ok := limiter.Acquire("alice", "/library.Library/GetAuthorBooks")
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire("alice", "/library.Library/GetAuthorBooks")
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire("alice", "/library.Library/GetAuthorBooks")
+	require.False(t, ok)
+
+	_, ok = limiter.Acquire("bob", "/library.Library/GetAuthorBooks")
+	require.True(t, ok)
+
+	// Releasing twice gives back a single call.
+	first()
+	first()
+
+	_, ok = limiter.Acquire("alice", "/library.Library/GetAuthorBooks")
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire("alice", "/library.Library/GetAuthorBooks")
+	require.False(t, ok)
+
+	// Methods without a cap are not counted.
+	for range 10 {
+		_, ok = limiter.Acquire("alice", "/library.Library/WatchCatalog")
+		require.True(t, ok)
+	}
+}
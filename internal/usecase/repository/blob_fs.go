@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/project/library/internal/entity"
+)
+
+var _ BlobStore = (*fileBlobStore)(nil)
+
+// fileBlobStore keeps blobs as files under a directory, the gateway serves
+// them from there.
+type fileBlobStore struct {
+	dir       string
+	publicURL string
+}
+
+func NewFileBlobStore(dir string, publicURL string) *fileBlobStore {
+	return &fileBlobStore{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (f *fileBlobStore) PutBlob(_ context.Context, key string, _ string, data []byte) error {
+	name, err := f.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	// Readers never see a partially written file.
+	temp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temp.Name())
+
+	if _, err = temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err = temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), name)
+}
+
+func (f *fileBlobStore) GetBlob(_ context.Context, key string) ([]byte, string, error) {
+	name, err := f.path(key)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(name)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", entity.ErrBlobNotFound
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, mime.TypeByExtension(path.Ext(key)), nil
+}
+
+func (f *fileBlobStore) DeleteBlob(_ context.Context, key string) error {
+	name, err := f.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (f *fileBlobStore) BlobURL(key string) string {
+	return f.publicURL + "/" + key
+}
+
+// path returns the file of the blob, keys must not lead out of the
+// directory.
+func (f *fileBlobStore) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(f.dir, name), nil
+}
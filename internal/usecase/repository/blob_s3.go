@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+var _ BlobStore = (*s3BlobStore)(nil)
+
+// S3Config locates a bucket of an S3-compatible object storage.
+type S3Config struct {
+	// Endpoint is the base URL of the storage, e.g. http://localhost:9000.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL is where clients download the objects of the bucket from,
+	// the bucket URL by default.
+	PublicURL string
+}
+
+// s3BlobStore keeps blobs as objects of an S3 bucket. Requests are
+// path-style and signed with AWS Signature Version 4, which S3-compatible
+// storages such as MinIO accept as well.
+type s3BlobStore struct {
+	client *http.Client
+	config S3Config
+	now    func() time.Time
+}
+
+func NewS3BlobStore(client *http.Client, config S3Config) *s3BlobStore {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	return &s3BlobStore{
+		client: client,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+func (s *s3BlobStore) PutBlob(ctx context.Context, key string, contentType string, data []byte) error {
+	response, err := s.do(ctx, http.MethodPut, key, contentType, data)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return s3Error(response, http.StatusOK)
+}
+
+func (s *s3BlobStore) GetBlob(ctx context.Context, key string) ([]byte, string, error) {
+	response, err := s.do(ctx, http.MethodGet, key, "", nil)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, "", entity.ErrBlobNotFound
+	}
+
+	if err = s3Error(response, http.StatusOK); err != nil {
+		return nil, "", err
+	}
+
+	data, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, response.Header.Get("Content-Type"), nil
+}
+
+func (s *s3BlobStore) DeleteBlob(ctx context.Context, key string) error {
+	response, err := s.do(ctx, http.MethodDelete, key, "", nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	// Deleting a missing object succeeds with 204 as well.
+	return s3Error(response, http.StatusNoContent, http.StatusOK)
+}
+
+func (s *s3BlobStore) BlobURL(key string) string {
+	return s.config.PublicURL + "/" + escapeKey(key)
+}
+
+func (s *s3BlobStore) do(
+	ctx context.Context,
+	method string,
+	key string,
+	contentType string,
+	body []byte,
+) (*http.Response, error) {
+	target := s.config.Endpoint + "/" + url.PathEscape(s.config.Bucket) + "/" + escapeKey(key)
+	request, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(request, body)
+
+	return s.client.Do(request)
+}
+
+// sign adds the AWS Signature Version 4 headers to the request.
+func (s *s3BlobStore) sign(request *http.Request, body []byte) {
+	const algorithm = "AWS4-HMAC-SHA256"
+
+	now := s.now().UTC()
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		algorithm,
+		request.Header.Get("X-Amz-Date"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := signingKey(s.config.SecretAccessKey, date, s.config.Region, "s3")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalizeHeaders returns the names of the signed headers and their
+// canonical form. The host and the x-amz-* headers are signed, as is the
+// content type when set.
+func canonicalizeHeaders(request *http.Request) (string, string) {
+	headers := map[string]string{"host": request.URL.Host}
+
+	for name, values := range request.Header {
+		lower := strings.ToLower(name)
+
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func signingKey(secret string, date string, region string, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapeKey escapes every segment of the key, keeping the slashes.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func s3Error(response *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if response.StatusCode == code {
+			return nil
+		}
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+
+	return fmt.Errorf("object storage responded with %s: %s", response.Status, message)
+}
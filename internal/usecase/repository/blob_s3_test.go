@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// s3StandIn serves the object requests of a single bucket from memory.
+type s3StandIn struct {
+	mx      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/20240305/us-east-1/s3/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[r.URL.Path] = body
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := s.objects[r.URL.Path]
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", s.types[r.URL.Path])
+		_, _ = w.Write(object)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3BlobStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	standIn := &s3StandIn{objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	store := NewS3BlobStore(server.Client(), S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "covers",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	store.now = func() time.Time { return time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, store.PutBlob(ctx, "books/1/large.png", "image/png", []byte("image")))
+	require.Contains(t, standIn.objects, "/covers/books/1/large.png")
+
+	data, contentType, err := store.GetBlob(ctx, "books/1/large.png")
+	require.NoError(t, err)
+	require.Equal(t, []byte("image"), data)
+	require.Equal(t, "image/png", contentType)
+	require.Equal(t, server.URL+"/covers/books/1/large.png", store.BlobURL("books/1/large.png"))
+
+	require.NoError(t, store.DeleteBlob(ctx, "books/1/large.png"))
+
+	_, _, err = store.GetBlob(ctx, "books/1/large.png")
+	require.ErrorIs(t, err, entity.ErrBlobNotFound)
+
+	store.config.AccessKeyID = "other"
+	require.Error(t, store.PutBlob(ctx, "books/1/large.png", "image/png", []byte("image")))
+}
+
+func TestSigningKey(t *testing.T) {
+	t.Parallel()
+
+	// The example of the AWS Signature Version 4 documentation.
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	require.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
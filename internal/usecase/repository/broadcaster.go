@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+// broadcaster fans a "something changed" signal out to every subscriber.
+// Signals are coalesced: a subscriber that has not consumed the previous
+// signal yet does not get a second one.
+type broadcaster struct {
+	mx          *sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{
+		mx:          new(sync.Mutex),
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+func (b *broadcaster) subscribe(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	b.mx.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mx.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mx.Lock()
+		defer b.mx.Unlock()
+
+		delete(b.subscribers, ch)
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (b *broadcaster) notify() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -1 +1,751 @@
 package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ AuthorRepository = (*inMemoryImpl)(nil)
+var _ BooksRepository = (*inMemoryImpl)(nil)
+var _ CatalogEventRepository = (*inMemoryImpl)(nil)
+var _ AuditRepository = (*inMemoryImpl)(nil)
+var _ OutboxRepository = (*inMemoryImpl)(nil)
+var _ Transactor = (*inMemoryImpl)(nil)
+
+// inMemoryImpl keeps the whole catalog in process memory. Transactions are
+// not isolated, so it is meant for tests and local experiments only.
+type inMemoryImpl struct {
+	authorsMx     *sync.RWMutex
+	authors       map[string]*entity.Author
+	authorHistory map[string][]temporal[entity.Author]
+	authorAliases map[string]string
+
+	booksMx     *sync.RWMutex
+	books       map[string]*entity.Book
+	bookHistory map[string][]temporal[entity.Book]
+
+	// subjectsMx is taken before booksMx.
+	subjectsMx *sync.RWMutex
+	subjects   map[string]*entity.Subject
+
+	// worksMx is taken before booksMx.
+	worksMx *sync.RWMutex
+	works   map[string]*entity.Work
+	series  map[string]*entity.Series
+
+	copiesMx *sync.RWMutex
+	copies   map[string]*entity.Copy
+
+	patronsMx *sync.RWMutex
+	patrons   map[string]*entity.Patron
+
+	// loansMx and holdsMx are taken after copiesMx, holdsMx after loansMx.
+	loansMx *sync.RWMutex
+	loans   map[string]*entity.Loan
+
+	holdsMx *sync.RWMutex
+	holds   map[string]*entity.Hold
+
+	reviewsMx   *sync.RWMutex
+	reviews     map[string]*entity.Review
+	bookRatings map[string]entity.BookRating
+
+	// ledgerMx is taken after loansMx.
+	ledgerMx *sync.RWMutex
+	ledger   []entity.LedgerTransaction
+
+	eventsMx           *sync.RWMutex
+	events             []entity.CatalogEvent
+	catalogBroadcaster *broadcaster
+
+	auditMx *sync.RWMutex
+	audit   []entity.AuditRecord
+
+	blobsMx *sync.RWMutex
+	blobs   map[string]blob
+
+	idempotencyMx   *sync.Mutex
+	idempotencyKeys map[string]*idempotencyEntry
+
+	apiKeysMx *sync.Mutex
+	apiKeys   map[string]*entity.APIKey
+
+	outboxMx *sync.Mutex
+	outbox   map[string]OutboxData
+}
+
+func NewInMemoryRepository() *inMemoryImpl {
+	return &inMemoryImpl{
+		authorsMx:     new(sync.RWMutex),
+		authors:       make(map[string]*entity.Author),
+		authorHistory: make(map[string][]temporal[entity.Author]),
+		authorAliases: make(map[string]string),
+
+		booksMx:     new(sync.RWMutex),
+		books:       make(map[string]*entity.Book),
+		bookHistory: make(map[string][]temporal[entity.Book]),
+
+		subjectsMx: new(sync.RWMutex),
+		subjects:   make(map[string]*entity.Subject),
+
+		worksMx: new(sync.RWMutex),
+		works:   make(map[string]*entity.Work),
+		series:  make(map[string]*entity.Series),
+
+		copiesMx: new(sync.RWMutex),
+		copies:   make(map[string]*entity.Copy),
+
+		patronsMx: new(sync.RWMutex),
+		patrons:   make(map[string]*entity.Patron),
+
+		loansMx: new(sync.RWMutex),
+		loans:   make(map[string]*entity.Loan),
+
+		holdsMx: new(sync.RWMutex),
+		holds:   make(map[string]*entity.Hold),
+
+		reviewsMx:   new(sync.RWMutex),
+		reviews:     make(map[string]*entity.Review),
+		bookRatings: make(map[string]entity.BookRating),
+
+		ledgerMx: new(sync.RWMutex),
+		ledger:   make([]entity.LedgerTransaction, 0),
+
+		eventsMx:           new(sync.RWMutex),
+		events:             make([]entity.CatalogEvent, 0),
+		catalogBroadcaster: newBroadcaster(),
+
+		auditMx: new(sync.RWMutex),
+		audit:   make([]entity.AuditRecord, 0),
+
+		blobsMx: new(sync.RWMutex),
+		blobs:   make(map[string]blob),
+
+		idempotencyMx:   new(sync.Mutex),
+		idempotencyKeys: make(map[string]*idempotencyEntry),
+
+		apiKeysMx: new(sync.Mutex),
+		apiKeys:   make(map[string]*entity.APIKey),
+
+		outboxMx: new(sync.Mutex),
+		outbox:   make(map[string]OutboxData),
+	}
+}
+
+func (i *inMemoryImpl) WithTx(ctx context.Context, function func(ctx context.Context) error) error {
+	return function(ctx)
+}
+
+func (i *inMemoryImpl) CreateAuthor(_ context.Context, author entity.Author) (entity.Author, error) {
+	i.authorsMx.Lock()
+	defer i.authorsMx.Unlock()
+
+	author.ID = uuid.NewString()
+	author.Version = 1
+	author.Aliases = slices.Clone(author.Aliases)
+
+	if err := i.checkAuthorConsistent(author); err != nil {
+		return entity.Author{}, err
+	}
+
+	i.authors[author.ID] = &author
+	i.authorHistory[author.ID] = append(i.authorHistory[author.ID], temporal[entity.Author]{
+		validFrom: time.Now().UTC(),
+		value:     author,
+	})
+
+	return author, nil
+}
+
+func (i *inMemoryImpl) GetAuthor(_ context.Context, authorID string) (entity.Author, error) {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	author, ok := i.authors[authorID]
+
+	if !ok {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	return *author, nil
+}
+
+func (i *inMemoryImpl) UpdateAuthor(
+	_ context.Context,
+	authorID string,
+	patch entity.AuthorPatch,
+	expectedVersion *uint64,
+) (entity.Author, error) {
+	i.authorsMx.Lock()
+	defer i.authorsMx.Unlock()
+
+	stored, ok := i.authors[authorID]
+
+	if !ok {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	if expectedVersion != nil && *expectedVersion != stored.Version {
+		return entity.Author{}, entity.ErrVersionMismatch
+	}
+
+	updated := applyAuthorPatch(*stored, patch)
+
+	if err := i.checkAuthorConsistent(updated); err != nil {
+		return entity.Author{}, err
+	}
+
+	updated.Version++
+	*stored = updated
+	i.authorHistory[authorID] = append(i.authorHistory[authorID], temporal[entity.Author]{
+		validFrom: time.Now().UTC(),
+		value:     updated,
+	})
+
+	return updated, nil
+}
+
+func applyAuthorPatch(author entity.Author, patch entity.AuthorPatch) entity.Author {
+	author.Aliases = slices.Clone(author.Aliases)
+
+	for field, value := range map[*string]*string{
+		&author.Name:      patch.Name,
+		&author.Biography: patch.Biography,
+		&author.BirthDate: patch.BirthDate,
+		&author.DeathDate: patch.DeathDate,
+		&author.Country:   patch.Country,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	if patch.Aliases != nil {
+		author.Aliases = slices.Clone(*patch.Aliases)
+	}
+
+	if patch.ExternalIDs != nil {
+		author.ExternalIDs = *patch.ExternalIDs
+	}
+
+	return author
+}
+
+// checkAuthorConsistent enforces the constraints of the author table. It
+// must be called with authorsMx held.
+func (i *inMemoryImpl) checkAuthorConsistent(author entity.Author) error {
+	// Dates formatted as YYYY-MM-DD compare as strings.
+	if author.BirthDate != "" && author.DeathDate != "" && author.DeathDate < author.BirthDate {
+		return entity.ErrInvalidLifeDates
+	}
+
+	for _, other := range i.authors {
+		if other.ID != author.ID && sharesExternalID(other.ExternalIDs, author.ExternalIDs) {
+			return entity.ErrExternalIDTaken
+		}
+	}
+
+	return nil
+}
+
+func sharesExternalID(first entity.ExternalIDs, second entity.ExternalIDs) bool {
+	return first.VIAF != "" && first.VIAF == second.VIAF ||
+		first.ISNI != "" && first.ISNI == second.ISNI ||
+		first.Wikidata != "" && first.Wikidata == second.Wikidata
+}
+
+func (i *inMemoryImpl) GetAuthorByExternalID(
+	_ context.Context,
+	scheme entity.ExternalIDScheme,
+	value string,
+) (entity.Author, error) {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	for _, author := range i.authors {
+		if value != "" && externalID(author.ExternalIDs, scheme) == value {
+			return *author, nil
+		}
+	}
+
+	return entity.Author{}, entity.ErrAuthorNotFound
+}
+
+func externalID(ids entity.ExternalIDs, scheme entity.ExternalIDScheme) string {
+	switch scheme {
+	case entity.ExternalIDSchemeVIAF:
+		return ids.VIAF
+	case entity.ExternalIDSchemeISNI:
+		return ids.ISNI
+	case entity.ExternalIDSchemeWikidata:
+		return ids.Wikidata
+	default:
+		return ""
+	}
+}
+
+func (i *inMemoryImpl) GetAuthorAsOf(_ context.Context, authorID string, asOf time.Time) (entity.Author, error) {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	author, ok := versionAsOf(i.authorHistory[authorID], asOf)
+
+	if !ok {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	return author, nil
+}
+
+func (i *inMemoryImpl) GetAuthorBooksAsOf(
+	_ context.Context,
+	authorID string,
+	role entity.ContributorRole,
+	asOf time.Time,
+) ([]entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	books := make([]entity.Book, 0)
+
+	for _, versions := range i.bookHistory {
+		book, ok := versionAsOf(versions, asOf)
+
+		if ok && credits(book, authorID, role) {
+			books = append(books, cloneBook(book))
+		}
+	}
+
+	return books, nil
+}
+
+func (i *inMemoryImpl) GetAuthorBooks(
+	_ context.Context,
+	authorID string,
+	role entity.ContributorRole,
+) ([]entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	books := make([]entity.Book, 0)
+
+	for _, book := range i.books {
+		if credits(*book, authorID, role) {
+			books = append(books, cloneBook(*book))
+		}
+	}
+
+	return books, nil
+}
+
+// credits reports whether the book credits the author in the role, or in any
+// role when it is empty.
+func credits(book entity.Book, authorID string, role entity.ContributorRole) bool {
+	return slices.ContainsFunc(book.Contributors, func(contributor entity.Contributor) bool {
+		return contributor.AuthorID == authorID && (role == "" || contributor.Role == role)
+	})
+}
+
+func (i *inMemoryImpl) CreateBook(_ context.Context, book entity.Book) (entity.Book, error) {
+	if err := i.checkContributorsExist(book.Contributors); err != nil {
+		return entity.Book{}, err
+	}
+
+	if err := i.checkSubjectsExist(book.SubjectIDs); err != nil {
+		return entity.Book{}, err
+	}
+
+	if err := i.checkWorkAndSeriesExist(&book.WorkID, &book.SeriesID); err != nil {
+		return entity.Book{}, err
+	}
+
+	i.booksMx.Lock()
+	defer i.booksMx.Unlock()
+
+	if i.isbnTaken(book.ISBN, "") {
+		return entity.Book{}, entity.ErrISBNTaken
+	}
+
+	now := time.Now().UTC()
+
+	book.ID = uuid.NewString()
+	book.AuthorIDs = entity.ContributorAuthorIDs(book.Contributors)
+	book.CreatedAt = now
+	book.UpdatedAt = now
+	book.Version = 1
+
+	stored := cloneBook(book)
+	i.books[book.ID] = &stored
+	i.recordBookVersion(stored)
+
+	return book, nil
+}
+
+func (i *inMemoryImpl) GetBook(_ context.Context, bookID string) (entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	book, ok := i.books[bookID]
+
+	if !ok {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	return cloneBook(*book), nil
+}
+
+func (i *inMemoryImpl) UpdateBook(
+	_ context.Context,
+	bookID string,
+	patch entity.BookPatch,
+	expectedVersion *uint64,
+) (entity.Book, error) {
+	if patch.Contributors != nil {
+		if err := i.checkContributorsExist(*patch.Contributors); err != nil {
+			return entity.Book{}, err
+		}
+	}
+
+	if err := i.checkAuthorsExist(patch.AddAuthorIDs); err != nil {
+		return entity.Book{}, err
+	}
+
+	if patch.SubjectIDs != nil {
+		if err := i.checkSubjectsExist(*patch.SubjectIDs); err != nil {
+			return entity.Book{}, err
+		}
+	}
+
+	if err := i.checkWorkAndSeriesExist(patch.WorkID, patch.SeriesID); err != nil {
+		return entity.Book{}, err
+	}
+
+	i.booksMx.Lock()
+	defer i.booksMx.Unlock()
+
+	stored, ok := i.books[bookID]
+
+	if !ok {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if expectedVersion != nil && *expectedVersion != stored.Version {
+		return entity.Book{}, entity.ErrVersionMismatch
+	}
+
+	if patch.ISBN != nil && i.isbnTaken(*patch.ISBN, bookID) {
+		return entity.Book{}, entity.ErrISBNTaken
+	}
+
+	updated := cloneBook(*stored)
+	applyBookPatch(&updated, patch)
+
+	if err := updated.CheckSeriesPosition(); err != nil {
+		return entity.Book{}, err
+	}
+
+	updated.Contributors = patchContributors(updated.Contributors, patch)
+	updated.AuthorIDs = entity.ContributorAuthorIDs(updated.Contributors)
+	updated.UpdatedAt = time.Now().UTC()
+	updated.Version++
+	*stored = updated
+	i.recordBookVersion(updated)
+
+	return cloneBook(updated), nil
+}
+
+// LockBook only returns the book, transactions of the in-memory repository
+// are not isolated.
+func (i *inMemoryImpl) LockBook(ctx context.Context, bookID string) (entity.Book, error) {
+	return i.GetBook(ctx, bookID)
+}
+
+func (i *inMemoryImpl) GetBookByISBN(_ context.Context, isbn string) (entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	for _, book := range i.books {
+		if isbn != "" && book.ISBN == isbn {
+			return cloneBook(*book), nil
+		}
+	}
+
+	return entity.Book{}, entity.ErrBookNotFound
+}
+
+func (i *inMemoryImpl) GetBookByTitle(_ context.Context, title string, authorIDs []string) (entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	authorIDs = slices.Compact(slices.Sorted(slices.Values(authorIDs)))
+
+	for _, book := range i.books {
+		if entity.NormalizeTitle(book.Name) == title && slices.Equal(book.AuthorIDs, authorIDs) {
+			return cloneBook(*book), nil
+		}
+	}
+
+	return entity.Book{}, entity.ErrBookNotFound
+}
+
+// LockBookTitle does nothing, transactions of the in-memory repository are
+// not isolated.
+func (i *inMemoryImpl) LockBookTitle(context.Context, string) error {
+	return nil
+}
+
+// isbnTaken reports whether another book has the ISBN. It must be called
+// with booksMx held.
+func (i *inMemoryImpl) isbnTaken(isbn string, bookID string) bool {
+	for _, book := range i.books {
+		if isbn != "" && book.ISBN == isbn && book.ID != bookID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyBookPatch(book *entity.Book, patch entity.BookPatch) {
+	for field, value := range map[*string]*string{
+		&book.Name:        patch.Name,
+		&book.ISBN:        patch.ISBN,
+		&book.Publisher:   patch.Publisher,
+		&book.Language:    patch.Language,
+		&book.Description: patch.Description,
+		&book.WorkID:      patch.WorkID,
+		&book.SeriesID:    patch.SeriesID,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	for field, value := range map[*int]*int{
+		&book.PublicationYear: patch.PublicationYear,
+		&book.PageCount:       patch.PageCount,
+		&book.SeriesPosition:  patch.SeriesPosition,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	if patch.Cover != nil {
+		book.Cover = *patch.Cover
+	}
+
+	if patch.SeriesID != nil && *patch.SeriesID == "" && patch.SeriesPosition == nil {
+		book.SeriesPosition = 0
+	}
+
+	for field, value := range map[*[]string]*[]string{
+		&book.SubjectIDs: patch.SubjectIDs,
+		&book.Tags:       patch.Tags,
+	} {
+		if value != nil {
+			*field = slices.Clone(*value)
+		}
+	}
+}
+
+func (i *inMemoryImpl) GetBookAsOf(_ context.Context, bookID string, asOf time.Time) (entity.Book, error) {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	book, ok := versionAsOf(i.bookHistory[bookID], asOf)
+
+	if !ok {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	return cloneBook(book), nil
+}
+
+// recordBookVersion must be called with booksMx held.
+func (i *inMemoryImpl) recordBookVersion(book entity.Book) {
+	i.bookHistory[book.ID] = append(i.bookHistory[book.ID], temporal[entity.Book]{
+		validFrom: book.UpdatedAt,
+		value:     cloneBook(book),
+	})
+}
+
+func patchContributors(contributors []entity.Contributor, patch entity.BookPatch) []entity.Contributor {
+	if patch.Contributors != nil {
+		return slices.Clone(*patch.Contributors)
+	}
+
+	contributors = slices.DeleteFunc(slices.Clone(contributors), func(contributor entity.Contributor) bool {
+		return slices.Contains(patch.RemoveAuthorIDs, contributor.AuthorID)
+	})
+
+	return entity.CompactContributors(append(contributors, entity.AuthorContributors(patch.AddAuthorIDs)...))
+}
+
+func (i *inMemoryImpl) checkContributorsExist(contributors []entity.Contributor) error {
+	return i.checkAuthorsExist(entity.ContributorAuthorIDs(contributors))
+}
+
+func (i *inMemoryImpl) checkAuthorsExist(authorIDs []string) error {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	for _, authorID := range authorIDs {
+		if _, ok := i.authors[authorID]; !ok {
+			return entity.ErrAuthorNotFound
+		}
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) AppendCatalogEvent(_ context.Context, event entity.CatalogEvent) error {
+	i.eventsMx.Lock()
+	defer i.eventsMx.Unlock()
+
+	event.Cursor = uint64(len(i.events)) + 1
+	event.OccurredAt = time.Now().UTC()
+	i.events = append(i.events, event)
+
+	i.catalogBroadcaster.notify()
+
+	return nil
+}
+
+func (i *inMemoryImpl) GetCatalogEvents(_ context.Context, afterCursor uint64, limit int) ([]entity.CatalogEvent, error) {
+	i.eventsMx.RLock()
+	defer i.eventsMx.RUnlock()
+
+	if afterCursor >= uint64(len(i.events)) {
+		return nil, nil
+	}
+
+	// Cursors are 1-based positions in the slice.
+	events := i.events[afterCursor:]
+
+	return slices.Clone(events[:min(limit, len(events))]), nil
+}
+
+func (i *inMemoryImpl) GetLastCatalogCursor(_ context.Context) (uint64, error) {
+	i.eventsMx.RLock()
+	defer i.eventsMx.RUnlock()
+
+	return uint64(len(i.events)), nil
+}
+
+func (i *inMemoryImpl) SubscribeCatalogEvents(ctx context.Context) <-chan struct{} {
+	return i.catalogBroadcaster.subscribe(ctx)
+}
+
+func (i *inMemoryImpl) AppendAuditRecord(_ context.Context, record entity.AuditRecord) error {
+	i.auditMx.Lock()
+	defer i.auditMx.Unlock()
+
+	record.ID = uint64(len(i.audit)) + 1
+	record.CreatedAt = time.Now().UTC()
+	i.audit = append(i.audit, record)
+
+	return nil
+}
+
+func (i *inMemoryImpl) GetAuditRecords(
+	_ context.Context,
+	kind entity.AuditEntityKind,
+	entityID string,
+	afterID uint64,
+	limit int,
+) ([]entity.AuditRecord, error) {
+	i.auditMx.RLock()
+	defer i.auditMx.RUnlock()
+
+	records := make([]entity.AuditRecord, 0)
+
+	// Ids are 1-based positions in the slice.
+	for _, record := range i.audit[min(afterID, uint64(len(i.audit))):] {
+		if len(records) == limit {
+			break
+		}
+
+		if record.EntityKind == kind && record.EntityID == entityID {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func (i *inMemoryImpl) SendMessage(_ context.Context, idempotencyKey string, kind OutboxKind, message []byte) error {
+	i.outboxMx.Lock()
+	defer i.outboxMx.Unlock()
+
+	if _, ok := i.outbox[idempotencyKey]; !ok {
+		i.outbox[idempotencyKey] = OutboxData{
+			IdempotencyKey: idempotencyKey,
+			Kind:           kind,
+			RawData:        message,
+		}
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) GetMessages(_ context.Context, batchSize int, _ time.Duration) ([]OutboxData, error) {
+	i.outboxMx.Lock()
+	defer i.outboxMx.Unlock()
+
+	messages := make([]OutboxData, 0, min(batchSize, len(i.outbox)))
+
+	for _, message := range i.outbox {
+		if len(messages) == batchSize {
+			break
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (i *inMemoryImpl) MarkAsProcessed(_ context.Context, idempotencyKeys []string) error {
+	i.outboxMx.Lock()
+	defer i.outboxMx.Unlock()
+
+	for _, key := range idempotencyKeys {
+		delete(i.outbox, key)
+	}
+
+	return nil
+}
+
+// temporal is a version of an entity that is current from validFrom until
+// the next version appears.
+type temporal[T any] struct {
+	validFrom time.Time
+	value     T
+}
+
+func versionAsOf[T any](versions []temporal[T], asOf time.Time) (T, bool) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].validFrom.After(asOf) {
+			return versions[i].value, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+func cloneBook(book entity.Book) entity.Book {
+	book.AuthorIDs = slices.Clone(book.AuthorIDs)
+	book.Contributors = slices.Clone(book.Contributors)
+	book.SubjectIDs = slices.Clone(book.SubjectIDs)
+	book.Tags = slices.Clone(book.Tags)
+
+	return book
+}
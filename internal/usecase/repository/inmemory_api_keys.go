@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ APIKeysRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateAPIKey(_ context.Context, key entity.APIKey) (entity.APIKey, error) {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	key = cloneAPIKey(key)
+	key.ID = uuid.NewString()
+	i.apiKeys[key.ID] = &key
+
+	return cloneAPIKey(key), nil
+}
+
+func (i *inMemoryImpl) GetAPIKey(_ context.Context, keyID string) (entity.APIKey, error) {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	key, ok := i.apiKeys[keyID]
+
+	if !ok {
+		return entity.APIKey{}, entity.ErrAPIKeyNotFound
+	}
+
+	return cloneAPIKey(*key), nil
+}
+
+func (i *inMemoryImpl) ListAPIKeys(_ context.Context, includeRevoked bool) ([]entity.APIKey, error) {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	keys := make([]entity.APIKey, 0, len(i.apiKeys))
+
+	for _, key := range i.apiKeys {
+		if includeRevoked || key.RevokedAt == nil {
+			keys = append(keys, cloneAPIKey(*key))
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b entity.APIKey) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return keys, nil
+}
+
+func (i *inMemoryImpl) RotateAPIKey(_ context.Context, key entity.APIKey) (entity.APIKey, error) {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	stored, ok := i.apiKeys[key.ID]
+
+	switch {
+	case !ok:
+		return entity.APIKey{}, entity.ErrAPIKeyNotFound
+	case stored.RevokedAt != nil:
+		return entity.APIKey{}, entity.ErrAPIKeyRevoked
+	}
+
+	stored.Salt = slices.Clone(key.Salt)
+	stored.Hash = slices.Clone(key.Hash)
+	stored.ExpiresAt = key.ExpiresAt
+
+	return cloneAPIKey(*stored), nil
+}
+
+func (i *inMemoryImpl) RevokeAPIKey(_ context.Context, keyID string, at time.Time) (entity.APIKey, error) {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	stored, ok := i.apiKeys[keyID]
+
+	if !ok {
+		return entity.APIKey{}, entity.ErrAPIKeyNotFound
+	}
+
+	if stored.RevokedAt == nil {
+		stored.RevokedAt = &at
+	}
+
+	return cloneAPIKey(*stored), nil
+}
+
+func (i *inMemoryImpl) TouchAPIKey(_ context.Context, keyID string, at time.Time) error {
+	i.apiKeysMx.Lock()
+	defer i.apiKeysMx.Unlock()
+
+	if stored, ok := i.apiKeys[keyID]; ok && (stored.LastUsedAt == nil || stored.LastUsedAt.Before(at)) {
+		stored.LastUsedAt = &at
+	}
+
+	return nil
+}
+
+func cloneAPIKey(key entity.APIKey) entity.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	key.Salt = slices.Clone(key.Salt)
+	key.Hash = slices.Clone(key.Hash)
+
+	return key
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/project/library/internal/entity"
+)
+
+// LockAuthor only returns the author, transactions of the in-memory
+// repository are not isolated.
+func (i *inMemoryImpl) LockAuthor(ctx context.Context, authorID string) (entity.Author, error) {
+	return i.GetAuthor(ctx, authorID)
+}
+
+func (i *inMemoryImpl) ResolveAuthorID(_ context.Context, authorID string) (string, error) {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	if resolved, ok := i.authorAliases[authorID]; ok {
+		return resolved, nil
+	}
+
+	return authorID, nil
+}
+
+func (i *inMemoryImpl) MergeAuthors(_ context.Context, targetID string, sourceIDs []string) error {
+	i.authorsMx.Lock()
+	defer i.authorsMx.Unlock()
+
+	for _, authorID := range append([]string{targetID}, sourceIDs...) {
+		if _, ok := i.authors[authorID]; !ok {
+			return entity.ErrAuthorNotFound
+		}
+	}
+
+	for alias, authorID := range i.authorAliases {
+		if slices.Contains(sourceIDs, authorID) {
+			i.authorAliases[alias] = targetID
+		}
+	}
+
+	for _, sourceID := range sourceIDs {
+		i.authorAliases[sourceID] = targetID
+		delete(i.authors, sourceID)
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) FindDuplicateAuthors(
+	_ context.Context,
+	minSimilarity float64,
+	limit int,
+) ([]entity.AuthorDuplicate, error) {
+	i.authorsMx.RLock()
+	defer i.authorsMx.RUnlock()
+
+	duplicates := make([]entity.AuthorDuplicate, 0)
+
+	for _, author := range i.authors {
+		for _, other := range i.authors {
+			if author.ID >= other.ID {
+				continue
+			}
+
+			similarity := entity.NameSimilarity(author.Name, other.Name)
+
+			if similarity >= minSimilarity {
+				duplicates = append(duplicates, entity.AuthorDuplicate{
+					Author:     *author,
+					Duplicate:  *other,
+					Similarity: similarity,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(duplicates, func(a, b entity.AuthorDuplicate) int {
+		return cmp.Or(
+			cmp.Compare(b.Similarity, a.Similarity),
+			cmp.Compare(a.Author.ID, b.Author.ID),
+			cmp.Compare(a.Duplicate.ID, b.Duplicate.ID),
+		)
+	})
+
+	return duplicates[:min(limit, len(duplicates))], nil
+}
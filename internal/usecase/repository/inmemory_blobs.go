@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/project/library/internal/entity"
+)
+
+var _ BlobStore = (*inMemoryImpl)(nil)
+
+type blob struct {
+	contentType string
+	data        []byte
+}
+
+func (i *inMemoryImpl) PutBlob(_ context.Context, key string, contentType string, data []byte) error {
+	i.blobsMx.Lock()
+	defer i.blobsMx.Unlock()
+
+	i.blobs[key] = blob{contentType: contentType, data: slices.Clone(data)}
+
+	return nil
+}
+
+func (i *inMemoryImpl) GetBlob(_ context.Context, key string) ([]byte, string, error) {
+	i.blobsMx.RLock()
+	defer i.blobsMx.RUnlock()
+
+	stored, ok := i.blobs[key]
+
+	if !ok {
+		return nil, "", entity.ErrBlobNotFound
+	}
+
+	return slices.Clone(stored.data), stored.contentType, nil
+}
+
+func (i *inMemoryImpl) DeleteBlob(_ context.Context, key string) error {
+	i.blobsMx.Lock()
+	defer i.blobsMx.Unlock()
+
+	delete(i.blobs, key)
+
+	return nil
+}
+
+func (i *inMemoryImpl) BlobURL(key string) string {
+	return "/covers/" + key
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ CopiesRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateCopy(_ context.Context, bookCopy entity.Copy) (entity.Copy, error) {
+	i.booksMx.RLock()
+	_, ok := i.books[bookCopy.BookID]
+	i.booksMx.RUnlock()
+
+	if !ok {
+		return entity.Copy{}, entity.ErrBookNotFound
+	}
+
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	if i.barcodeTaken(bookCopy.Barcode, "") {
+		return entity.Copy{}, entity.ErrBarcodeTaken
+	}
+
+	now := time.Now().UTC()
+
+	bookCopy.ID = uuid.NewString()
+	bookCopy.CreatedAt = now
+	bookCopy.UpdatedAt = now
+
+	stored := bookCopy
+	i.copies[bookCopy.ID] = &stored
+
+	return bookCopy, nil
+}
+
+func (i *inMemoryImpl) GetCopy(_ context.Context, copyID string) (entity.Copy, error) {
+	i.copiesMx.RLock()
+	defer i.copiesMx.RUnlock()
+
+	bookCopy, ok := i.copies[copyID]
+
+	if !ok {
+		return entity.Copy{}, entity.ErrCopyNotFound
+	}
+
+	return *bookCopy, nil
+}
+
+// LockCopy only returns the copy, transactions of the in-memory repository
+// are not isolated.
+func (i *inMemoryImpl) LockCopy(ctx context.Context, copyID string) (entity.Copy, error) {
+	return i.GetCopy(ctx, copyID)
+}
+
+func (i *inMemoryImpl) UpdateCopy(_ context.Context, copyID string, patch entity.CopyPatch) (entity.Copy, error) {
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	stored, ok := i.copies[copyID]
+
+	if !ok {
+		return entity.Copy{}, entity.ErrCopyNotFound
+	}
+
+	if patch.Barcode != nil && i.barcodeTaken(*patch.Barcode, copyID) {
+		return entity.Copy{}, entity.ErrBarcodeTaken
+	}
+
+	applyCopyPatch(stored, patch)
+	stored.UpdatedAt = time.Now().UTC()
+
+	return *stored, nil
+}
+
+func (i *inMemoryImpl) DeleteCopy(_ context.Context, copyID string) error {
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	if _, ok := i.copies[copyID]; !ok {
+		return entity.ErrCopyNotFound
+	}
+
+	if i.copyHasLoans(copyID) {
+		return entity.ErrCopyHasLoans
+	}
+
+	delete(i.copies, copyID)
+
+	return nil
+}
+
+func (i *inMemoryImpl) GetBookCopies(_ context.Context, bookID string) ([]entity.Copy, error) {
+	i.copiesMx.RLock()
+	defer i.copiesMx.RUnlock()
+
+	copies := make([]entity.Copy, 0)
+
+	for _, bookCopy := range i.copies {
+		if bookCopy.BookID == bookID {
+			copies = append(copies, *bookCopy)
+		}
+	}
+
+	slices.SortFunc(copies, func(a, b entity.Copy) int {
+		return cmp.Compare(a.Barcode, b.Barcode)
+	})
+
+	return copies, nil
+}
+
+func (i *inMemoryImpl) GetBookAvailability(_ context.Context, bookID string) (entity.Availability, error) {
+	i.copiesMx.RLock()
+	defer i.copiesMx.RUnlock()
+
+	var availability entity.Availability
+
+	for _, bookCopy := range i.copies {
+		if bookCopy.BookID == bookID {
+			availability.Count(bookCopy.Status)
+		}
+	}
+
+	return availability, nil
+}
+
+// barcodeTaken reports whether another copy has the barcode. It must be
+// called with copiesMx held.
+func (i *inMemoryImpl) barcodeTaken(barcode string, copyID string) bool {
+	for _, bookCopy := range i.copies {
+		if bookCopy.Barcode == barcode && bookCopy.ID != copyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyCopyPatch(bookCopy *entity.Copy, patch entity.CopyPatch) {
+	for field, value := range map[*string]*string{
+		&bookCopy.Barcode:    patch.Barcode,
+		&bookCopy.Branch:     patch.Branch,
+		&bookCopy.Location:   patch.Location,
+		&bookCopy.AcquiredOn: patch.AcquiredOn,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	if patch.Condition != nil {
+		bookCopy.Condition = *patch.Condition
+	}
+
+	if patch.Status != nil {
+		bookCopy.Status = *patch.Status
+	}
+}
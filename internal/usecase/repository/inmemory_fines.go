@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ FinesRepository = (*inMemoryImpl)(nil)
+
+// TryLockFines always succeeds, there is a single process in memory.
+func (i *inMemoryImpl) TryLockFines(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (i *inMemoryImpl) GetOverdueLoans(
+	_ context.Context,
+	before time.Time,
+	maxFine int64,
+	afterID string,
+	limit int,
+) ([]entity.Loan, error) {
+	i.loansMx.RLock()
+	defer i.loansMx.RUnlock()
+
+	i.ledgerMx.RLock()
+	defer i.ledgerMx.RUnlock()
+
+	overdue := make([]entity.Loan, 0)
+
+	for _, loan := range i.loans {
+		if !loan.Active() || !loan.DueAt.Before(before) || loan.ID <= afterID {
+			continue
+		}
+
+		if maxFine == 0 || i.accruedFines(loan.ID) < maxFine {
+			overdue = append(overdue, *loan)
+		}
+	}
+
+	slices.SortFunc(overdue, func(a, b entity.Loan) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return overdue[:min(limit, len(overdue))], nil
+}
+
+// LockLoanFines does not lock anything, transactions are not isolated in
+// memory.
+func (i *inMemoryImpl) LockLoanFines(ctx context.Context, loanID string) (entity.Loan, int64, error) {
+	loan, err := i.GetLoan(ctx, loanID)
+
+	if err != nil {
+		return entity.Loan{}, 0, err
+	}
+
+	i.ledgerMx.RLock()
+	defer i.ledgerMx.RUnlock()
+
+	return loan, i.accruedFines(loanID), nil
+}
+
+// LockPatronBalance does not lock anything, transactions are not isolated in
+// memory.
+func (i *inMemoryImpl) LockPatronBalance(ctx context.Context, patronID string) (int64, error) {
+	return i.GetPatronBalance(ctx, patronID)
+}
+
+func (i *inMemoryImpl) GetPatronBalance(ctx context.Context, patronID string) (int64, error) {
+	if _, err := i.GetPatron(ctx, patronID); err != nil {
+		return 0, err
+	}
+
+	i.ledgerMx.RLock()
+	defer i.ledgerMx.RUnlock()
+
+	var balance int64
+
+	for _, transaction := range i.ledger {
+		if transaction.PatronID != patronID {
+			continue
+		}
+
+		if transaction.Debit == entity.LedgerAccountPatron {
+			balance += transaction.Amount
+		}
+
+		if transaction.Credit == entity.LedgerAccountPatron {
+			balance -= transaction.Amount
+		}
+	}
+
+	return balance, nil
+}
+
+func (i *inMemoryImpl) PostLedgerTransaction(
+	ctx context.Context,
+	transaction entity.LedgerTransaction,
+) (entity.LedgerTransaction, error) {
+	if transaction.Amount <= 0 {
+		return entity.LedgerTransaction{}, entity.ErrInvalidAmount
+	}
+
+	if _, err := i.GetPatron(ctx, transaction.PatronID); err != nil {
+		return entity.LedgerTransaction{}, err
+	}
+
+	if transaction.LoanID != "" {
+		if _, err := i.GetLoan(ctx, transaction.LoanID); err != nil {
+			return entity.LedgerTransaction{}, err
+		}
+	}
+
+	i.ledgerMx.Lock()
+	defer i.ledgerMx.Unlock()
+
+	transaction.ID = uuid.NewString()
+	i.ledger = append(i.ledger, transaction)
+
+	return transaction, nil
+}
+
+// accruedFines sums the fines charged on the loan. It must be called with
+// ledgerMx held.
+func (i *inMemoryImpl) accruedFines(loanID string) int64 {
+	var accrued int64
+
+	for _, transaction := range i.ledger {
+		if transaction.LoanID == loanID && transaction.Kind == entity.LedgerEntryAccrual {
+			accrued += transaction.Amount
+		}
+	}
+
+	return accrued
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ HoldsRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateHold(ctx context.Context, hold entity.Hold) (entity.Hold, error) {
+	if _, err := i.GetBook(ctx, hold.BookID); err != nil {
+		return entity.Hold{}, err
+	}
+
+	if _, err := i.GetPatron(ctx, hold.PatronID); err != nil {
+		return entity.Hold{}, err
+	}
+
+	i.holdsMx.Lock()
+	defer i.holdsMx.Unlock()
+
+	active := entity.HoldFilter{BookID: hold.BookID, PatronID: hold.PatronID, ActiveOnly: true}
+
+	for _, stored := range i.holds {
+		if active.Matches(*stored) {
+			return entity.Hold{}, entity.ErrHoldExists
+		}
+	}
+
+	hold.ID = uuid.NewString()
+
+	stored := hold
+	i.holds[hold.ID] = &stored
+
+	return hold, nil
+}
+
+func (i *inMemoryImpl) GetHold(_ context.Context, holdID string) (entity.Hold, error) {
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	hold, ok := i.holds[holdID]
+
+	if !ok {
+		return entity.Hold{}, entity.ErrHoldNotFound
+	}
+
+	return *hold, nil
+}
+
+// LockHold only returns the hold, transactions of the in-memory repository
+// are not isolated.
+func (i *inMemoryImpl) LockHold(ctx context.Context, holdID string) (entity.Hold, error) {
+	return i.GetHold(ctx, holdID)
+}
+
+func (i *inMemoryImpl) GetCopyHold(_ context.Context, copyID string) (entity.Hold, error) {
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	for _, hold := range i.holds {
+		if hold.CopyID == copyID && hold.Status == entity.HoldStatusReady {
+			return *hold, nil
+		}
+	}
+
+	return entity.Hold{}, entity.ErrHoldNotFound
+}
+
+func (i *inMemoryImpl) ListHolds(_ context.Context, filter entity.HoldFilter) ([]entity.Hold, error) {
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	return i.sortedHolds(filter.Matches), nil
+}
+
+func (i *inMemoryImpl) CountWaitingHolds(_ context.Context, bookID string) (int, error) {
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	return len(i.sortedHolds(isWaitingFor(bookID))), nil
+}
+
+func (i *inMemoryImpl) AssignCopy(_ context.Context, copyID string, pickupBy time.Time) (entity.Hold, error) {
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	bookCopy, ok := i.copies[copyID]
+
+	if !ok {
+		return entity.Hold{}, entity.ErrCopyNotFound
+	}
+
+	i.holdsMx.Lock()
+	defer i.holdsMx.Unlock()
+
+	waiting := i.sortedHolds(isWaitingFor(bookCopy.BookID))
+
+	if len(waiting) == 0 {
+		return entity.Hold{}, entity.ErrHoldNotFound
+	}
+
+	if bookCopy.Status != entity.CopyStatusAvailable {
+		return entity.Hold{}, entity.ErrCopyNotAvailable
+	}
+
+	bookCopy.Status = entity.CopyStatusOnHold
+	bookCopy.UpdatedAt = time.Now().UTC()
+
+	hold := i.holds[waiting[0].ID]
+	hold.Status = entity.HoldStatusReady
+	hold.CopyID = copyID
+	hold.PickupBy = &pickupBy
+
+	return *hold, nil
+}
+
+func (i *inMemoryImpl) CloseHold(
+	_ context.Context,
+	holdID string,
+	status entity.HoldStatus,
+	closedAt time.Time,
+) (entity.Hold, error) {
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	i.holdsMx.Lock()
+	defer i.holdsMx.Unlock()
+
+	hold, ok := i.holds[holdID]
+
+	if !ok || !hold.Active() {
+		return entity.Hold{}, entity.ErrHoldNotActive
+	}
+
+	if bookCopy, held := i.copies[hold.CopyID]; held && bookCopy.Status == entity.CopyStatusOnHold {
+		bookCopy.Status = entity.CopyStatusAvailable
+		bookCopy.UpdatedAt = time.Now().UTC()
+	}
+
+	hold.Status = status
+	hold.ClosedAt = &closedAt
+
+	return *hold, nil
+}
+
+func (i *inMemoryImpl) GetExpiredHolds(_ context.Context, before time.Time, limit int) ([]entity.Hold, error) {
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	expired := i.sortedHolds(func(hold entity.Hold) bool {
+		return hold.Status == entity.HoldStatusReady && hold.PickupBy.Before(before)
+	})
+
+	return expired[:min(limit, len(expired))], nil
+}
+
+// sortedHolds returns the holds accepted by keep, oldest first. It must be
+// called with holdsMx held.
+func (i *inMemoryImpl) sortedHolds(keep func(hold entity.Hold) bool) []entity.Hold {
+	holds := make([]entity.Hold, 0)
+
+	for _, hold := range i.holds {
+		if keep(*hold) {
+			holds = append(holds, *hold)
+		}
+	}
+
+	slices.SortFunc(holds, func(a, b entity.Hold) int {
+		return cmp.Or(a.PlacedAt.Compare(b.PlacedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return holds
+}
+
+func isWaitingFor(bookID string) func(hold entity.Hold) bool {
+	return func(hold entity.Hold) bool {
+		return hold.BookID == bookID && hold.Status == entity.HoldStatusWaiting
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+var _ IdempotencyRepository = (*inMemoryImpl)(nil)
+
+// idempotencyEntry is an idempotency record with the moment it was
+// reserved.
+type idempotencyEntry struct {
+	record     entity.IdempotencyRecord
+	reservedAt time.Time
+}
+
+func (i *inMemoryImpl) ReserveIdempotencyKey(
+	_ context.Context,
+	record entity.IdempotencyRecord,
+	pendingTTL time.Duration,
+) (entity.IdempotencyRecord, bool, error) {
+	i.idempotencyMx.Lock()
+	defer i.idempotencyMx.Unlock()
+
+	now := time.Now().UTC()
+	entry, ok := i.idempotencyKeys[record.Key]
+
+	if ok && entry.record.ExpiresAt.After(now) && (entry.record.Completed || now.Sub(entry.reservedAt) <= pendingTTL) {
+		return cloneIdempotencyRecord(entry.record), false, nil
+	}
+
+	record = cloneIdempotencyRecord(record)
+	record.Response, record.Completed = nil, false
+	i.idempotencyKeys[record.Key] = &idempotencyEntry{record: record, reservedAt: now}
+
+	return cloneIdempotencyRecord(record), true, nil
+}
+
+func (i *inMemoryImpl) CompleteIdempotencyKey(_ context.Context, key, token string, response []byte) error {
+	i.idempotencyMx.Lock()
+	defer i.idempotencyMx.Unlock()
+
+	if entry, ok := i.idempotencyKeys[key]; ok && entry.record.Token == token {
+		entry.record.Response = slices.Clone(response)
+		entry.record.Completed = true
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) ReleaseIdempotencyKey(_ context.Context, key, token string) error {
+	i.idempotencyMx.Lock()
+	defer i.idempotencyMx.Unlock()
+
+	if entry, ok := i.idempotencyKeys[key]; ok && entry.record.Token == token && !entry.record.Completed {
+		delete(i.idempotencyKeys, key)
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) PruneIdempotencyKeys(_ context.Context, before time.Time) (int64, error) {
+	i.idempotencyMx.Lock()
+	defer i.idempotencyMx.Unlock()
+
+	var pruned int64
+
+	for key, entry := range i.idempotencyKeys {
+		if entry.record.ExpiresAt.Before(before) {
+			delete(i.idempotencyKeys, key)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
+
+func cloneIdempotencyRecord(record entity.IdempotencyRecord) entity.IdempotencyRecord {
+	record.Fingerprint = slices.Clone(record.Fingerprint)
+	record.Response = slices.Clone(record.Response)
+
+	return record
+}
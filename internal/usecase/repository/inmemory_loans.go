@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ LoansRepository = (*inMemoryImpl)(nil)
+
+// LockPatronLoans does not lock anything, transactions are not isolated in
+// memory.
+func (i *inMemoryImpl) LockPatronLoans(ctx context.Context, patronID string) (int, error) {
+	if _, err := i.GetPatron(ctx, patronID); err != nil {
+		return 0, err
+	}
+
+	i.loansMx.RLock()
+	defer i.loansMx.RUnlock()
+
+	active := 0
+
+	for _, loan := range i.loans {
+		if loan.PatronID == patronID && loan.Active() {
+			active++
+		}
+	}
+
+	return active, nil
+}
+
+func (i *inMemoryImpl) CreateLoan(ctx context.Context, loan entity.Loan) (entity.Loan, error) {
+	if _, err := i.GetPatron(ctx, loan.PatronID); err != nil {
+		return entity.Loan{}, err
+	}
+
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	bookCopy, ok := i.copies[loan.CopyID]
+
+	if !ok {
+		return entity.Loan{}, entity.ErrCopyNotFound
+	}
+
+	if bookCopy.Status != entity.CopyStatusAvailable {
+		return entity.Loan{}, entity.ErrCopyNotAvailable
+	}
+
+	i.loansMx.Lock()
+	defer i.loansMx.Unlock()
+
+	loan.ID = uuid.NewString()
+	loan.Renewals = 0
+	loan.ReturnedAt = nil
+
+	stored := loan
+	i.loans[loan.ID] = &stored
+
+	bookCopy.Status = entity.CopyStatusOnLoan
+	bookCopy.UpdatedAt = time.Now().UTC()
+
+	return loan, nil
+}
+
+func (i *inMemoryImpl) GetLoan(_ context.Context, loanID string) (entity.Loan, error) {
+	i.loansMx.RLock()
+	defer i.loansMx.RUnlock()
+
+	loan, ok := i.loans[loanID]
+
+	if !ok {
+		return entity.Loan{}, entity.ErrLoanNotFound
+	}
+
+	return *loan, nil
+}
+
+func (i *inMemoryImpl) RenewLoan(
+	_ context.Context,
+	loanID string,
+	renewals int,
+	dueAt time.Time,
+) (entity.Loan, error) {
+	i.loansMx.Lock()
+	defer i.loansMx.Unlock()
+
+	loan, ok := i.loans[loanID]
+
+	if !ok {
+		return entity.Loan{}, entity.ErrLoanNotFound
+	}
+
+	if loan.Renewals != renewals || !loan.Active() {
+		return entity.Loan{}, entity.ErrVersionMismatch
+	}
+
+	loan.DueAt = dueAt
+	loan.Renewals++
+
+	return *loan, nil
+}
+
+func (i *inMemoryImpl) ReturnCopy(_ context.Context, copyID string, returnedAt time.Time) (entity.Loan, error) {
+	i.copiesMx.Lock()
+	defer i.copiesMx.Unlock()
+
+	i.loansMx.Lock()
+	defer i.loansMx.Unlock()
+
+	for _, loan := range i.loans {
+		if loan.CopyID != copyID || !loan.Active() {
+			continue
+		}
+
+		loan.ReturnedAt = &returnedAt
+
+		if bookCopy, ok := i.copies[copyID]; ok {
+			bookCopy.Status = entity.CopyStatusAvailable
+			bookCopy.UpdatedAt = time.Now().UTC()
+		}
+
+		return *loan, nil
+	}
+
+	return entity.Loan{}, entity.ErrCopyNotOnLoan
+}
+
+// copyHasLoans reports whether the copy has ever been lent or held. It must
+// be called with copiesMx held.
+func (i *inMemoryImpl) copyHasLoans(copyID string) bool {
+	i.loansMx.RLock()
+	defer i.loansMx.RUnlock()
+
+	for _, loan := range i.loans {
+		if loan.CopyID == copyID {
+			return true
+		}
+	}
+
+	i.holdsMx.RLock()
+	defer i.holdsMx.RUnlock()
+
+	for _, hold := range i.holds {
+		if hold.CopyID == copyID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ PatronsRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreatePatron(_ context.Context, patron entity.Patron) (entity.Patron, error) {
+	i.patronsMx.Lock()
+	defer i.patronsMx.Unlock()
+
+	if i.cardNumberTaken(patron.CardNumber, "") {
+		return entity.Patron{}, entity.ErrCardNumberTaken
+	}
+
+	now := time.Now().UTC()
+
+	patron.ID = uuid.NewString()
+	patron.CreatedAt = now
+	patron.UpdatedAt = now
+
+	stored := patron
+	i.patrons[patron.ID] = &stored
+
+	return patron, nil
+}
+
+func (i *inMemoryImpl) GetPatron(_ context.Context, patronID string) (entity.Patron, error) {
+	i.patronsMx.RLock()
+	defer i.patronsMx.RUnlock()
+
+	patron, ok := i.patrons[patronID]
+
+	if !ok {
+		return entity.Patron{}, entity.ErrPatronNotFound
+	}
+
+	return *patron, nil
+}
+
+func (i *inMemoryImpl) UpdatePatron(
+	_ context.Context,
+	patronID string,
+	patch entity.PatronPatch,
+) (entity.Patron, error) {
+	i.patronsMx.Lock()
+	defer i.patronsMx.Unlock()
+
+	stored, ok := i.patrons[patronID]
+
+	if !ok {
+		return entity.Patron{}, entity.ErrPatronNotFound
+	}
+
+	if patch.CardNumber != nil && i.cardNumberTaken(*patch.CardNumber, patronID) {
+		return entity.Patron{}, entity.ErrCardNumberTaken
+	}
+
+	applyPatronPatch(stored, patch)
+	stored.UpdatedAt = time.Now().UTC()
+
+	return *stored, nil
+}
+
+func (i *inMemoryImpl) ListPatrons(
+	_ context.Context,
+	filter entity.PatronFilter,
+	afterCardNumber string,
+	limit int,
+) ([]entity.Patron, error) {
+	i.patronsMx.RLock()
+	defer i.patronsMx.RUnlock()
+
+	patrons := make([]entity.Patron, 0)
+
+	for _, patron := range i.patrons {
+		if patron.CardNumber > afterCardNumber && filter.Matches(*patron) {
+			patrons = append(patrons, *patron)
+		}
+	}
+
+	slices.SortFunc(patrons, func(a, b entity.Patron) int {
+		return cmp.Compare(a.CardNumber, b.CardNumber)
+	})
+
+	return patrons[:min(limit, len(patrons))], nil
+}
+
+// cardNumberTaken reports whether another patron has the card number. It
+// must be called with patronsMx held.
+func (i *inMemoryImpl) cardNumberTaken(cardNumber string, patronID string) bool {
+	for _, patron := range i.patrons {
+		if patron.CardNumber == cardNumber && patron.ID != patronID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyPatronPatch(patron *entity.Patron, patch entity.PatronPatch) {
+	for field, value := range map[*string]*string{
+		&patron.CardNumber:       patch.CardNumber,
+		&patron.ExpiresOn:        patch.ExpiresOn,
+		&patron.SuspensionReason: patch.SuspensionReason,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	for field, value := range map[*entity.Sensitive]*entity.Sensitive{
+		&patron.Name:    patch.Name,
+		&patron.Email:   patch.Email,
+		&patron.Phone:   patch.Phone,
+		&patron.Address: patch.Address,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	if patch.MembershipType != nil {
+		patron.MembershipType = *patch.MembershipType
+	}
+
+	if patch.Status != nil {
+		patron.Status = *patch.Status
+	}
+}
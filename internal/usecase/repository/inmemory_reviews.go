@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ ReviewsRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	if _, err := i.GetBook(ctx, review.BookID); err != nil {
+		return entity.Review{}, err
+	}
+
+	if _, err := i.GetPatron(ctx, review.PatronID); err != nil {
+		return entity.Review{}, err
+	}
+
+	if err := entity.CheckRating(review.Rating); err != nil {
+		return entity.Review{}, err
+	}
+
+	i.reviewsMx.Lock()
+	defer i.reviewsMx.Unlock()
+
+	existing := entity.ReviewFilter{BookID: review.BookID, PatronID: review.PatronID}
+
+	for _, stored := range i.reviews {
+		if existing.Matches(*stored) {
+			return entity.Review{}, entity.ErrReviewExists
+		}
+	}
+
+	now := time.Now().UTC()
+
+	review.ID = uuid.NewString()
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	stored := review
+	i.reviews[review.ID] = &stored
+	i.addBookRating(review.BookID, 1, review.Rating)
+
+	return review, nil
+}
+
+func (i *inMemoryImpl) GetReview(_ context.Context, reviewID string) (entity.Review, error) {
+	i.reviewsMx.RLock()
+	defer i.reviewsMx.RUnlock()
+
+	review, ok := i.reviews[reviewID]
+
+	if !ok {
+		return entity.Review{}, entity.ErrReviewNotFound
+	}
+
+	return *review, nil
+}
+
+func (i *inMemoryImpl) UpdateReview(
+	_ context.Context,
+	reviewID string,
+	patch entity.ReviewPatch,
+) (entity.Review, error) {
+	i.reviewsMx.Lock()
+	defer i.reviewsMx.Unlock()
+
+	stored, ok := i.reviews[reviewID]
+
+	if !ok {
+		return entity.Review{}, entity.ErrReviewNotFound
+	}
+
+	updated := *stored
+
+	if patch.Rating != nil {
+		updated.Rating = *patch.Rating
+	}
+
+	if patch.Text != nil {
+		updated.Text = *patch.Text
+	}
+
+	if err := entity.CheckRating(updated.Rating); err != nil {
+		return entity.Review{}, err
+	}
+
+	i.addBookRating(updated.BookID, 0, updated.Rating-stored.Rating)
+
+	updated.UpdatedAt = time.Now().UTC()
+	*stored = updated
+
+	return updated, nil
+}
+
+func (i *inMemoryImpl) DeleteReview(_ context.Context, reviewID string) error {
+	i.reviewsMx.Lock()
+	defer i.reviewsMx.Unlock()
+
+	review, ok := i.reviews[reviewID]
+
+	if !ok {
+		return entity.ErrReviewNotFound
+	}
+
+	i.addBookRating(review.BookID, -1, -review.Rating)
+	delete(i.reviews, reviewID)
+
+	return nil
+}
+
+func (i *inMemoryImpl) ListReviews(
+	_ context.Context,
+	filter entity.ReviewFilter,
+	afterCreatedAt time.Time,
+	afterID string,
+	limit int,
+) ([]entity.Review, error) {
+	i.reviewsMx.RLock()
+	defer i.reviewsMx.RUnlock()
+
+	reviews := make([]entity.Review, 0)
+
+	for _, review := range i.reviews {
+		if filter.Matches(*review) && (afterID == "" || compareNewestFirst(*review, afterCreatedAt, afterID) > 0) {
+			reviews = append(reviews, *review)
+		}
+	}
+
+	slices.SortFunc(reviews, func(a, b entity.Review) int {
+		return compareNewestFirst(a, b.CreatedAt, b.ID)
+	})
+
+	return reviews[:min(limit, len(reviews))], nil
+}
+
+// compareNewestFirst orders the review before the one created at createdAt
+// with the id when it is newer.
+func compareNewestFirst(review entity.Review, createdAt time.Time, id string) int {
+	return -cmp.Or(review.CreatedAt.Compare(createdAt), cmp.Compare(review.ID, id))
+}
+
+func (i *inMemoryImpl) GetBookRating(_ context.Context, bookID string) (entity.BookRating, error) {
+	i.reviewsMx.RLock()
+	defer i.reviewsMx.RUnlock()
+
+	return i.bookRatings[bookID], nil
+}
+
+// addBookRating must be called with reviewsMx held.
+func (i *inMemoryImpl) addBookRating(bookID string, count int, sum int) {
+	rating := i.bookRatings[bookID]
+	rating.Count += count
+	rating.Sum += sum
+	i.bookRatings[bookID] = rating
+}
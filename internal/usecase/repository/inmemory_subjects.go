@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ SubjectsRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateSubject(_ context.Context, subject entity.Subject) (entity.Subject, error) {
+	i.subjectsMx.Lock()
+	defer i.subjectsMx.Unlock()
+
+	if err := i.checkSubjectPlace(subject.ID, subject.Name, subject.ParentID); err != nil {
+		return entity.Subject{}, err
+	}
+
+	now := time.Now().UTC()
+
+	subject.ID = uuid.NewString()
+	subject.CreatedAt = now
+	subject.UpdatedAt = now
+
+	stored := subject
+	i.subjects[subject.ID] = &stored
+
+	return subject, nil
+}
+
+func (i *inMemoryImpl) GetSubject(_ context.Context, subjectID string) (entity.Subject, error) {
+	i.subjectsMx.RLock()
+	defer i.subjectsMx.RUnlock()
+
+	subject, ok := i.subjects[subjectID]
+
+	if !ok {
+		return entity.Subject{}, entity.ErrSubjectNotFound
+	}
+
+	return *subject, nil
+}
+
+func (i *inMemoryImpl) UpdateSubject(
+	_ context.Context,
+	subjectID string,
+	patch entity.SubjectPatch,
+) (entity.Subject, error) {
+	i.subjectsMx.Lock()
+	defer i.subjectsMx.Unlock()
+
+	stored, ok := i.subjects[subjectID]
+
+	if !ok {
+		return entity.Subject{}, entity.ErrSubjectNotFound
+	}
+
+	updated := *stored
+
+	if patch.Name != nil {
+		updated.Name = *patch.Name
+	}
+
+	if patch.ParentID != nil {
+		updated.ParentID = *patch.ParentID
+	}
+
+	if err := i.checkSubjectPlace(subjectID, updated.Name, updated.ParentID); err != nil {
+		return entity.Subject{}, err
+	}
+
+	updated.UpdatedAt = time.Now().UTC()
+	*stored = updated
+
+	return updated, nil
+}
+
+// checkSubjectPlace verifies that the subject named name may be put under
+// the parent. It must be called with subjectsMx held.
+func (i *inMemoryImpl) checkSubjectPlace(subjectID string, name string, parentID string) error {
+	for ancestorID := parentID; ancestorID != ""; ancestorID = i.subjects[ancestorID].ParentID {
+		if _, ok := i.subjects[ancestorID]; !ok {
+			return entity.ErrSubjectNotFound
+		}
+
+		if ancestorID == subjectID {
+			return entity.ErrSubjectCycle
+		}
+	}
+
+	for _, sibling := range i.subjects {
+		if sibling.ID != subjectID && sibling.ParentID == parentID && strings.EqualFold(sibling.Name, name) {
+			return entity.ErrSubjectNameTaken
+		}
+	}
+
+	return nil
+}
+
+func (i *inMemoryImpl) DeleteSubject(_ context.Context, subjectID string) error {
+	i.subjectsMx.Lock()
+	defer i.subjectsMx.Unlock()
+
+	if _, ok := i.subjects[subjectID]; !ok {
+		return entity.ErrSubjectNotFound
+	}
+
+	for _, subject := range i.subjects {
+		if subject.ParentID == subjectID {
+			return entity.ErrSubjectInUse
+		}
+	}
+
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	for _, book := range i.books {
+		if slices.Contains(book.SubjectIDs, subjectID) {
+			return entity.ErrSubjectInUse
+		}
+	}
+
+	delete(i.subjects, subjectID)
+
+	return nil
+}
+
+func (i *inMemoryImpl) ListSubjects(_ context.Context, rootID string) ([]entity.Subject, error) {
+	i.subjectsMx.RLock()
+	defer i.subjectsMx.RUnlock()
+
+	subjects := make([]entity.Subject, 0)
+
+	for _, subject := range i.subjects {
+		if rootID == "" || i.isDescendant(subject.ID, rootID) {
+			subjects = append(subjects, *subject)
+		}
+	}
+
+	slices.SortFunc(subjects, func(a, b entity.Subject) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	return subjects, nil
+}
+
+func (i *inMemoryImpl) ListBooks(
+	_ context.Context,
+	filter entity.BookFilter,
+	afterName string,
+	afterID string,
+	limit int,
+) ([]entity.Book, error) {
+	books := i.filterBooks(filter)
+
+	slices.SortFunc(books, compareBooks)
+
+	if afterID != "" {
+		after := entity.Book{ID: afterID, Name: afterName}
+		books = slices.DeleteFunc(books, func(book entity.Book) bool {
+			return compareBooks(book, after) <= 0
+		})
+	}
+
+	return books[:min(limit, len(books))], nil
+}
+
+func (i *inMemoryImpl) GetTagCounts(
+	_ context.Context,
+	filter entity.BookFilter,
+	limit int,
+) ([]entity.TagCount, error) {
+	counts := make(map[string]int)
+
+	for _, book := range i.filterBooks(filter) {
+		for _, tag := range book.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]entity.TagCount, 0, len(counts))
+
+	for tag, count := range counts {
+		result = append(result, entity.TagCount{Tag: tag, Count: count})
+	}
+
+	slices.SortFunc(result, func(a, b entity.TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+	})
+
+	return result[:min(limit, len(result))], nil
+}
+
+// filterBooks returns the books selected by the filter in no particular
+// order.
+func (i *inMemoryImpl) filterBooks(filter entity.BookFilter) []entity.Book {
+	i.subjectsMx.RLock()
+	defer i.subjectsMx.RUnlock()
+
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	books := make([]entity.Book, 0)
+
+	for _, book := range i.books {
+		if filter.Tag != "" && !slices.Contains(book.Tags, filter.Tag) {
+			continue
+		}
+
+		if filter.Title != "" && entity.NormalizeTitle(book.Name) != filter.Title {
+			continue
+		}
+
+		if filter.SubjectID != "" && !slices.ContainsFunc(book.SubjectIDs, func(subjectID string) bool {
+			return i.isDescendant(subjectID, filter.SubjectID)
+		}) {
+			continue
+		}
+
+		books = append(books, cloneBook(*book))
+	}
+
+	return books
+}
+
+// isDescendant reports whether the subject is the ancestor or one of its
+// descendants. It must be called with subjectsMx held.
+func (i *inMemoryImpl) isDescendant(subjectID string, ancestorID string) bool {
+	for subjectID != "" {
+		if subjectID == ancestorID {
+			return true
+		}
+
+		subject, ok := i.subjects[subjectID]
+
+		if !ok {
+			return false
+		}
+
+		subjectID = subject.ParentID
+	}
+
+	return false
+}
+
+func (i *inMemoryImpl) checkSubjectsExist(subjectIDs []string) error {
+	i.subjectsMx.RLock()
+	defer i.subjectsMx.RUnlock()
+
+	for _, subjectID := range subjectIDs {
+		if _, ok := i.subjects[subjectID]; !ok {
+			return entity.ErrSubjectNotFound
+		}
+	}
+
+	return nil
+}
+
+func compareBooks(a, b entity.Book) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+}
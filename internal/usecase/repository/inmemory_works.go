@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project/library/internal/entity"
+)
+
+var _ WorksRepository = (*inMemoryImpl)(nil)
+
+func (i *inMemoryImpl) CreateWork(_ context.Context, work entity.Work) (entity.Work, error) {
+	i.worksMx.Lock()
+	defer i.worksMx.Unlock()
+
+	work.ID = uuid.NewString()
+	work.CreatedAt = time.Now().UTC()
+
+	stored := work
+	i.works[work.ID] = &stored
+
+	return work, nil
+}
+
+func (i *inMemoryImpl) GetWork(_ context.Context, workID string) (entity.Work, error) {
+	i.worksMx.RLock()
+	defer i.worksMx.RUnlock()
+
+	work, ok := i.works[workID]
+
+	if !ok {
+		return entity.Work{}, entity.ErrWorkNotFound
+	}
+
+	return *work, nil
+}
+
+func (i *inMemoryImpl) CreateSeries(_ context.Context, series entity.Series) (entity.Series, error) {
+	i.worksMx.Lock()
+	defer i.worksMx.Unlock()
+
+	series.ID = uuid.NewString()
+	series.CreatedAt = time.Now().UTC()
+
+	stored := series
+	i.series[series.ID] = &stored
+
+	return series, nil
+}
+
+func (i *inMemoryImpl) GetSeries(_ context.Context, seriesID string) (entity.Series, error) {
+	i.worksMx.RLock()
+	defer i.worksMx.RUnlock()
+
+	series, ok := i.series[seriesID]
+
+	if !ok {
+		return entity.Series{}, entity.ErrSeriesNotFound
+	}
+
+	return *series, nil
+}
+
+func (i *inMemoryImpl) GetWorkEditions(_ context.Context, workID string) ([]entity.Book, error) {
+	editions := i.selectBooks(func(book entity.Book) bool {
+		return book.WorkID == workID
+	})
+
+	slices.SortFunc(editions, func(a, b entity.Book) int {
+		return cmp.Or(compareNumbers(a.PublicationYear, b.PublicationYear), compareBooks(a, b))
+	})
+
+	return editions, nil
+}
+
+func (i *inMemoryImpl) GetSeriesBooks(_ context.Context, seriesID string) ([]entity.Book, error) {
+	books := i.selectBooks(func(book entity.Book) bool {
+		return book.SeriesID == seriesID
+	})
+
+	slices.SortFunc(books, func(a, b entity.Book) int {
+		return cmp.Or(compareNumbers(a.SeriesPosition, b.SeriesPosition), compareBooks(a, b))
+	})
+
+	return books, nil
+}
+
+func (i *inMemoryImpl) selectBooks(selected func(book entity.Book) bool) []entity.Book {
+	i.booksMx.RLock()
+	defer i.booksMx.RUnlock()
+
+	books := make([]entity.Book, 0)
+
+	for _, book := range i.books {
+		if selected(*book) {
+			books = append(books, cloneBook(*book))
+		}
+	}
+
+	return books
+}
+
+// compareNumbers orders unknown zero values after the others.
+func compareNumbers(a, b int) int {
+	return cmp.Or(compareBools(a == 0, b == 0), cmp.Compare(a, b))
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// checkWorkAndSeriesExist checks the work and the series a book is set to,
+// nil and empty ids are not checked.
+func (i *inMemoryImpl) checkWorkAndSeriesExist(workID *string, seriesID *string) error {
+	i.worksMx.RLock()
+	defer i.worksMx.RUnlock()
+
+	if workID != nil && *workID != "" && i.works[*workID] == nil {
+		return entity.ErrWorkNotFound
+	}
+
+	if seriesID != nil && *seriesID != "" && i.series[*seriesID] == nil {
+		return entity.ErrSeriesNotFound
+	}
+
+	return nil
+}
@@ -1 +1,414 @@
 package repository
+
+import (
+	"context"
+	"crypto"
+	"time"
+
+	"github.com/project/library/internal/entity"
+)
+
+type (
+	AuthorRepository interface {
+		// CreateAuthor fails with entity.ErrExternalIDTaken when an external
+		// ID of the author belongs to another one.
+		CreateAuthor(ctx context.Context, author entity.Author) (entity.Author, error)
+		GetAuthor(ctx context.Context, authorID string) (entity.Author, error)
+		GetAuthorByExternalID(ctx context.Context, scheme entity.ExternalIDScheme, value string) (entity.Author, error)
+		// UpdateAuthor applies patch and returns the updated author. It fails
+		// with entity.ErrVersionMismatch when expectedVersion is set and
+		// differs from the stored one, and with entity.ErrExternalIDTaken or
+		// entity.ErrInvalidLifeDates when the result would be inconsistent.
+		UpdateAuthor(
+			ctx context.Context,
+			authorID string,
+			patch entity.AuthorPatch,
+			expectedVersion *uint64,
+		) (entity.Author, error)
+		// GetAuthorBooks returns the books crediting the author in the role,
+		// or in any role when it is empty.
+		GetAuthorBooks(ctx context.Context, authorID string, role entity.ContributorRole) ([]entity.Book, error)
+		// GetAuthorAsOf returns the version of the author that was current
+		// at asOf.
+		GetAuthorAsOf(ctx context.Context, authorID string, asOf time.Time) (entity.Author, error)
+		// GetAuthorBooksAsOf returns the books crediting the author in the
+		// role at asOf, in the versions that were current then.
+		GetAuthorBooksAsOf(
+			ctx context.Context,
+			authorID string,
+			role entity.ContributorRole,
+			asOf time.Time,
+		) ([]entity.Book, error)
+		// LockAuthor returns the author and keeps other transactions from
+		// changing, crediting or merging it until the current one ends.
+		LockAuthor(ctx context.Context, authorID string) (entity.Author, error)
+		// ResolveAuthorID returns the id of the author the one with authorID
+		// has been merged into, or authorID itself when it was not merged.
+		ResolveAuthorID(ctx context.Context, authorID string) (string, error)
+		// MergeAuthors deletes the source authors, which must not be
+		// credited on any book, and records their ids as aliases of the
+		// target. Aliases of the sources move to the target.
+		MergeAuthors(ctx context.Context, targetID string, sourceIDs []string) error
+		// FindDuplicateAuthors returns up to limit pairs of authors whose
+		// names have at least the trigram similarity, the most similar
+		// first.
+		FindDuplicateAuthors(ctx context.Context, minSimilarity float64, limit int) ([]entity.AuthorDuplicate, error)
+	}
+
+	BooksRepository interface {
+		// CreateBook fails with entity.ErrISBNTaken when the ISBN of the book
+		// belongs to another one, with entity.ErrSubjectNotFound when one of
+		// its subjects does not exist and with entity.ErrWorkNotFound or
+		// entity.ErrSeriesNotFound when its work or series does not.
+		CreateBook(ctx context.Context, book entity.Book) (entity.Book, error)
+		GetBook(ctx context.Context, bookID string) (entity.Book, error)
+		// LockBook returns the book and keeps other transactions from
+		// changing it until the current one ends.
+		LockBook(ctx context.Context, bookID string) (entity.Book, error)
+		GetBookByISBN(ctx context.Context, isbn string) (entity.Book, error)
+		// GetBookByTitle returns a book with the normalized title crediting
+		// exactly the authors, in whatever roles. It fails with
+		// entity.ErrBookNotFound when there is none.
+		GetBookByTitle(ctx context.Context, title string, authorIDs []string) (entity.Book, error)
+		// LockBookTitle keeps other transactions from locking the same
+		// normalized title until the current one ends, so duplicate checks
+		// of concurrent registrations do not miss each other.
+		LockBookTitle(ctx context.Context, title string) error
+		// UpdateBook applies patch and returns the updated book. It fails
+		// with entity.ErrVersionMismatch when expectedVersion is set and
+		// differs from the stored one, with entity.ErrISBNTaken when the new
+		// ISBN belongs to another book, with entity.ErrSubjectNotFound,
+		// entity.ErrWorkNotFound or entity.ErrSeriesNotFound when a new
+		// subject, work or series does not exist and with
+		// entity.ErrInvalidSeriesPosition when the book would be numbered
+		// outside a series.
+		UpdateBook(
+			ctx context.Context,
+			bookID string,
+			patch entity.BookPatch,
+			expectedVersion *uint64,
+		) (entity.Book, error)
+		// GetBookAsOf returns the version of the book that was current at
+		// asOf.
+		GetBookAsOf(ctx context.Context, bookID string, asOf time.Time) (entity.Book, error)
+		// ListBooks returns up to limit books selected by the filter that
+		// sort after the book named afterName with the id afterID, ordered
+		// by name and id. An empty afterID starts from the first book.
+		ListBooks(
+			ctx context.Context,
+			filter entity.BookFilter,
+			afterName string,
+			afterID string,
+			limit int,
+		) ([]entity.Book, error)
+		// GetTagCounts returns up to limit tags of the books selected by
+		// the filter, the most used first.
+		GetTagCounts(ctx context.Context, filter entity.BookFilter, limit int) ([]entity.TagCount, error)
+	}
+
+	SubjectsRepository interface {
+		// CreateSubject fails with entity.ErrSubjectNotFound when the parent
+		// does not exist and with entity.ErrSubjectNameTaken when a sibling
+		// has the same name.
+		CreateSubject(ctx context.Context, subject entity.Subject) (entity.Subject, error)
+		GetSubject(ctx context.Context, subjectID string) (entity.Subject, error)
+		// UpdateSubject applies patch and returns the updated subject. It
+		// fails with entity.ErrSubjectCycle when the subject would be moved
+		// under one of its descendants.
+		UpdateSubject(ctx context.Context, subjectID string, patch entity.SubjectPatch) (entity.Subject, error)
+		// DeleteSubject fails with entity.ErrSubjectInUse when the subject
+		// has subtopics or classifies a book.
+		DeleteSubject(ctx context.Context, subjectID string) error
+		// ListSubjects returns the subject and all its descendants, or every
+		// subject when rootID is empty, ordered by name.
+		ListSubjects(ctx context.Context, rootID string) ([]entity.Subject, error)
+	}
+
+	// ReviewsRepository keeps the aggregated rating of every book in step
+	// with its reviews, each write updates both in one transaction.
+	ReviewsRepository interface {
+		// CreateReview fails with entity.ErrReviewExists when the patron has
+		// reviewed the book already.
+		CreateReview(ctx context.Context, review entity.Review) (entity.Review, error)
+		GetReview(ctx context.Context, reviewID string) (entity.Review, error)
+		UpdateReview(ctx context.Context, reviewID string, patch entity.ReviewPatch) (entity.Review, error)
+		DeleteReview(ctx context.Context, reviewID string) error
+		// ListReviews returns up to limit reviews selected by the filter,
+		// newest first. A non-empty afterID starts the list right after the
+		// review created at afterCreatedAt with that id.
+		ListReviews(
+			ctx context.Context,
+			filter entity.ReviewFilter,
+			afterCreatedAt time.Time,
+			afterID string,
+			limit int,
+		) ([]entity.Review, error)
+		// GetBookRating returns the aggregated rating of the book, zero
+		// when it has no reviews.
+		GetBookRating(ctx context.Context, bookID string) (entity.BookRating, error)
+	}
+
+	WorksRepository interface {
+		CreateWork(ctx context.Context, work entity.Work) (entity.Work, error)
+		GetWork(ctx context.Context, workID string) (entity.Work, error)
+		CreateSeries(ctx context.Context, series entity.Series) (entity.Series, error)
+		GetSeries(ctx context.Context, seriesID string) (entity.Series, error)
+		// GetWorkEditions returns the books of the work ordered by
+		// publication year, unknown years last, and name.
+		GetWorkEditions(ctx context.Context, workID string) ([]entity.Book, error)
+		// GetSeriesBooks returns the books of the series in reading order,
+		// unnumbered books last ordered by name.
+		GetSeriesBooks(ctx context.Context, seriesID string) ([]entity.Book, error)
+	}
+
+	CopiesRepository interface {
+		// CreateCopy fails with entity.ErrBookNotFound when the book does not
+		// exist and with entity.ErrBarcodeTaken when the barcode belongs to
+		// another copy.
+		CreateCopy(ctx context.Context, bookCopy entity.Copy) (entity.Copy, error)
+		GetCopy(ctx context.Context, copyID string) (entity.Copy, error)
+		// LockCopy returns the copy and keeps other transactions from
+		// changing it until the current one ends.
+		LockCopy(ctx context.Context, copyID string) (entity.Copy, error)
+		// UpdateCopy applies patch and returns the updated copy. It fails
+		// with entity.ErrBarcodeTaken when the new barcode belongs to
+		// another copy.
+		UpdateCopy(ctx context.Context, copyID string, patch entity.CopyPatch) (entity.Copy, error)
+		DeleteCopy(ctx context.Context, copyID string) error
+		// GetBookCopies returns the copies of the book ordered by barcode.
+		GetBookCopies(ctx context.Context, bookID string) ([]entity.Copy, error)
+		GetBookAvailability(ctx context.Context, bookID string) (entity.Availability, error)
+	}
+
+	PatronsRepository interface {
+		// CreatePatron fails with entity.ErrCardNumberTaken when the card
+		// number belongs to another patron.
+		CreatePatron(ctx context.Context, patron entity.Patron) (entity.Patron, error)
+		GetPatron(ctx context.Context, patronID string) (entity.Patron, error)
+		// UpdatePatron applies patch and returns the updated patron. It fails
+		// with entity.ErrCardNumberTaken when the new card number belongs to
+		// another patron.
+		UpdatePatron(ctx context.Context, patronID string, patch entity.PatronPatch) (entity.Patron, error)
+		// ListPatrons returns up to limit patrons selected by the filter with
+		// card numbers greater than afterCardNumber, ordered by card number.
+		ListPatrons(
+			ctx context.Context,
+			filter entity.PatronFilter,
+			afterCardNumber string,
+			limit int,
+		) ([]entity.Patron, error)
+	}
+
+	LoansRepository interface {
+		// LockPatronLoans keeps other transactions from lending to the patron
+		// until the current one ends and returns the number of the active
+		// loans of the patron.
+		LockPatronLoans(ctx context.Context, patronID string) (int, error)
+		// CreateLoan lends the copy and marks it as on loan. It fails with
+		// entity.ErrCopyNotAvailable unless the copy is available.
+		CreateLoan(ctx context.Context, loan entity.Loan) (entity.Loan, error)
+		GetLoan(ctx context.Context, loanID string) (entity.Loan, error)
+		// RenewLoan moves the due date of the active loan and counts the
+		// renewal. It fails with entity.ErrVersionMismatch when the loan has
+		// been renewed or returned since it was read with renewals renewals.
+		RenewLoan(ctx context.Context, loanID string, renewals int, dueAt time.Time) (entity.Loan, error)
+		// ReturnCopy ends the active loan of the copy and makes the copy
+		// available. It fails with entity.ErrCopyNotOnLoan when there is no
+		// active loan of the copy.
+		ReturnCopy(ctx context.Context, copyID string, returnedAt time.Time) (entity.Loan, error)
+	}
+
+	HoldsRepository interface {
+		// CreateHold fails with entity.ErrHoldExists when the patron already
+		// has an active hold of the book.
+		CreateHold(ctx context.Context, hold entity.Hold) (entity.Hold, error)
+		GetHold(ctx context.Context, holdID string) (entity.Hold, error)
+		// LockHold returns the hold and keeps other transactions from
+		// changing it until the current one ends.
+		LockHold(ctx context.Context, holdID string) (entity.Hold, error)
+		// GetCopyHold returns the ready hold the copy is put aside for, or
+		// fails with entity.ErrHoldNotFound.
+		GetCopyHold(ctx context.Context, copyID string) (entity.Hold, error)
+		// ListHolds returns the holds selected by the filter, oldest first.
+		ListHolds(ctx context.Context, filter entity.HoldFilter) ([]entity.Hold, error)
+		CountWaitingHolds(ctx context.Context, bookID string) (int, error)
+		// AssignCopy puts the available copy aside for the oldest hold
+		// waiting for its book until pickupBy. It fails with
+		// entity.ErrHoldNotFound when no hold waits for the book and with
+		// entity.ErrCopyNotAvailable when the copy is not available.
+		AssignCopy(ctx context.Context, copyID string, pickupBy time.Time) (entity.Hold, error)
+		// CloseHold ends the active hold with the status and makes the copy
+		// put aside for it available. It fails with entity.ErrHoldNotActive
+		// when the hold has been closed already.
+		CloseHold(ctx context.Context, holdID string, status entity.HoldStatus, closedAt time.Time) (entity.Hold, error)
+		// GetExpiredHolds returns up to limit ready holds that were not
+		// picked up before the moment.
+		GetExpiredHolds(ctx context.Context, before time.Time, limit int) ([]entity.Hold, error)
+	}
+
+	FinesRepository interface {
+		// TryLockFines takes the lock of fine accrual until the current
+		// transaction ends and reports whether it was free, so only one
+		// replica accrues fines at a time.
+		TryLockFines(ctx context.Context) (bool, error)
+		// GetOverdueLoans returns up to limit active loans due before the
+		// moment with ids greater than afterID, ordered by id. Loans whose
+		// accrued fines reached maxFine are left out unless it is zero.
+		GetOverdueLoans(
+			ctx context.Context,
+			before time.Time,
+			maxFine int64,
+			afterID string,
+			limit int,
+		) ([]entity.Loan, error)
+		// LockLoanFines keeps other transactions from changing the loan
+		// until the current one ends and returns it with the fines accrued
+		// on it so far.
+		LockLoanFines(ctx context.Context, loanID string) (entity.Loan, int64, error)
+		// LockPatronBalance keeps other transactions from lending to or
+		// charging the patron until the current one ends and returns the
+		// balance of the patron.
+		LockPatronBalance(ctx context.Context, patronID string) (int64, error)
+		GetPatronBalance(ctx context.Context, patronID string) (int64, error)
+		// PostLedgerTransaction appends the debit and the credit entry of
+		// the transaction to the fines ledger.
+		PostLedgerTransaction(
+			ctx context.Context,
+			transaction entity.LedgerTransaction,
+		) (entity.LedgerTransaction, error)
+	}
+
+	// BlobStore keeps binary objects, such as cover images, under slash
+	// separated keys. Blobs are not transactional, a blob written in a
+	// transaction that rolls back has to be deleted.
+	BlobStore interface {
+		PutBlob(ctx context.Context, key string, contentType string, data []byte) error
+		// GetBlob returns the blob with its content type. It fails with
+		// entity.ErrBlobNotFound when there is no blob under the key.
+		GetBlob(ctx context.Context, key string) ([]byte, string, error)
+		// DeleteBlob succeeds when there is no blob under the key.
+		DeleteBlob(ctx context.Context, key string) error
+		// BlobURL returns the URL clients download the blob from.
+		BlobURL(key string) string
+	}
+
+	// VerificationKeys is the key set bearer tokens are verified with.
+	VerificationKeys interface {
+		// GetVerificationKey returns the public key with the id. It fails
+		// with entity.ErrVerificationKeyNotFound when the set has no such
+		// key.
+		GetVerificationKey(ctx context.Context, keyID string) (crypto.PublicKey, error)
+	}
+
+	// CatalogEventRepository persists the catalog change feed. Events must be
+	// appended in the same transaction as the change they describe.
+	CatalogEventRepository interface {
+		AppendCatalogEvent(ctx context.Context, event entity.CatalogEvent) error
+		GetCatalogEvents(ctx context.Context, afterCursor uint64, limit int) ([]entity.CatalogEvent, error)
+		GetLastCatalogCursor(ctx context.Context) (uint64, error)
+		// SubscribeCatalogEvents returns a channel that receives a signal
+		// whenever new events may be available. The channel is closed once
+		// ctx is done.
+		SubscribeCatalogEvents(ctx context.Context) <-chan struct{}
+	}
+
+	// AuditRepository persists the append-only audit log. Records must be
+	// appended in the same transaction as the change they describe.
+	AuditRepository interface {
+		AppendAuditRecord(ctx context.Context, record entity.AuditRecord) error
+		// GetAuditRecords returns up to limit records of the entity with ids
+		// greater than afterID, ordered by id.
+		GetAuditRecords(
+			ctx context.Context,
+			kind entity.AuditEntityKind,
+			entityID string,
+			afterID uint64,
+			limit int,
+		) ([]entity.AuditRecord, error)
+	}
+
+	IdempotencyRepository interface {
+		// ReserveIdempotencyKey stores the pending record and returns true
+		// unless the key belongs to an unexpired record, which is returned
+		// instead. A pending record older than pendingTTL no longer holds
+		// its key, its request is assumed to have died.
+		ReserveIdempotencyKey(
+			ctx context.Context,
+			record entity.IdempotencyRecord,
+			pendingTTL time.Duration,
+		) (entity.IdempotencyRecord, bool, error)
+		// CompleteIdempotencyKey stores the response of the pending record
+		// reserved with the token. It does nothing once the key has been
+		// taken over by another reservation.
+		CompleteIdempotencyKey(ctx context.Context, key, token string, response []byte) error
+		// ReleaseIdempotencyKey deletes the pending record reserved with the
+		// token, so the request can be retried.
+		ReleaseIdempotencyKey(ctx context.Context, key, token string) error
+		// PruneIdempotencyKeys deletes the records that expired before the
+		// moment and returns their number.
+		PruneIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+	}
+
+	APIKeysRepository interface {
+		// CreateAPIKey stores the key and returns it with its id.
+		CreateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+		// GetAPIKey fails with entity.ErrAPIKeyNotFound for an unknown id.
+		GetAPIKey(ctx context.Context, keyID string) (entity.APIKey, error)
+		// ListAPIKeys returns the keys oldest first.
+		ListAPIKeys(ctx context.Context, includeRevoked bool) ([]entity.APIKey, error)
+		// RotateAPIKey replaces the salt, the hash and the expiry of the
+		// key. It fails with entity.ErrAPIKeyRevoked for a revoked key.
+		RotateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+		// RevokeAPIKey revokes the key at the moment unless it already is.
+		RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (entity.APIKey, error)
+		// TouchAPIKey records that the key was used at the moment.
+		TouchAPIKey(ctx context.Context, keyID string, at time.Time) error
+	}
+)
+
+type OutboxKind int
+
+const (
+	OutboxKindUndefined OutboxKind = iota
+	OutboxKindBook
+	OutboxKindAuthor
+	OutboxKindPatron
+	OutboxKindLoan
+	OutboxKindHold
+	OutboxKindAuthorMerge
+)
+
+func (o OutboxKind) String() string {
+	switch o {
+	case OutboxKindBook:
+		return "book"
+	case OutboxKindAuthor:
+		return "author"
+	case OutboxKindPatron:
+		return "patron"
+	case OutboxKindLoan:
+		return "loan"
+	case OutboxKindHold:
+		return "hold"
+	case OutboxKindAuthorMerge:
+		return "author_merge"
+	default:
+		return "undefined"
+	}
+}
+
+type OutboxData struct {
+	IdempotencyKey string
+	Kind           OutboxKind
+	RawData        []byte
+}
+
+type OutboxRepository interface {
+	SendMessage(ctx context.Context, idempotencyKey string, kind OutboxKind, message []byte) error
+	GetMessages(ctx context.Context, batchSize int, inProgressTTL time.Duration) ([]OutboxData, error)
+	MarkAsProcessed(ctx context.Context, idempotencyKeys []string) error
+}
+
+type Transactor interface {
+	WithTx(ctx context.Context, function func(ctx context.Context) error) error
+}
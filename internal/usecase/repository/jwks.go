@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	jwksRefreshInterval = time.Hour
+	// jwksRetryInterval limits how often the key set is fetched, so tokens
+	// with made-up key ids, or any tokens while the issuer is down, do not
+	// flood the issuer.
+	jwksRetryInterval = time.Minute
+	maxJWKSSize       = 1 << 20
+)
+
+var _ VerificationKeys = (*jwks)(nil)
+
+// jwks caches a JSON Web Key Set. The set is fetched again every hour and
+// when a token names an unknown key, so rotated keys are picked up.
+type jwks struct {
+	fetch     func(ctx context.Context) ([]byte, error)
+	refreshes *singleflight.Group
+
+	mx          *sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// NewFileJWKS reads the key set from a file.
+func NewFileJWKS(path string) *jwks {
+	return newJWKS(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
+
+// NewURLJWKS downloads the key set from the URL, usually the jwks_uri of
+// the issuer.
+func NewURLJWKS(client *http.Client, url string) *jwks {
+	return newJWKS(func(ctx context.Context) ([]byte, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := client.Do(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("can not download key set: %s", response.Status)
+		}
+
+		return io.ReadAll(io.LimitReader(response.Body, maxJWKSSize))
+	})
+}
+
+func newJWKS(fetch func(ctx context.Context) ([]byte, error)) *jwks {
+	return &jwks{
+		fetch:     fetch,
+		refreshes: new(singleflight.Group),
+		mx:        new(sync.Mutex),
+	}
+}
+
+// GetVerificationKey matches an empty key id with the only key of a set.
+func (j *jwks) GetVerificationKey(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	key, ok, refresh := j.cached(keyID)
+
+	switch {
+	case !refresh && ok:
+		return key, nil
+	case !refresh:
+		return nil, entity.ErrVerificationKeyNotFound
+	}
+
+	// Concurrent requests wait for one fetch, which is shared and so must
+	// not fail when the request that started it goes away.
+	_, err, _ := j.refreshes.Do("", func() (any, error) {
+		return nil, j.refresh(context.WithoutCancel(ctx))
+	})
+
+	if err != nil {
+		// The cached key stays valid while the issuer is unavailable.
+		if ok {
+			return key, nil
+		}
+
+		return nil, err
+	}
+
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	if key, ok = j.lookup(keyID); !ok {
+		return nil, entity.ErrVerificationKeyNotFound
+	}
+
+	return key, nil
+}
+
+// cached returns the cached key and whether the set is due to be fetched,
+// because it is old or lacks the key, and was not tried recently.
+func (j *jwks) cached(keyID string) (crypto.PublicKey, bool, bool) {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	key, ok := j.lookup(keyID)
+	due := !ok || time.Since(j.fetchedAt) >= jwksRefreshInterval
+
+	return key, ok, due && time.Since(j.attemptedAt) >= jwksRetryInterval
+}
+
+// lookup must be called with mx held.
+func (j *jwks) lookup(keyID string) (crypto.PublicKey, bool) {
+	if keyID == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+
+	key, ok := j.keys[keyID]
+
+	return key, ok
+}
+
+// refresh fetches the key set without holding mx, so cached keys are
+// served meanwhile.
+func (j *jwks) refresh(ctx context.Context) error {
+	var keys map[string]crypto.PublicKey
+
+	data, err := j.fetch(ctx)
+
+	if err == nil {
+		keys, err = ParseJWKS(data)
+	}
+
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	j.attemptedAt = time.Now()
+
+	if err != nil {
+		return err
+	}
+
+	j.keys, j.fetchedAt = keys, j.attemptedAt
+
+	return nil
+}
+
+// jsonWebKey holds the members of RSA, EC and OKP keys, RFC 7517 and 8037.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// ParseJWKS returns the signature verification keys of a JSON Web Key Set
+// by their ids. Encryption keys and keys of unsupported types are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("malformed key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, webKey := range set.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		key, err := webKey.publicKey()
+
+		if errors.Is(err, errUnsupportedKey) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("malformed key %q: %w", webKey.KeyID, err)
+		}
+
+		keys[webKey.KeyID] = key
+	}
+
+	return keys, nil
+}
+
+var errUnsupportedKey = errors.New("unsupported key type")
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.KeyType == "RSA":
+		return k.rsaKey()
+	case k.KeyType == "EC":
+		return k.ecdsaKey()
+	case k.KeyType == "OKP" && k.Curve == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errUnsupportedKey
+	}
+}
+
+func (k jsonWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeInt(k.N)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := decodeInt(k.E)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jsonWebKey) ecdsaKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+
+	switch k.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, errUnsupportedKey
+	}
+
+	x, err := decodeInt(k.X)
+
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := decodeInt(k.Y)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+	// The conversion checks that the point is on the curve.
+	if _, err = key.ECDH(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// decodeInt decodes a base64url encoded big-endian unsigned integer.
+func decodeInt(encoded string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid integer")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// ed25519Set returns a new key and the key set holding it with id "ed".
+func ed25519Set(t *testing.T) (ed25519.PublicKey, []byte) {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return public, []byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"ed","x":"` +
+		base64.RawURLEncoding.EncodeToString(public) + `"}]}`)
+}
+
+func TestJWKSIssuerDown(t *testing.T) {
+	t.Parallel()
+
+	public, set := ed25519Set(t)
+
+	var (
+		fetches atomic.Int32
+		down    atomic.Bool
+	)
+
+	keys := newJWKS(func(context.Context) ([]byte, error) {
+		fetches.Add(1)
+
+		if down.Load() {
+			return nil, errors.New("issuer is down")
+		}
+
+		return set, nil
+	})
+
+	ctx := context.Background()
+
+	key, err := keys.GetVerificationKey(ctx, "ed")
+	require.NoError(t, err)
+	require.Equal(t, public, key)
+
+	// The cached set gets stale while the issuer is down.
+	down.Store(true)
+	keys.mx.Lock()
+	keys.fetchedAt = time.Now().Add(-2 * jwksRefreshInterval)
+	keys.attemptedAt = keys.fetchedAt
+	keys.mx.Unlock()
+
+	for range 3 {
+		key, err = keys.GetVerificationKey(ctx, "ed")
+		require.NoError(t, err)
+		require.Equal(t, public, key)
+	}
+
+	// The stale key is served without fetching the set on every request.
+	require.Equal(t, int32(2), fetches.Load())
+
+	_, err = keys.GetVerificationKey(ctx, "unknown")
+	require.ErrorIs(t, err, entity.ErrVerificationKeyNotFound)
+	require.Equal(t, int32(2), fetches.Load())
+}
+
+func TestJWKSSharedFetch(t *testing.T) {
+	t.Parallel()
+
+	_, set := ed25519Set(t)
+
+	var fetches atomic.Int32
+
+	release := make(chan struct{})
+	keys := newJWKS(func(context.Context) ([]byte, error) {
+		fetches.Add(1)
+		<-release
+
+		return set, nil
+	})
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 8)
+
+	for range cap(errs) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, getErr := keys.GetVerificationKey(context.Background(), "ed")
+			errs <- getErr
+		}()
+	}
+
+	// The fetch runs without the lock, so the cache is still readable.
+	require.Eventually(t, func() bool { return fetches.Load() == 1 }, time.Second, time.Millisecond)
+	keys.mx.Lock()
+	require.Empty(t, keys.keys)
+	keys.mx.Unlock()
+
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for getErr := range errs {
+		require.NoError(t, getErr)
+	}
+
+	require.Equal(t, int32(1), fetches.Load())
+}
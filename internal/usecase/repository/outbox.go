@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ OutboxRepository = (*outboxRepository)(nil)
+
+type outboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutbox(db *pgxpool.Pool) *outboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (o *outboxRepository) SendMessage(
+	ctx context.Context,
+	idempotencyKey string,
+	kind OutboxKind,
+	message []byte,
+) error {
+	const query = `
+INSERT INTO outbox (idempotency_key, data, status, kind)
+VALUES ($1, $2, 'CREATED', $3)
+ON CONFLICT (idempotency_key) DO NOTHING`
+
+	_, err := getQuerier(ctx, o.db).Exec(ctx, query, idempotencyKey, message, kind)
+
+	return err
+}
+
+func (o *outboxRepository) GetMessages(
+	ctx context.Context,
+	batchSize int,
+	inProgressTTL time.Duration,
+) ([]OutboxData, error) {
+	const query = `
+UPDATE outbox
+SET status     = 'IN_PROGRESS',
+    updated_at = now()
+WHERE idempotency_key IN (SELECT idempotency_key
+                          FROM outbox
+                          WHERE status = 'CREATED'
+                             OR (status = 'IN_PROGRESS' AND updated_at < now() - $2::interval)
+                          ORDER BY created_at
+                          LIMIT $1 FOR UPDATE SKIP LOCKED)
+RETURNING idempotency_key, data, kind`
+
+	rows, err := getQuerier(ctx, o.db).Query(ctx, query, batchSize, inProgressTTL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (OutboxData, error) {
+		var data OutboxData
+		err := row.Scan(&data.IdempotencyKey, &data.RawData, &data.Kind)
+
+		return data, err
+	})
+}
+
+func (o *outboxRepository) MarkAsProcessed(ctx context.Context, idempotencyKeys []string) error {
+	if len(idempotencyKeys) == 0 {
+		return nil
+	}
+
+	const query = `
+UPDATE outbox
+SET status     = 'SUCCESS',
+    updated_at = now()
+WHERE idempotency_key = ANY ($1)`
+
+	_, err := getQuerier(ctx, o.db).Exec(ctx, query, idempotencyKeys)
+
+	return err
+}
@@ -0,0 +1,933 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/project/library/internal/entity"
+)
+
+var _ AuthorRepository = (*postgresRepository)(nil)
+var _ BooksRepository = (*postgresRepository)(nil)
+var _ CatalogEventRepository = (*postgresRepository)(nil)
+var _ AuditRepository = (*postgresRepository)(nil)
+
+const (
+	foreignKeyViolationCode = "23503"
+	uniqueViolationCode     = "23505"
+	checkViolationCode      = "23514"
+
+	catalogEventChannel = "catalog_event"
+	// catalogEventLockKey serializes catalog event writers, so event ids are
+	// assigned in commit order and a reader never skips over an id that is
+	// committed later.
+	catalogEventLockKey = 7_263_540_001
+	// finesLockKey is held by the replica accruing fines.
+	finesLockKey = 7_263_540_002
+	// subjectTreeLockKey serializes moves of subjects, so concurrent moves
+	// can not make a cycle.
+	subjectTreeLockKey = 7_263_540_003
+	// bookTitleLockKey seeds the hashes of the titles locked by
+	// LockBookTitle.
+	bookTitleLockKey = 7_263_540_004
+	// holdQueueLockKey seeds the hashes of the books whose hold queue is
+	// assigned a copy by AssignCopy.
+	holdQueueLockKey = 7_263_540_005
+)
+
+type postgresRepository struct {
+	db                 *pgxpool.Pool
+	catalogBroadcaster *broadcaster
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *postgresRepository {
+	return &postgresRepository{
+		db:                 db,
+		catalogBroadcaster: newBroadcaster(),
+	}
+}
+
+// authorColumns are scanned by scanAuthor from an author row aliased as a.
+// Missing values are coalesced, so history snapshots taken before a column
+// was added stay readable.
+const authorColumns = `a.id,
+       a.name,
+       a.version,
+       coalesce(a.biography, ''),
+       coalesce(to_char(a.birth_date, 'YYYY-MM-DD'), ''),
+       coalesce(to_char(a.death_date, 'YYYY-MM-DD'), ''),
+       coalesce(a.country, ''),
+       coalesce(a.aliases, '{}'),
+       coalesce(a.viaf, ''),
+       coalesce(a.isni, ''),
+       coalesce(a.wikidata, '')`
+
+func (p *postgresRepository) CreateAuthor(ctx context.Context, author entity.Author) (entity.Author, error) {
+	const query = `
+INSERT INTO author AS a (name, biography, birth_date, death_date, country, aliases, viaf, isni, wikidata)
+VALUES ($1, $2, nullif($3, '')::date, nullif($4, '')::date, $5, coalesce($6, '{}'::text[]),
+        nullif($7, ''), nullif($8, ''), nullif($9, ''))
+RETURNING ` + authorColumns
+
+	created, err := scanAuthor(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		author.Name,
+		author.Biography,
+		author.BirthDate,
+		author.DeathDate,
+		author.Country,
+		author.Aliases,
+		author.ExternalIDs.VIAF,
+		author.ExternalIDs.ISNI,
+		author.ExternalIDs.Wikidata,
+	))
+
+	if err != nil {
+		return entity.Author{}, authorWriteError(err)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetAuthor(ctx context.Context, authorID string) (entity.Author, error) {
+	const query = `SELECT ` + authorColumns + ` FROM author a WHERE a.id = $1`
+
+	author, err := scanAuthor(getQuerier(ctx, p.db).QueryRow(ctx, query, authorID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) UpdateAuthor(
+	ctx context.Context,
+	authorID string,
+	patch entity.AuthorPatch,
+	expectedVersion *uint64,
+) (entity.Author, error) {
+	const query = `
+UPDATE author AS a
+SET name       = coalesce($2, a.name),
+    biography  = coalesce($3, a.biography),
+    birth_date = CASE WHEN $4::text IS NULL THEN a.birth_date ELSE nullif($4, '')::date END,
+    death_date = CASE WHEN $5::text IS NULL THEN a.death_date ELSE nullif($5, '')::date END,
+    country    = coalesce($6, a.country),
+    aliases    = coalesce($7, a.aliases),
+    viaf       = CASE WHEN $8 THEN nullif($9, '') ELSE a.viaf END,
+    isni       = CASE WHEN $8 THEN nullif($10, '') ELSE a.isni END,
+    wikidata   = CASE WHEN $8 THEN nullif($11, '') ELSE a.wikidata END,
+    version    = a.version + 1
+WHERE a.id = $1
+  AND ($12::bigint IS NULL OR a.version = $12)
+RETURNING ` + authorColumns
+
+	var externalIDs entity.ExternalIDs
+
+	if patch.ExternalIDs != nil {
+		externalIDs = *patch.ExternalIDs
+	}
+
+	q := getQuerier(ctx, p.db)
+	author, err := scanAuthor(q.QueryRow(ctx, query,
+		authorID,
+		patch.Name,
+		patch.Biography,
+		patch.BirthDate,
+		patch.DeathDate,
+		patch.Country,
+		patch.Aliases,
+		patch.ExternalIDs != nil,
+		externalIDs.VIAF,
+		externalIDs.ISNI,
+		externalIDs.Wikidata,
+		expectedVersion,
+	))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		const existsQuery = `SELECT EXISTS (SELECT 1 FROM author WHERE id = $1)`
+		return entity.Author{}, updateMissError(ctx, q, existsQuery, authorID, entity.ErrAuthorNotFound)
+	}
+
+	if err != nil {
+		return entity.Author{}, authorWriteError(err)
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) GetAuthorByExternalID(
+	ctx context.Context,
+	scheme entity.ExternalIDScheme,
+	value string,
+) (entity.Author, error) {
+	var column string
+
+	switch scheme {
+	case entity.ExternalIDSchemeVIAF:
+		column = "viaf"
+	case entity.ExternalIDSchemeISNI:
+		column = "isni"
+	case entity.ExternalIDSchemeWikidata:
+		column = "wikidata"
+	default:
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	query := `SELECT ` + authorColumns + ` FROM author a WHERE a.` + column + ` = $1`
+	author, err := scanAuthor(getQuerier(ctx, p.db).QueryRow(ctx, query, value))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func scanAuthor(row pgx.Row) (entity.Author, error) {
+	var author entity.Author
+	err := row.Scan(authorDestinations(&author)...)
+
+	return author, err
+}
+
+// authorDestinations returns the scan destinations of authorColumns.
+func authorDestinations(author *entity.Author) []any {
+	return []any{
+		&author.ID,
+		&author.Name,
+		&author.Version,
+		&author.Biography,
+		&author.BirthDate,
+		&author.DeathDate,
+		&author.Country,
+		&author.Aliases,
+		&author.ExternalIDs.VIAF,
+		&author.ExternalIDs.ISNI,
+		&author.ExternalIDs.Wikidata,
+	}
+}
+
+// authorWriteError maps the constraint violations of the author table to
+// entity errors.
+func authorWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolationCode:
+		return entity.ErrExternalIDTaken
+	case checkViolationCode:
+		return entity.ErrInvalidLifeDates
+	default:
+		return err
+	}
+}
+
+func (p *postgresRepository) GetAuthorBooks(
+	ctx context.Context,
+	authorID string,
+	role entity.ContributorRole,
+) ([]entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, ` + bookCredits + `
+FROM book b
+         JOIN author_book ab ON ab.book_id = b.id
+WHERE b.id IN (SELECT book_id FROM author_book WHERE author_id = $1 AND ($2::text = '' OR role::text = $2))
+GROUP BY b.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, authorID, string(role))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanBook)
+}
+
+func (p *postgresRepository) GetAuthorAsOf(
+	ctx context.Context,
+	authorID string,
+	asOf time.Time,
+) (entity.Author, error) {
+	const query = `
+SELECT ` + authorColumns + `
+FROM author_history h,
+     jsonb_populate_record(NULL::author, h.data) a
+WHERE h.id = $1
+  AND h.valid_from <= $2
+  AND $2 < h.valid_to`
+
+	author, err := scanAuthor(getQuerier(ctx, p.db).QueryRow(ctx, query, authorID, asOf))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) GetAuthorBooksAsOf(
+	ctx context.Context,
+	authorID string,
+	role entity.ContributorRole,
+	asOf time.Time,
+) ([]entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, h.author_ids, h.contributors
+FROM book_history h,
+     jsonb_populate_record(NULL::book, h.data) b
+WHERE h.author_ids @> ARRAY [$1::uuid]
+  AND ($2::text = '' OR h.contributors @> jsonb_build_array(jsonb_build_object('author_id', $1::uuid, 'role', $2::text)))
+  AND h.valid_from <= $3
+  AND $3 < h.valid_to`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, authorID, string(role), asOf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanBook)
+}
+
+func (p *postgresRepository) CreateBook(ctx context.Context, book entity.Book) (entity.Book, error) {
+	var created entity.Book
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		const queryBook = `
+INSERT INTO book AS b (name, isbn, publisher, publication_year, language, page_count, description, subject_ids, tags,
+                       work_id, series_id, series_position, normalized_name)
+VALUES ($1, nullif($2, ''), $3, $4, $5, $6, $7, coalesce($8::uuid[], '{}'), coalesce($9::text[], '{}'),
+        nullif($10, '')::uuid, nullif($11, '')::uuid, $12, $13)
+RETURNING ` + bookColumns + `, '{}'::uuid[], '[]'::jsonb`
+
+		if err := lockSubjects(ctx, tx, book.SubjectIDs); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, queryBook,
+			book.Name,
+			book.ISBN,
+			book.Publisher,
+			book.PublicationYear,
+			book.Language,
+			book.PageCount,
+			book.Description,
+			book.SubjectIDs,
+			book.Tags,
+			book.WorkID,
+			book.SeriesID,
+			book.SeriesPosition,
+			entity.NormalizeTitle(book.Name),
+		)
+
+		if err != nil {
+			return err
+		}
+
+		if created, err = pgx.CollectExactlyOneRow(rows, scanBook); err != nil {
+			return bookWriteError(err)
+		}
+
+		created.AuthorIDs = entity.ContributorAuthorIDs(book.Contributors)
+		created.Contributors = book.Contributors
+
+		return linkBookContributors(ctx, tx, created.ID, book.Contributors)
+	})
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetBook(ctx context.Context, bookID string) (entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, ` + bookCredits + `
+FROM book b
+         LEFT JOIN author_book ab ON ab.book_id = b.id
+WHERE b.id = $1
+GROUP BY b.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, bookID)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	book, err := pgx.CollectExactlyOneRow(rows, scanBook)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+func (p *postgresRepository) LockBook(ctx context.Context, bookID string) (entity.Book, error) {
+	const query = `SELECT 1 FROM book WHERE id = $1 FOR UPDATE`
+
+	var locked int
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, bookID).Scan(&locked)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return p.GetBook(ctx, bookID)
+}
+
+func (p *postgresRepository) GetBookByISBN(ctx context.Context, isbn string) (entity.Book, error) {
+	const query = `SELECT id FROM book WHERE isbn = $1`
+
+	var bookID string
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, isbn).Scan(&bookID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return p.GetBook(ctx, bookID)
+}
+
+func (p *postgresRepository) GetBookByTitle(ctx context.Context, title string, authorIDs []string) (entity.Book, error) {
+	const query = `
+SELECT b.id
+FROM book b
+WHERE b.normalized_name = $1
+  AND (SELECT array_agg(DISTINCT ab.author_id ORDER BY ab.author_id)
+       FROM author_book ab
+       WHERE ab.book_id = b.id) = (SELECT array_agg(DISTINCT a ORDER BY a) FROM unnest($2::uuid[]) a)
+LIMIT 1`
+
+	var bookID string
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, title, authorIDs).Scan(&bookID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return p.GetBook(ctx, bookID)
+}
+
+func (p *postgresRepository) LockBookTitle(ctx context.Context, title string) error {
+	const query = `SELECT pg_advisory_xact_lock(hashtextextended($1, $2))`
+
+	_, err := getQuerier(ctx, p.db).Exec(ctx, query, title, bookTitleLockKey)
+
+	return err
+}
+
+func (p *postgresRepository) UpdateBook(
+	ctx context.Context,
+	bookID string,
+	patch entity.BookPatch,
+	expectedVersion *uint64,
+) (entity.Book, error) {
+	var book entity.Book
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		const queryBook = `
+UPDATE book AS b
+SET name             = coalesce($2, b.name),
+    isbn             = CASE WHEN $3::text IS NULL THEN b.isbn ELSE nullif($3, '') END,
+    publisher        = coalesce($4, b.publisher),
+    publication_year = coalesce($5, b.publication_year),
+    language         = coalesce($6, b.language),
+    page_count       = coalesce($7, b.page_count),
+    description      = coalesce($8, b.description),
+    subject_ids      = coalesce($10::uuid[], b.subject_ids),
+    tags             = coalesce($11::text[], b.tags),
+    work_id          = CASE WHEN $12::text IS NULL THEN b.work_id ELSE nullif($12, '')::uuid END,
+    series_id        = CASE WHEN $13::text IS NULL THEN b.series_id ELSE nullif($13, '')::uuid END,
+    series_position  = coalesce($14, CASE WHEN $13 = '' THEN 0 ELSE b.series_position END),
+    cover            = coalesce($15::jsonb, b.cover),
+    normalized_name  = coalesce($16, b.normalized_name),
+    version          = b.version + 1
+WHERE b.id = $1
+  AND ($9::bigint IS NULL OR b.version = $9)
+RETURNING ` + bookColumns + `, '{}'::uuid[], '[]'::jsonb`
+
+		if patch.SubjectIDs != nil {
+			if err := lockSubjects(ctx, tx, *patch.SubjectIDs); err != nil {
+				return err
+			}
+		}
+
+		var normalizedName *string
+
+		if patch.Name != nil {
+			normalizedName = new(string)
+			*normalizedName = entity.NormalizeTitle(*patch.Name)
+		}
+
+		rows, err := tx.Query(ctx, queryBook,
+			bookID,
+			patch.Name,
+			patch.ISBN,
+			patch.Publisher,
+			patch.PublicationYear,
+			patch.Language,
+			patch.PageCount,
+			patch.Description,
+			expectedVersion,
+			patch.SubjectIDs,
+			patch.Tags,
+			patch.WorkID,
+			patch.SeriesID,
+			patch.SeriesPosition,
+			patch.Cover,
+			normalizedName,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		book, err = pgx.CollectExactlyOneRow(rows, scanBook)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			const existsQuery = `SELECT EXISTS (SELECT 1 FROM book WHERE id = $1)`
+			return updateMissError(ctx, tx, existsQuery, bookID, entity.ErrBookNotFound)
+		}
+
+		if err != nil {
+			return bookWriteError(err)
+		}
+
+		if err = patchBookContributors(ctx, tx, bookID, patch); err != nil {
+			return err
+		}
+
+		const queryCredits = `SELECT ` + bookCredits + ` FROM author_book ab WHERE ab.book_id = $1`
+
+		return tx.QueryRow(ctx, queryCredits, bookID).Scan(&book.AuthorIDs, &book.Contributors)
+	})
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+// patchBookContributors changes the credits of a book whose row is already
+// locked by the update, so concurrent patches of the same book do not
+// interleave.
+func patchBookContributors(ctx context.Context, tx pgx.Tx, bookID string, patch entity.BookPatch) error {
+	if patch.Contributors != nil {
+		const queryUnlink = `DELETE FROM author_book WHERE book_id = $1`
+
+		if _, err := tx.Exec(ctx, queryUnlink, bookID); err != nil {
+			return err
+		}
+
+		return linkBookContributors(ctx, tx, bookID, *patch.Contributors)
+	}
+
+	if len(patch.RemoveAuthorIDs) == 0 && len(patch.AddAuthorIDs) == 0 {
+		return nil
+	}
+
+	const queryUnlink = `DELETE FROM author_book WHERE book_id = $1 AND author_id = ANY ($2::uuid[])`
+
+	if _, err := tx.Exec(ctx, queryUnlink, bookID, patch.RemoveAuthorIDs); err != nil {
+		return err
+	}
+
+	const queryLink = `
+INSERT INTO author_book (author_id, book_id, role, position)
+SELECT a.author_id,
+       $1,
+       'author',
+       a.ordinality + coalesce((SELECT max(position) FROM author_book WHERE book_id = $1), -1)
+FROM unnest($2::uuid[]) WITH ORDINALITY a(author_id, ordinality)
+ON CONFLICT DO NOTHING`
+
+	if _, err := tx.Exec(ctx, queryLink, bookID, patch.AddAuthorIDs); err != nil {
+		return linkError(err)
+	}
+
+	// Close the gaps left by removed and already credited authors.
+	const queryRenumber = `
+UPDATE author_book ab
+SET position = o.position
+FROM (SELECT author_id, role, row_number() OVER (ORDER BY position, author_id, role) - 1 AS position
+      FROM author_book
+      WHERE book_id = $1) o
+WHERE ab.book_id = $1
+  AND ab.author_id = o.author_id
+  AND ab.role = o.role
+  AND ab.position <> o.position`
+
+	_, err := tx.Exec(ctx, queryRenumber, bookID)
+
+	return err
+}
+
+func (p *postgresRepository) GetBookAsOf(ctx context.Context, bookID string, asOf time.Time) (entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, h.author_ids, h.contributors
+FROM book_history h,
+     jsonb_populate_record(NULL::book, h.data) b
+WHERE h.id = $1
+  AND h.valid_from <= $2
+  AND $2 < h.valid_to`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, bookID, asOf)
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	book, err := pgx.CollectExactlyOneRow(rows, scanBook)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Book{}, entity.ErrBookNotFound
+	}
+
+	if err != nil {
+		return entity.Book{}, err
+	}
+
+	return book, nil
+}
+
+// PruneHistory drops the versions of authors and books that stopped being
+// current before the given moment.
+func (p *postgresRepository) PruneHistory(ctx context.Context, before time.Time) (int64, error) {
+	var pruned int64
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		for _, query := range []string{
+			`DELETE FROM author_history WHERE valid_to < $1`,
+			`DELETE FROM book_history WHERE valid_to < $1`,
+		} {
+			tag, err := tx.Exec(ctx, query, before)
+
+			if err != nil {
+				return err
+			}
+
+			pruned += tag.RowsAffected()
+		}
+
+		return nil
+	})
+
+	return pruned, err
+}
+
+// linkBookContributors credits the contributors, which are expected to be
+// compacted, on the book.
+func linkBookContributors(ctx context.Context, tx pgx.Tx, bookID string, contributors []entity.Contributor) error {
+	if len(contributors) == 0 {
+		return nil
+	}
+
+	authorIDs := make([]string, 0, len(contributors))
+	roles := make([]string, 0, len(contributors))
+	positions := make([]int, 0, len(contributors))
+
+	for _, contributor := range contributors {
+		authorIDs = append(authorIDs, contributor.AuthorID)
+		roles = append(roles, string(contributor.Role))
+		positions = append(positions, contributor.Position)
+	}
+
+	const query = `
+INSERT INTO author_book (author_id, book_id, role, position)
+SELECT c.author_id, $1, c.role::contributor_role, c.position
+FROM unnest($2::uuid[], $3::text[], $4::int[]) c(author_id, role, position)`
+
+	_, err := tx.Exec(ctx, query, bookID, authorIDs, roles, positions)
+
+	return linkError(err)
+}
+
+// linkError maps a link to a missing author to entity.ErrAuthorNotFound.
+func linkError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return entity.ErrAuthorNotFound
+	}
+
+	return err
+}
+
+// bookColumns are scanned by scanBook from a book row aliased as b, followed
+// by the author ids and the contributors. Missing values are coalesced, so history snapshots
+// taken before a column was added stay readable.
+const bookColumns = `b.id,
+       b.name,
+       b.created_at,
+       b.updated_at,
+       b.version,
+       coalesce(b.isbn, ''),
+       coalesce(b.publisher, ''),
+       coalesce(b.publication_year, 0),
+       coalesce(b.language, ''),
+       coalesce(b.page_count, 0),
+       coalesce(b.description, ''),
+       coalesce(b.subject_ids, '{}'),
+       coalesce(b.tags, '{}'),
+       coalesce(b.work_id::text, ''),
+       coalesce(b.series_id::text, ''),
+       coalesce(b.series_position, 0),
+       coalesce(b.cover, '{}')`
+
+// bookCredits aggregates the author_book rows aliased as ab into the author
+// ids and the contributors of a book.
+const bookCredits = `coalesce(array_agg(DISTINCT ab.author_id ORDER BY ab.author_id)
+                FILTER (WHERE ab.author_id IS NOT NULL), '{}'),
+       coalesce(jsonb_agg(jsonb_build_object('author_id', ab.author_id, 'role', ab.role, 'position', ab.position)
+                          ORDER BY ab.position) FILTER (WHERE ab.author_id IS NOT NULL), '[]')`
+
+func scanBook(row pgx.CollectableRow) (entity.Book, error) {
+	var book entity.Book
+	err := row.Scan(
+		&book.ID,
+		&book.Name,
+		&book.CreatedAt,
+		&book.UpdatedAt,
+		&book.Version,
+		&book.ISBN,
+		&book.Publisher,
+		&book.PublicationYear,
+		&book.Language,
+		&book.PageCount,
+		&book.Description,
+		&book.SubjectIDs,
+		&book.Tags,
+		&book.WorkID,
+		&book.SeriesID,
+		&book.SeriesPosition,
+		&book.Cover,
+		&book.AuthorIDs,
+		&book.Contributors,
+	)
+
+	return book, err
+}
+
+// bookWriteError maps the constraint violations of the book table to entity
+// errors.
+func bookWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == uniqueViolationCode:
+		return entity.ErrISBNTaken
+	case pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "book_work_id_fkey":
+		return entity.ErrWorkNotFound
+	case pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "book_series_id_fkey":
+		return entity.ErrSeriesNotFound
+	case pgErr.Code == checkViolationCode && pgErr.ConstraintName == "book_series_position_check":
+		return entity.ErrInvalidSeriesPosition
+	default:
+		return err
+	}
+}
+
+// updateMissError tells why a conditional update matched no rows: either
+// the row does not exist or its version has changed.
+func updateMissError(ctx context.Context, q querier, existsQuery string, id string, notFound error) error {
+	var exists bool
+
+	if err := q.QueryRow(ctx, existsQuery, id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return notFound
+	}
+
+	return entity.ErrVersionMismatch
+}
+
+type catalogEventData struct {
+	Book   *entity.Book   `json:"book,omitempty"`
+	Author *entity.Author `json:"author,omitempty"`
+}
+
+func (p *postgresRepository) AppendCatalogEvent(ctx context.Context, event entity.CatalogEvent) error {
+	data, err := json.Marshal(catalogEventData{
+		Book:   event.Book,
+		Author: event.Author,
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not serialize catalog event: %w", err)
+	}
+
+	return runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, catalogEventLockKey); err != nil {
+			return err
+		}
+
+		const query = `INSERT INTO catalog_event (kind, data, author_ids) VALUES ($1, $2, coalesce($3, '{}'))`
+
+		_, err := tx.Exec(ctx, query, event.Kind, data, event.AuthorIDs)
+
+		return err
+	})
+}
+
+func (p *postgresRepository) GetCatalogEvents(
+	ctx context.Context,
+	afterCursor uint64,
+	limit int,
+) ([]entity.CatalogEvent, error) {
+	const query = `
+SELECT id, kind, data, author_ids, created_at
+FROM catalog_event
+WHERE id > $1
+ORDER BY id
+LIMIT $2`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, afterCursor, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.CatalogEvent, error) {
+		var (
+			event entity.CatalogEvent
+			data  catalogEventData
+		)
+
+		err := row.Scan(&event.Cursor, &event.Kind, &data, &event.AuthorIDs, &event.OccurredAt)
+		event.Book, event.Author = data.Book, data.Author
+
+		return event, err
+	})
+}
+
+func (p *postgresRepository) GetLastCatalogCursor(ctx context.Context) (uint64, error) {
+	const query = `SELECT coalesce(max(id), 0) FROM catalog_event`
+
+	var cursor uint64
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query).Scan(&cursor)
+
+	return cursor, err
+}
+
+func (p *postgresRepository) SubscribeCatalogEvents(ctx context.Context) <-chan struct{} {
+	return p.catalogBroadcaster.subscribe(ctx)
+}
+
+// ListenCatalogEvents forwards Postgres notifications about new catalog
+// events to the subscribers of this replica. It blocks until ctx is done or
+// the listening connection fails.
+func (p *postgresRepository) ListenCatalogEvents(ctx context.Context) error {
+	pooled, err := p.db.Acquire(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// The listening connection never goes back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err = conn.Exec(ctx, "LISTEN "+catalogEventChannel); err != nil {
+		return err
+	}
+
+	// Events written while the replica was not listening are picked up too.
+	p.catalogBroadcaster.notify()
+
+	for {
+		if _, err = conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+
+		p.catalogBroadcaster.notify()
+	}
+}
+
+func (p *postgresRepository) AppendAuditRecord(ctx context.Context, record entity.AuditRecord) error {
+	const query = `
+INSERT INTO audit_log (actor, operation, entity_kind, entity_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := getQuerier(ctx, p.db).Exec(ctx, query,
+		record.Actor, record.Operation, record.EntityKind, record.EntityID, record.Before, record.After)
+
+	return err
+}
+
+func (p *postgresRepository) GetAuditRecords(
+	ctx context.Context,
+	kind entity.AuditEntityKind,
+	entityID string,
+	afterID uint64,
+	limit int,
+) ([]entity.AuditRecord, error) {
+	const query = `
+SELECT id, actor, operation, entity_kind, entity_id, before, after, created_at
+FROM audit_log
+WHERE entity_kind = $1
+  AND entity_id = $2
+  AND id > $3
+ORDER BY id
+LIMIT $4`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, kind, entityID, afterID, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.AuditRecord, error) {
+		var record entity.AuditRecord
+		err := row.Scan(&record.ID, &record.Actor, &record.Operation, &record.EntityKind, &record.EntityID,
+			&record.Before, &record.After, &record.CreatedAt)
+
+		return record, err
+	})
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project/library/internal/entity"
+)
+
+var _ APIKeysRepository = (*postgresRepository)(nil)
+
+const apiKeyColumns = `k.id, k.name, k.scopes, k.salt, k.hash, k.created_at, k.expires_at, k.last_used_at, k.revoked_at`
+
+func scanAPIKey(row pgx.Row) (entity.APIKey, error) {
+	var key entity.APIKey
+
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Scopes,
+		&key.Salt,
+		&key.Hash,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+
+	return key, err
+}
+
+func (p *postgresRepository) CreateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	const query = `
+INSERT INTO api_key AS k (name, scopes, salt, hash, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + apiKeyColumns
+
+	return scanAPIKey(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		key.Name, key.Scopes, key.Salt, key.Hash, key.CreatedAt.UTC(), key.ExpiresAt.UTC()))
+}
+
+func (p *postgresRepository) GetAPIKey(ctx context.Context, keyID string) (entity.APIKey, error) {
+	const query = `SELECT ` + apiKeyColumns + ` FROM api_key k WHERE k.id = $1`
+
+	key, err := scanAPIKey(getQuerier(ctx, p.db).QueryRow(ctx, query, keyID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.APIKey{}, entity.ErrAPIKeyNotFound
+	}
+
+	return key, err
+}
+
+func (p *postgresRepository) ListAPIKeys(ctx context.Context, includeRevoked bool) ([]entity.APIKey, error) {
+	const query = `
+SELECT ` + apiKeyColumns + `
+FROM api_key k
+WHERE $1 OR k.revoked_at IS NULL
+ORDER BY k.created_at, k.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, includeRevoked)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.APIKey, error) {
+		return scanAPIKey(row)
+	})
+}
+
+func (p *postgresRepository) RotateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	const query = `
+UPDATE api_key AS k
+SET salt = $2, hash = $3, expires_at = $4
+WHERE k.id = $1 AND k.revoked_at IS NULL
+RETURNING ` + apiKeyColumns
+
+	rotated, err := scanAPIKey(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		key.ID, key.Salt, key.Hash, key.ExpiresAt.UTC()))
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return rotated, err
+	}
+
+	// Tell a revoked key from a missing one.
+	if _, err = p.GetAPIKey(ctx, key.ID); err != nil {
+		return entity.APIKey{}, err
+	}
+
+	return entity.APIKey{}, entity.ErrAPIKeyRevoked
+}
+
+func (p *postgresRepository) RevokeAPIKey(ctx context.Context, keyID string, at time.Time) (entity.APIKey, error) {
+	const query = `
+UPDATE api_key AS k
+SET revoked_at = coalesce(k.revoked_at, $2)
+WHERE k.id = $1
+RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(getQuerier(ctx, p.db).QueryRow(ctx, query, keyID, at.UTC()))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.APIKey{}, entity.ErrAPIKeyNotFound
+	}
+
+	return key, err
+}
+
+func (p *postgresRepository) TouchAPIKey(ctx context.Context, keyID string, at time.Time) error {
+	const query = `UPDATE api_key SET last_used_at = greatest(last_used_at, $2) WHERE id = $1`
+
+	_, err := getQuerier(ctx, p.db).Exec(ctx, query, keyID, at.UTC())
+
+	return err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project/library/internal/entity"
+)
+
+// duplicateColumns are authorColumns of the second author of a duplicate
+// pair, aliased as d.
+var duplicateColumns = strings.ReplaceAll(authorColumns, "a.", "d.")
+
+func (p *postgresRepository) LockAuthor(ctx context.Context, authorID string) (entity.Author, error) {
+	const query = `SELECT ` + authorColumns + ` FROM author a WHERE a.id = $1 FOR UPDATE`
+
+	author, err := scanAuthor(getQuerier(ctx, p.db).QueryRow(ctx, query, authorID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Author{}, entity.ErrAuthorNotFound
+	}
+
+	if err != nil {
+		return entity.Author{}, err
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) ResolveAuthorID(ctx context.Context, authorID string) (string, error) {
+	const query = `SELECT coalesce((SELECT author_id::text FROM author_alias WHERE alias_id = $1::uuid), $1::text)`
+
+	var resolved string
+
+	if err := getQuerier(ctx, p.db).QueryRow(ctx, query, authorID).Scan(&resolved); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+func (p *postgresRepository) MergeAuthors(ctx context.Context, targetID string, sourceIDs []string) error {
+	return runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		const (
+			moveAliases  = `UPDATE author_alias SET author_id = $1 WHERE author_id = ANY ($2::uuid[])`
+			addAliases   = `INSERT INTO author_alias (alias_id, author_id) SELECT unnest($2::uuid[]), $1`
+			deleteMerged = `DELETE FROM author WHERE id = ANY ($1::uuid[])`
+		)
+
+		if _, err := tx.Exec(ctx, moveAliases, targetID, sourceIDs); err != nil {
+			return linkError(err)
+		}
+
+		if _, err := tx.Exec(ctx, addAliases, targetID, sourceIDs); err != nil {
+			return linkError(err)
+		}
+
+		tag, err := tx.Exec(ctx, deleteMerged, sourceIDs)
+
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() != int64(len(sourceIDs)) {
+			return entity.ErrAuthorNotFound
+		}
+
+		return nil
+	})
+}
+
+func (p *postgresRepository) FindDuplicateAuthors(
+	ctx context.Context,
+	minSimilarity float64,
+	limit int,
+) ([]entity.AuthorDuplicate, error) {
+	var duplicates []entity.AuthorDuplicate
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		// The % operator uses the trigram index, but only compares with the
+		// threshold of the setting.
+		const setThreshold = `SELECT set_config('pg_trgm.similarity_threshold', $1::text, true)`
+
+		query := `
+SELECT ` + authorColumns + `, ` + duplicateColumns + `, similarity(a.name, d.name) AS similarity
+FROM author a
+         JOIN author d ON a.name % d.name AND a.id < d.id
+ORDER BY similarity DESC, a.id, d.id
+LIMIT $1`
+
+		if _, err := tx.Exec(ctx, setThreshold, minSimilarity); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, query, limit)
+
+		if err != nil {
+			return err
+		}
+
+		duplicates, err = pgx.CollectRows(rows, scanAuthorDuplicate)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+func scanAuthorDuplicate(row pgx.CollectableRow) (entity.AuthorDuplicate, error) {
+	var duplicate entity.AuthorDuplicate
+
+	destinations := authorDestinations(&duplicate.Author)
+	destinations = append(destinations, authorDestinations(&duplicate.Duplicate)...)
+	destinations = append(destinations, &duplicate.Similarity)
+
+	err := row.Scan(destinations...)
+
+	return duplicate, err
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ CopiesRepository = (*postgresRepository)(nil)
+
+// copyColumns are scanned by scanCopy from a copy row aliased as c.
+const copyColumns = `c.id,
+       c.book_id,
+       c.barcode,
+       c.branch,
+       c.location,
+       c.condition::text,
+       coalesce(to_char(c.acquired_on, 'YYYY-MM-DD'), ''),
+       c.status::text,
+       c.created_at,
+       c.updated_at`
+
+func (p *postgresRepository) CreateCopy(ctx context.Context, bookCopy entity.Copy) (entity.Copy, error) {
+	const query = `
+INSERT INTO copy AS c (book_id, barcode, branch, location, condition, acquired_on, status)
+VALUES ($1, $2, $3, $4, $5::text::copy_condition, nullif($6, '')::date, $7::text::copy_status)
+RETURNING ` + copyColumns
+
+	created, err := scanCopy(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		bookCopy.BookID,
+		bookCopy.Barcode,
+		bookCopy.Branch,
+		bookCopy.Location,
+		string(bookCopy.Condition),
+		bookCopy.AcquiredOn,
+		string(bookCopy.Status),
+	))
+
+	if err != nil {
+		return entity.Copy{}, copyWriteError(err)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetCopy(ctx context.Context, copyID string) (entity.Copy, error) {
+	const query = `SELECT ` + copyColumns + ` FROM copy c WHERE c.id = $1`
+
+	bookCopy, err := scanCopy(getQuerier(ctx, p.db).QueryRow(ctx, query, copyID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Copy{}, entity.ErrCopyNotFound
+	}
+
+	if err != nil {
+		return entity.Copy{}, err
+	}
+
+	return bookCopy, nil
+}
+
+func (p *postgresRepository) LockCopy(ctx context.Context, copyID string) (entity.Copy, error) {
+	const query = `SELECT ` + copyColumns + ` FROM copy c WHERE c.id = $1 FOR UPDATE`
+
+	bookCopy, err := scanCopy(getQuerier(ctx, p.db).QueryRow(ctx, query, copyID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Copy{}, entity.ErrCopyNotFound
+	}
+
+	if err != nil {
+		return entity.Copy{}, err
+	}
+
+	return bookCopy, nil
+}
+
+func (p *postgresRepository) UpdateCopy(ctx context.Context, copyID string, patch entity.CopyPatch) (entity.Copy, error) {
+	const query = `
+UPDATE copy AS c
+SET barcode     = coalesce($2, c.barcode),
+    branch      = coalesce($3, c.branch),
+    location    = coalesce($4, c.location),
+    condition   = coalesce($5::text::copy_condition, c.condition),
+    acquired_on = CASE WHEN $6::text IS NULL THEN c.acquired_on ELSE nullif($6, '')::date END,
+    status      = coalesce($7::text::copy_status, c.status)
+WHERE c.id = $1
+RETURNING ` + copyColumns
+
+	bookCopy, err := scanCopy(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		copyID,
+		patch.Barcode,
+		patch.Branch,
+		patch.Location,
+		(*string)(patch.Condition),
+		patch.AcquiredOn,
+		(*string)(patch.Status),
+	))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Copy{}, entity.ErrCopyNotFound
+	}
+
+	if err != nil {
+		return entity.Copy{}, copyWriteError(err)
+	}
+
+	return bookCopy, nil
+}
+
+func (p *postgresRepository) DeleteCopy(ctx context.Context, copyID string) error {
+	const query = `DELETE FROM copy WHERE id = $1`
+
+	tag, err := getQuerier(ctx, p.db).Exec(ctx, query, copyID)
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return entity.ErrCopyHasLoans
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCopyNotFound
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) GetBookCopies(ctx context.Context, bookID string) ([]entity.Copy, error) {
+	const query = `SELECT ` + copyColumns + ` FROM copy c WHERE c.book_id = $1 ORDER BY c.barcode`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, bookID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Copy, error) {
+		return scanCopy(row)
+	})
+}
+
+func (p *postgresRepository) GetBookAvailability(ctx context.Context, bookID string) (entity.Availability, error) {
+	const query = `
+SELECT count(*),
+       count(*) FILTER (WHERE status = 'available'),
+       count(*) FILTER (WHERE status = 'on_loan'),
+       count(*) FILTER (WHERE status = 'on_hold'),
+       count(*) FILTER (WHERE status = 'lost'),
+       count(*) FILTER (WHERE status = 'withdrawn')
+FROM copy
+WHERE book_id = $1`
+
+	var availability entity.Availability
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, bookID).Scan(
+		&availability.Total,
+		&availability.Available,
+		&availability.OnLoan,
+		&availability.OnHold,
+		&availability.Lost,
+		&availability.Withdrawn,
+	)
+
+	return availability, err
+}
+
+func scanCopy(row pgx.Row) (entity.Copy, error) {
+	var bookCopy entity.Copy
+	err := row.Scan(
+		&bookCopy.ID,
+		&bookCopy.BookID,
+		&bookCopy.Barcode,
+		&bookCopy.Branch,
+		&bookCopy.Location,
+		&bookCopy.Condition,
+		&bookCopy.AcquiredOn,
+		&bookCopy.Status,
+		&bookCopy.CreatedAt,
+		&bookCopy.UpdatedAt,
+	)
+
+	return bookCopy, err
+}
+
+// copyWriteError maps the constraint violations of the copy table to entity
+// errors.
+func copyWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolationCode:
+		return entity.ErrBarcodeTaken
+	case foreignKeyViolationCode:
+		return entity.ErrBookNotFound
+	default:
+		return err
+	}
+}
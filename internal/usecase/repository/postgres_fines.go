@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ FinesRepository = (*postgresRepository)(nil)
+
+func (p *postgresRepository) TryLockFines(ctx context.Context) (bool, error) {
+	const query = `SELECT pg_try_advisory_xact_lock($1)`
+
+	var locked bool
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, finesLockKey).Scan(&locked)
+
+	return locked, err
+}
+
+func (p *postgresRepository) GetOverdueLoans(
+	ctx context.Context,
+	before time.Time,
+	maxFine int64,
+	afterID string,
+	limit int,
+) ([]entity.Loan, error) {
+	const query = `
+SELECT ` + loanColumns + `
+FROM loan l
+WHERE l.returned_at IS NULL
+  AND l.due_at < $1
+  AND ($3::text = '' OR l.id > nullif($3, '')::uuid)
+  AND ($2::bigint = 0 OR (SELECT coalesce(sum(f.amount), 0)::bigint
+                  FROM fines_ledger f
+                  WHERE f.loan_id = l.id
+                    AND f.kind = 'accrual'
+                    AND f.account = 'patron') < $2)
+ORDER BY l.id
+LIMIT $4`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, before.UTC(), maxFine, afterID, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Loan, error) {
+		return scanLoan(row)
+	})
+}
+
+func (p *postgresRepository) LockLoanFines(ctx context.Context, loanID string) (entity.Loan, int64, error) {
+	const (
+		lockQuery    = `SELECT ` + loanColumns + ` FROM loan l WHERE l.id = $1 FOR UPDATE`
+		accruedQuery = `
+SELECT coalesce(sum(amount), 0)::bigint
+FROM fines_ledger
+WHERE loan_id = $1
+  AND kind = 'accrual'
+  AND account = 'patron'`
+	)
+
+	q := getQuerier(ctx, p.db)
+	loan, err := scanLoan(q.QueryRow(ctx, lockQuery, loanID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Loan{}, 0, entity.ErrLoanNotFound
+	}
+
+	if err != nil {
+		return entity.Loan{}, 0, err
+	}
+
+	var accrued int64
+
+	if err = q.QueryRow(ctx, accruedQuery, loanID).Scan(&accrued); err != nil {
+		return entity.Loan{}, 0, err
+	}
+
+	return loan, accrued, nil
+}
+
+func (p *postgresRepository) LockPatronBalance(ctx context.Context, patronID string) (int64, error) {
+	const lockQuery = `SELECT 1 FROM patron WHERE id = $1 FOR UPDATE`
+
+	q := getQuerier(ctx, p.db)
+
+	var locked int
+
+	err := q.QueryRow(ctx, lockQuery, patronID).Scan(&locked)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, entity.ErrPatronNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return patronBalance(ctx, q, patronID)
+}
+
+func (p *postgresRepository) GetPatronBalance(ctx context.Context, patronID string) (int64, error) {
+	const existsQuery = `SELECT EXISTS(SELECT 1 FROM patron WHERE id = $1)`
+
+	q := getQuerier(ctx, p.db)
+
+	var exists bool
+
+	if err := q.QueryRow(ctx, existsQuery, patronID).Scan(&exists); err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, entity.ErrPatronNotFound
+	}
+
+	return patronBalance(ctx, q, patronID)
+}
+
+func patronBalance(ctx context.Context, q querier, patronID string) (int64, error) {
+	const query = `
+SELECT coalesce(sum(amount), 0)::bigint
+FROM fines_ledger
+WHERE patron_id = $1
+  AND account = 'patron'`
+
+	var balance int64
+	err := q.QueryRow(ctx, query, patronID).Scan(&balance)
+
+	return balance, err
+}
+
+func (p *postgresRepository) PostLedgerTransaction(
+	ctx context.Context,
+	transaction entity.LedgerTransaction,
+) (entity.LedgerTransaction, error) {
+	// The entries share the transaction id generated once by the first
+	// common table expression.
+	const query = `
+WITH t AS (SELECT uuid_generate_v4() AS id),
+     posted AS (
+         INSERT INTO fines_ledger (transaction_id, kind, patron_id, loan_id, account, amount, note, created_at)
+             SELECT t.id,
+                    $1::text::ledger_entry_kind,
+                    $2,
+                    nullif($3, '')::uuid,
+                    entry.account::ledger_account,
+                    entry.amount,
+                    $7,
+                    $8
+             FROM t,
+                  (VALUES ($4::text, $6::bigint), ($5::text, -$6::bigint)) AS entry (account, amount)
+             RETURNING transaction_id)
+SELECT DISTINCT transaction_id
+FROM posted`
+
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query,
+		string(transaction.Kind),
+		transaction.PatronID,
+		transaction.LoanID,
+		string(transaction.Debit),
+		string(transaction.Credit),
+		transaction.Amount,
+		transaction.Note,
+		transaction.CreatedAt.UTC(),
+	).Scan(&transaction.ID)
+
+	if err != nil {
+		return entity.LedgerTransaction{}, ledgerWriteError(err)
+	}
+
+	return transaction, nil
+}
+
+// ledgerWriteError maps the constraint violations of the fines_ledger table
+// to entity errors.
+func ledgerWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "fines_ledger_loan_id_fkey":
+		return entity.ErrLoanNotFound
+	case pgErr.Code == foreignKeyViolationCode:
+		return entity.ErrPatronNotFound
+	case pgErr.Code == checkViolationCode:
+		return entity.ErrInvalidAmount
+	default:
+		return err
+	}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ HoldsRepository = (*postgresRepository)(nil)
+
+// holdColumns are scanned by scanHold from a hold row aliased as h. They are
+// named to be selected from the common table expressions returning them.
+const holdColumns = `h.id,
+       h.book_id,
+       h.patron_id,
+       h.status::text                 AS status,
+       coalesce(h.copy_id::text, '') AS copy_id,
+       h.placed_at,
+       h.pickup_by,
+       h.closed_at`
+
+func (p *postgresRepository) CreateHold(ctx context.Context, hold entity.Hold) (entity.Hold, error) {
+	const query = `
+INSERT INTO hold AS h (book_id, patron_id, status, placed_at)
+VALUES ($1, $2, $3::text::hold_status, $4)
+RETURNING ` + holdColumns
+
+	created, err := scanHold(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		hold.BookID,
+		hold.PatronID,
+		string(hold.Status),
+		hold.PlacedAt.UTC(),
+	))
+
+	if err != nil {
+		return entity.Hold{}, holdWriteError(err)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetHold(ctx context.Context, holdID string) (entity.Hold, error) {
+	const query = `SELECT ` + holdColumns + ` FROM hold h WHERE h.id = $1`
+
+	return p.getHold(ctx, query, holdID)
+}
+
+func (p *postgresRepository) LockHold(ctx context.Context, holdID string) (entity.Hold, error) {
+	const query = `SELECT ` + holdColumns + ` FROM hold h WHERE h.id = $1 FOR UPDATE`
+
+	return p.getHold(ctx, query, holdID)
+}
+
+func (p *postgresRepository) GetCopyHold(ctx context.Context, copyID string) (entity.Hold, error) {
+	const query = `SELECT ` + holdColumns + ` FROM hold h WHERE h.copy_id = $1 AND h.status = 'ready'`
+
+	return p.getHold(ctx, query, copyID)
+}
+
+func (p *postgresRepository) getHold(ctx context.Context, query string, args ...any) (entity.Hold, error) {
+	hold, err := scanHold(getQuerier(ctx, p.db).QueryRow(ctx, query, args...))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Hold{}, entity.ErrHoldNotFound
+	}
+
+	if err != nil {
+		return entity.Hold{}, err
+	}
+
+	return hold, nil
+}
+
+func (p *postgresRepository) ListHolds(ctx context.Context, filter entity.HoldFilter) ([]entity.Hold, error) {
+	const query = `
+SELECT ` + holdColumns + `
+FROM hold h
+WHERE ($1::text = '' OR h.book_id = nullif($1, '')::uuid)
+  AND ($2::text = '' OR h.patron_id = nullif($2, '')::uuid)
+  AND (NOT $3 OR h.status IN ('waiting', 'ready'))
+ORDER BY h.placed_at, h.id`
+
+	return p.queryHolds(ctx, query, filter.BookID, filter.PatronID, filter.ActiveOnly)
+}
+
+func (p *postgresRepository) CountWaitingHolds(ctx context.Context, bookID string) (int, error) {
+	const query = `SELECT count(*) FROM hold WHERE book_id = $1 AND status = 'waiting'`
+
+	var waiting int
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, bookID).Scan(&waiting)
+
+	return waiting, err
+}
+
+func (p *postgresRepository) AssignCopy(ctx context.Context, copyID string, pickupBy time.Time) (entity.Hold, error) {
+	// Copies of a book are assigned one at a time. A head of the queue locked
+	// by a concurrent cancellation is waited for rather than skipped, so the
+	// holds are served in the order they were placed.
+	const (
+		lockQuery = `
+SELECT pg_advisory_xact_lock(hashtextextended(c.book_id::text, $2))
+FROM copy c
+WHERE c.id = $1`
+		nextQuery = `
+SELECT h.id
+FROM hold h
+         JOIN copy c ON c.book_id = h.book_id
+WHERE c.id = $1
+  AND h.status = 'waiting'
+ORDER BY h.placed_at, h.id
+LIMIT 1 FOR UPDATE OF h`
+		putAsideQuery = `UPDATE copy SET status = 'on_hold' WHERE id = $1 AND status = 'available'`
+		readyQuery    = `
+UPDATE hold AS h
+SET status    = 'ready',
+    copy_id   = $2,
+    pickup_by = $3
+WHERE h.id = $1
+RETURNING ` + holdColumns
+	)
+
+	var hold entity.Hold
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockQuery, copyID, holdQueueLockKey); err != nil {
+			return err
+		}
+
+		var holdID string
+		err := tx.QueryRow(ctx, nextQuery, copyID).Scan(&holdID)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrHoldNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, putAsideQuery, copyID)
+
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return entity.ErrCopyNotAvailable
+		}
+
+		hold, err = scanHold(tx.QueryRow(ctx, readyQuery, holdID, copyID, pickupBy.UTC()))
+
+		return err
+	})
+
+	return hold, err
+}
+
+func (p *postgresRepository) CloseHold(
+	ctx context.Context,
+	holdID string,
+	status entity.HoldStatus,
+	closedAt time.Time,
+) (entity.Hold, error) {
+	const query = `
+WITH closed AS (
+    UPDATE hold AS h
+    SET status    = $2::text::hold_status,
+        closed_at = $3
+    WHERE h.id = $1
+      AND h.status IN ('waiting', 'ready')
+    RETURNING ` + holdColumns + `),
+     released AS (
+         UPDATE copy AS c
+         SET status = 'available'
+         FROM closed
+         WHERE c.id::text = closed.copy_id
+           AND c.status = 'on_hold')
+SELECT *
+FROM closed`
+
+	hold, err := p.getHold(ctx, query, holdID, string(status), closedAt.UTC())
+
+	if errors.Is(err, entity.ErrHoldNotFound) {
+		return entity.Hold{}, entity.ErrHoldNotActive
+	}
+
+	return hold, err
+}
+
+func (p *postgresRepository) GetExpiredHolds(ctx context.Context, before time.Time, limit int) ([]entity.Hold, error) {
+	const query = `
+SELECT ` + holdColumns + `
+FROM hold h
+WHERE h.status = 'ready'
+  AND h.pickup_by < $1
+ORDER BY h.pickup_by
+LIMIT $2`
+
+	return p.queryHolds(ctx, query, before.UTC(), limit)
+}
+
+func (p *postgresRepository) queryHolds(ctx context.Context, query string, args ...any) ([]entity.Hold, error) {
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Hold, error) {
+		return scanHold(row)
+	})
+}
+
+func scanHold(row pgx.Row) (entity.Hold, error) {
+	var hold entity.Hold
+	err := row.Scan(
+		&hold.ID,
+		&hold.BookID,
+		&hold.PatronID,
+		&hold.Status,
+		&hold.CopyID,
+		&hold.PlacedAt,
+		&hold.PickupBy,
+		&hold.ClosedAt,
+	)
+
+	return hold, err
+}
+
+// holdWriteError maps the constraint violations of the hold table to entity
+// errors.
+func holdWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == uniqueViolationCode:
+		return entity.ErrHoldExists
+	case pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "hold_patron_id_fkey":
+		return entity.ErrPatronNotFound
+	case pgErr.Code == foreignKeyViolationCode:
+		return entity.ErrBookNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project/library/internal/entity"
+)
+
+var _ IdempotencyRepository = (*postgresRepository)(nil)
+
+func (p *postgresRepository) ReserveIdempotencyKey(
+	ctx context.Context,
+	record entity.IdempotencyRecord,
+	pendingTTL time.Duration,
+) (entity.IdempotencyRecord, bool, error) {
+	const (
+		reserve = `
+INSERT INTO idempotency_key AS k (key, fingerprint, token, created_at, expires_at)
+VALUES ($1, $2, $6, $3, $4)
+ON CONFLICT (key) DO UPDATE
+    SET fingerprint = excluded.fingerprint,
+        token       = excluded.token,
+        response    = NULL,
+        completed   = false,
+        created_at  = excluded.created_at,
+        expires_at  = excluded.expires_at
+WHERE k.expires_at <= $3
+   OR (NOT k.completed AND k.created_at < $5)
+RETURNING k.key`
+		existing = `SELECT key, fingerprint, response, completed, expires_at FROM idempotency_key WHERE key = $1`
+	)
+
+	var (
+		stored   entity.IdempotencyRecord
+		reserved bool
+	)
+
+	now := time.Now().UTC()
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		var key string
+
+		err := tx.QueryRow(ctx, reserve, record.Key, record.Fingerprint, now, record.ExpiresAt.UTC(),
+			now.Add(-pendingTTL), record.Token).Scan(&key)
+
+		if err == nil {
+			stored, reserved = record, true
+			return nil
+		}
+
+		// The conflicting row is locked now, so it is read as it stays.
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		return tx.QueryRow(ctx, existing, record.Key).Scan(
+			&stored.Key,
+			&stored.Fingerprint,
+			&stored.Response,
+			&stored.Completed,
+			&stored.ExpiresAt,
+		)
+	})
+
+	if err != nil {
+		return entity.IdempotencyRecord{}, false, err
+	}
+
+	return stored, reserved, nil
+}
+
+func (p *postgresRepository) CompleteIdempotencyKey(ctx context.Context, key, token string, response []byte) error {
+	const query = `UPDATE idempotency_key SET response = $3, completed = true WHERE key = $1 AND token = $2`
+
+	_, err := getQuerier(ctx, p.db).Exec(ctx, query, key, token, response)
+
+	return err
+}
+
+func (p *postgresRepository) ReleaseIdempotencyKey(ctx context.Context, key, token string) error {
+	const query = `DELETE FROM idempotency_key WHERE key = $1 AND token = $2 AND NOT completed`
+
+	_, err := getQuerier(ctx, p.db).Exec(ctx, query, key, token)
+
+	return err
+}
+
+func (p *postgresRepository) PruneIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	const query = `DELETE FROM idempotency_key WHERE expires_at < $1`
+
+	tag, err := getQuerier(ctx, p.db).Exec(ctx, query, before.UTC())
+
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
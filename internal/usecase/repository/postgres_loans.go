@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ LoansRepository = (*postgresRepository)(nil)
+
+// loanColumns are scanned by scanLoan from a loan row aliased as l.
+const loanColumns = `l.id,
+       l.copy_id,
+       l.patron_id,
+       l.checked_out_at,
+       l.due_at,
+       l.renewals,
+       l.returned_at`
+
+func (p *postgresRepository) LockPatronLoans(ctx context.Context, patronID string) (int, error) {
+	const (
+		lockQuery  = `SELECT 1 FROM patron WHERE id = $1 FOR UPDATE`
+		countQuery = `SELECT count(*) FROM loan WHERE patron_id = $1 AND returned_at IS NULL`
+	)
+
+	q := getQuerier(ctx, p.db)
+
+	var locked, active int
+
+	err := q.QueryRow(ctx, lockQuery, patronID).Scan(&locked)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, entity.ErrPatronNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err = q.QueryRow(ctx, countQuery, patronID).Scan(&active); err != nil {
+		return 0, err
+	}
+
+	return active, nil
+}
+
+func (p *postgresRepository) CreateLoan(ctx context.Context, loan entity.Loan) (entity.Loan, error) {
+	// Updating the copy first makes concurrent checkouts of it wait for
+	// each other, the partial unique index backs this up.
+	const query = `
+WITH lent AS (
+    UPDATE copy
+    SET status = 'on_loan'
+    WHERE id = $1
+      AND status = 'available'
+    RETURNING id)
+INSERT
+INTO loan AS l (copy_id, patron_id, checked_out_at, due_at)
+SELECT lent.id, $2, $3, $4
+FROM lent
+RETURNING ` + loanColumns
+
+	q := getQuerier(ctx, p.db)
+	created, err := scanLoan(q.QueryRow(ctx, query,
+		loan.CopyID,
+		loan.PatronID,
+		loan.CheckedOutAt.UTC(),
+		loan.DueAt.UTC(),
+	))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Loan{}, unavailableCopyError(ctx, q, loan.CopyID)
+	}
+
+	if err != nil {
+		return entity.Loan{}, loanWriteError(err)
+	}
+
+	return created, nil
+}
+
+// unavailableCopyError tells a missing copy from one that can not be lent.
+func unavailableCopyError(ctx context.Context, q querier, copyID string) error {
+	const query = `SELECT EXISTS(SELECT 1 FROM copy WHERE id = $1)`
+
+	var exists bool
+
+	if err := q.QueryRow(ctx, query, copyID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return entity.ErrCopyNotFound
+	}
+
+	return entity.ErrCopyNotAvailable
+}
+
+func (p *postgresRepository) GetLoan(ctx context.Context, loanID string) (entity.Loan, error) {
+	const query = `SELECT ` + loanColumns + ` FROM loan l WHERE l.id = $1`
+
+	loan, err := scanLoan(getQuerier(ctx, p.db).QueryRow(ctx, query, loanID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Loan{}, entity.ErrLoanNotFound
+	}
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+func (p *postgresRepository) RenewLoan(
+	ctx context.Context,
+	loanID string,
+	renewals int,
+	dueAt time.Time,
+) (entity.Loan, error) {
+	const query = `
+UPDATE loan AS l
+SET due_at   = $3,
+    renewals = l.renewals + 1
+WHERE l.id = $1
+  AND l.renewals = $2
+  AND l.returned_at IS NULL
+RETURNING ` + loanColumns
+
+	const existsQuery = `SELECT EXISTS(SELECT 1 FROM loan WHERE id = $1)`
+
+	q := getQuerier(ctx, p.db)
+	loan, err := scanLoan(q.QueryRow(ctx, query, loanID, renewals, dueAt.UTC()))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Loan{}, updateMissError(ctx, q, existsQuery, loanID, entity.ErrLoanNotFound)
+	}
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+func (p *postgresRepository) ReturnCopy(ctx context.Context, copyID string, returnedAt time.Time) (entity.Loan, error) {
+	const query = `
+WITH returned AS (
+    UPDATE loan AS l
+    SET returned_at = $2
+    WHERE l.copy_id = $1
+      AND l.returned_at IS NULL
+    RETURNING ` + loanColumns + `),
+     released AS (
+         UPDATE copy
+         SET status = 'available'
+         WHERE id = $1
+           AND EXISTS(SELECT 1 FROM returned))
+SELECT *
+FROM returned`
+
+	loan, err := scanLoan(getQuerier(ctx, p.db).QueryRow(ctx, query, copyID, returnedAt.UTC()))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Loan{}, entity.ErrCopyNotOnLoan
+	}
+
+	if err != nil {
+		return entity.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+func scanLoan(row pgx.Row) (entity.Loan, error) {
+	var loan entity.Loan
+	err := row.Scan(
+		&loan.ID,
+		&loan.CopyID,
+		&loan.PatronID,
+		&loan.CheckedOutAt,
+		&loan.DueAt,
+		&loan.Renewals,
+		&loan.ReturnedAt,
+	)
+
+	return loan, err
+}
+
+// loanWriteError maps the constraint violations of the loan table to entity
+// errors.
+func loanWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case uniqueViolationCode:
+		return entity.ErrCopyNotAvailable
+	case foreignKeyViolationCode:
+		return entity.ErrPatronNotFound
+	default:
+		return err
+	}
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ PatronsRepository = (*postgresRepository)(nil)
+
+// patronColumns are scanned by scanPatron from a patron row aliased as p.
+const patronColumns = `p.id,
+       p.card_number,
+       p.name,
+       p.email,
+       p.phone,
+       p.address,
+       p.membership_type::text,
+       coalesce(to_char(p.expires_on, 'YYYY-MM-DD'), ''),
+       p.status::text,
+       p.suspension_reason,
+       p.created_at,
+       p.updated_at`
+
+func (p *postgresRepository) CreatePatron(ctx context.Context, patron entity.Patron) (entity.Patron, error) {
+	const query = `
+INSERT INTO patron AS p (card_number, name, email, phone, address, membership_type, expires_on, status)
+VALUES ($1, $2, $3, $4, $5, $6::text::membership_type, nullif($7, '')::date, $8::text::patron_status)
+RETURNING ` + patronColumns
+
+	created, err := scanPatron(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		patron.CardNumber,
+		patron.Name.Reveal(),
+		patron.Email.Reveal(),
+		patron.Phone.Reveal(),
+		patron.Address.Reveal(),
+		string(patron.MembershipType),
+		patron.ExpiresOn,
+		string(patron.Status),
+	))
+
+	if err != nil {
+		return entity.Patron{}, patronWriteError(err)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetPatron(ctx context.Context, patronID string) (entity.Patron, error) {
+	const query = `SELECT ` + patronColumns + ` FROM patron p WHERE p.id = $1`
+
+	patron, err := scanPatron(getQuerier(ctx, p.db).QueryRow(ctx, query, patronID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Patron{}, entity.ErrPatronNotFound
+	}
+
+	if err != nil {
+		return entity.Patron{}, err
+	}
+
+	return patron, nil
+}
+
+func (p *postgresRepository) UpdatePatron(
+	ctx context.Context,
+	patronID string,
+	patch entity.PatronPatch,
+) (entity.Patron, error) {
+	const query = `
+UPDATE patron AS p
+SET card_number       = coalesce($2, p.card_number),
+    name              = coalesce($3, p.name),
+    email             = coalesce($4, p.email),
+    phone             = coalesce($5, p.phone),
+    address           = coalesce($6, p.address),
+    membership_type   = coalesce($7::text::membership_type, p.membership_type),
+    expires_on        = CASE WHEN $8::text IS NULL THEN p.expires_on ELSE nullif($8, '')::date END,
+    status            = coalesce($9::text::patron_status, p.status),
+    suspension_reason = coalesce($10, p.suspension_reason)
+WHERE p.id = $1
+RETURNING ` + patronColumns
+
+	patron, err := scanPatron(getQuerier(ctx, p.db).QueryRow(ctx, query,
+		patronID,
+		patch.CardNumber,
+		(*string)(patch.Name),
+		(*string)(patch.Email),
+		(*string)(patch.Phone),
+		(*string)(patch.Address),
+		(*string)(patch.MembershipType),
+		patch.ExpiresOn,
+		(*string)(patch.Status),
+		patch.SuspensionReason,
+	))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Patron{}, entity.ErrPatronNotFound
+	}
+
+	if err != nil {
+		return entity.Patron{}, patronWriteError(err)
+	}
+
+	return patron, nil
+}
+
+func (p *postgresRepository) ListPatrons(
+	ctx context.Context,
+	filter entity.PatronFilter,
+	afterCardNumber string,
+	limit int,
+) ([]entity.Patron, error) {
+	const query = `
+SELECT ` + patronColumns + `
+FROM patron p
+WHERE ($1::text = '' OR p.status::text = $1)
+  AND ($2::text = '' OR p.membership_type::text = $2)
+  AND p.card_number > $3
+ORDER BY p.card_number
+LIMIT $4`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query,
+		string(filter.Status),
+		string(filter.MembershipType),
+		afterCardNumber,
+		limit,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Patron, error) {
+		return scanPatron(row)
+	})
+}
+
+func scanPatron(row pgx.Row) (entity.Patron, error) {
+	var patron entity.Patron
+	err := row.Scan(
+		&patron.ID,
+		&patron.CardNumber,
+		&patron.Name,
+		&patron.Email,
+		&patron.Phone,
+		&patron.Address,
+		&patron.MembershipType,
+		&patron.ExpiresOn,
+		&patron.Status,
+		&patron.SuspensionReason,
+		&patron.CreatedAt,
+		&patron.UpdatedAt,
+	)
+
+	return patron, err
+}
+
+// patronWriteError maps the constraint violations of the patron table to
+// entity errors.
+func patronWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return entity.ErrCardNumberTaken
+	}
+
+	return err
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ ReviewsRepository = (*postgresRepository)(nil)
+
+// reviewColumns are scanned by scanReview from a review row aliased as r.
+const reviewColumns = `r.id,
+       r.book_id,
+       r.patron_id,
+       r.rating,
+       r.text,
+       r.created_at,
+       r.updated_at`
+
+// addBookRating adds $2 reviews with the rating sum $3 to the rating of the
+// book $1, negative values remove them.
+const addBookRating = `
+INSERT INTO book_rating AS br (book_id, rating_count, rating_sum)
+VALUES ($1, $2, $3)
+ON CONFLICT (book_id) DO UPDATE SET rating_count = br.rating_count + excluded.rating_count,
+                                    rating_sum   = br.rating_sum + excluded.rating_sum`
+
+func (p *postgresRepository) CreateReview(ctx context.Context, review entity.Review) (entity.Review, error) {
+	const query = `
+INSERT INTO review AS r (book_id, patron_id, rating, text)
+VALUES ($1, $2, $3, $4)
+RETURNING ` + reviewColumns
+
+	var created entity.Review
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		var err error
+		created, err = scanReview(tx.QueryRow(ctx, query, review.BookID, review.PatronID, review.Rating, review.Text))
+
+		if err != nil {
+			return reviewWriteError(err)
+		}
+
+		_, err = tx.Exec(ctx, addBookRating, created.BookID, 1, created.Rating)
+
+		return err
+	})
+
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetReview(ctx context.Context, reviewID string) (entity.Review, error) {
+	const query = `SELECT ` + reviewColumns + ` FROM review r WHERE r.id = $1`
+
+	review, err := scanReview(getQuerier(ctx, p.db).QueryRow(ctx, query, reviewID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Review{}, entity.ErrReviewNotFound
+	}
+
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (p *postgresRepository) UpdateReview(
+	ctx context.Context,
+	reviewID string,
+	patch entity.ReviewPatch,
+) (entity.Review, error) {
+	// The previous rating is read under the row lock, so concurrent updates
+	// can not take the same rating off the aggregate twice.
+	const query = `
+UPDATE review AS r
+SET rating = coalesce($2, r.rating),
+    text   = coalesce($3, r.text)
+FROM (SELECT id, rating FROM review WHERE id = $1 FOR UPDATE) previous
+WHERE r.id = previous.id
+RETURNING ` + reviewColumns + `, previous.rating`
+
+	var review entity.Review
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		var previousRating int
+		var err error
+		review, err = scanReview(tx.QueryRow(ctx, query, reviewID, patch.Rating, patch.Text), &previousRating)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrReviewNotFound
+		}
+
+		if err != nil {
+			return reviewWriteError(err)
+		}
+
+		if review.Rating == previousRating {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, addBookRating, review.BookID, 0, review.Rating-previousRating)
+
+		return err
+	})
+
+	if err != nil {
+		return entity.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (p *postgresRepository) DeleteReview(ctx context.Context, reviewID string) error {
+	const query = `DELETE FROM review WHERE id = $1 RETURNING book_id, rating`
+
+	return runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		var bookID string
+		var rating int
+
+		err := tx.QueryRow(ctx, query, reviewID).Scan(&bookID, &rating)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrReviewNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, addBookRating, bookID, -1, -rating)
+
+		return err
+	})
+}
+
+func (p *postgresRepository) ListReviews(
+	ctx context.Context,
+	filter entity.ReviewFilter,
+	afterCreatedAt time.Time,
+	afterID string,
+	limit int,
+) ([]entity.Review, error) {
+	const query = `
+SELECT ` + reviewColumns + `
+FROM review r
+WHERE ($1::text = '' OR r.book_id = nullif($1, '')::uuid)
+  AND ($2::text = '' OR r.patron_id = nullif($2, '')::uuid)
+  AND ($4::text = '' OR (r.created_at, r.id) < ($3, nullif($4, '')::uuid))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $5`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query,
+		filter.BookID,
+		filter.PatronID,
+		afterCreatedAt.UTC(),
+		afterID,
+		limit,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Review, error) {
+		return scanReview(row)
+	})
+}
+
+func (p *postgresRepository) GetBookRating(ctx context.Context, bookID string) (entity.BookRating, error) {
+	const query = `SELECT rating_count, rating_sum FROM book_rating WHERE book_id = $1`
+
+	var rating entity.BookRating
+
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, bookID).Scan(&rating.Count, &rating.Sum)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.BookRating{}, nil
+	}
+
+	if err != nil {
+		return entity.BookRating{}, err
+	}
+
+	return rating, nil
+}
+
+// scanReview scans reviewColumns followed by the extra destinations.
+func scanReview(row pgx.Row, extra ...any) (entity.Review, error) {
+	var review entity.Review
+	err := row.Scan(append([]any{
+		&review.ID,
+		&review.BookID,
+		&review.PatronID,
+		&review.Rating,
+		&review.Text,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	}, extra...)...)
+
+	return review, err
+}
+
+func reviewWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == uniqueViolationCode:
+		return entity.ErrReviewExists
+	case pgErr.Code == foreignKeyViolationCode && pgErr.ConstraintName == "review_patron_id_fkey":
+		return entity.ErrPatronNotFound
+	case pgErr.Code == foreignKeyViolationCode:
+		return entity.ErrBookNotFound
+	case pgErr.Code == checkViolationCode:
+		return entity.ErrInvalidRating
+	default:
+		return err
+	}
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project/library/internal/entity"
+)
+
+var _ SubjectsRepository = (*postgresRepository)(nil)
+
+// subjectColumns are scanned by scanSubject from a subject row aliased as s.
+const subjectColumns = `s.id,
+       s.name,
+       coalesce(s.parent_id::text, ''),
+       s.created_at,
+       s.updated_at`
+
+// bookSubtree is a common table expression selecting the subject $1 and all
+// its descendants, used by bookFilter.
+const bookSubtree = `
+WITH RECURSIVE subtree AS (SELECT id
+                           FROM subject
+                           WHERE id = nullif($1, '')::uuid
+                           UNION ALL
+                           SELECT s.id
+                           FROM subject s
+                                    JOIN subtree t ON s.parent_id = t.id)`
+
+// bookFilter selects the books aliased as b classified under the subtree of
+// bookSubtree, tagged with $2 and titled $3, empty parameters match any book.
+const bookFilter = `($1::text = '' OR b.subject_ids && ARRAY(SELECT id FROM subtree))
+  AND ($2::text = '' OR b.tags @> ARRAY [$2::text])
+  AND ($3::text = '' OR b.normalized_name = $3)`
+
+func (p *postgresRepository) CreateSubject(ctx context.Context, subject entity.Subject) (entity.Subject, error) {
+	const query = `
+INSERT INTO subject AS s (name, parent_id)
+VALUES ($1, nullif($2, '')::uuid)
+RETURNING ` + subjectColumns
+
+	created, err := scanSubject(getQuerier(ctx, p.db).QueryRow(ctx, query, subject.Name, subject.ParentID))
+
+	if err != nil {
+		return entity.Subject{}, subjectWriteError(err)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetSubject(ctx context.Context, subjectID string) (entity.Subject, error) {
+	const query = `SELECT ` + subjectColumns + ` FROM subject s WHERE s.id = $1`
+
+	subject, err := scanSubject(getQuerier(ctx, p.db).QueryRow(ctx, query, subjectID))
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Subject{}, entity.ErrSubjectNotFound
+	}
+
+	if err != nil {
+		return entity.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+func (p *postgresRepository) UpdateSubject(
+	ctx context.Context,
+	subjectID string,
+	patch entity.SubjectPatch,
+) (entity.Subject, error) {
+	const query = `
+UPDATE subject AS s
+SET name      = coalesce($2, s.name),
+    parent_id = CASE WHEN $3::text IS NULL THEN s.parent_id ELSE nullif($3, '')::uuid END
+WHERE s.id = $1
+RETURNING ` + subjectColumns
+
+	var subject entity.Subject
+
+	err := runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		if patch.ParentID != nil && *patch.ParentID != "" {
+			if err := checkSubjectMove(ctx, tx, subjectID, *patch.ParentID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		subject, err = scanSubject(tx.QueryRow(ctx, query, subjectID, patch.Name, patch.ParentID))
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.ErrSubjectNotFound
+		}
+
+		if err != nil {
+			return subjectWriteError(err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return entity.Subject{}, err
+	}
+
+	return subject, nil
+}
+
+// checkSubjectMove makes sure the new parent is not the subject itself or
+// one of its descendants, and keeps other moves from changing that until the
+// transaction ends.
+func checkSubjectMove(ctx context.Context, tx pgx.Tx, subjectID string, parentID string) error {
+	const query = `
+WITH RECURSIVE ancestors AS (SELECT id, parent_id
+                             FROM subject
+                             WHERE id = $2
+                             UNION ALL
+                             SELECT s.id, s.parent_id
+                             FROM subject s
+                                      JOIN ancestors a ON s.id = a.parent_id)
+SELECT EXISTS(SELECT 1 FROM ancestors WHERE id = $1)`
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, subjectTreeLockKey); err != nil {
+		return err
+	}
+
+	var cycle bool
+
+	if err := tx.QueryRow(ctx, query, subjectID, parentID).Scan(&cycle); err != nil {
+		return err
+	}
+
+	if cycle {
+		return entity.ErrSubjectCycle
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) DeleteSubject(ctx context.Context, subjectID string) error {
+	const (
+		deleteQuery = `DELETE FROM subject WHERE id = $1`
+		// Checked after the delete, which waits for the transactions
+		// classifying books under the subject, see lockSubjects.
+		usedQuery = `SELECT EXISTS(SELECT 1 FROM book WHERE subject_ids @> ARRAY [$1::uuid])`
+	)
+
+	return runInTx(ctx, p.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, deleteQuery, subjectID)
+
+		var pgErr *pgconn.PgError
+
+		// The parent_id keys of the subtopics refer to the subject.
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return entity.ErrSubjectInUse
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return entity.ErrSubjectNotFound
+		}
+
+		var used bool
+
+		if err = tx.QueryRow(ctx, usedQuery, subjectID).Scan(&used); err != nil {
+			return err
+		}
+
+		if used {
+			return entity.ErrSubjectInUse
+		}
+
+		return nil
+	})
+}
+
+func (p *postgresRepository) ListSubjects(ctx context.Context, rootID string) ([]entity.Subject, error) {
+	const query = `
+WITH RECURSIVE tree AS (SELECT *
+                        FROM subject
+                        WHERE CASE WHEN $1::text = '' THEN parent_id IS NULL ELSE id = nullif($1, '')::uuid END
+                        UNION ALL
+                        SELECT s.*
+                        FROM subject s
+                                 JOIN tree t ON s.parent_id = t.id)
+SELECT ` + subjectColumns + `
+FROM tree s
+ORDER BY s.name, s.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, rootID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Subject, error) {
+		return scanSubject(row)
+	})
+}
+
+func (p *postgresRepository) ListBooks(
+	ctx context.Context,
+	filter entity.BookFilter,
+	afterName string,
+	afterID string,
+	limit int,
+) ([]entity.Book, error) {
+	const query = bookSubtree + `
+SELECT ` + bookColumns + `, ` + bookCredits + `
+FROM book b
+         LEFT JOIN author_book ab ON ab.book_id = b.id
+WHERE ` + bookFilter + `
+  AND ($5::text = '' OR (b.name, b.id) > ($4, nullif($5, '')::uuid))
+GROUP BY b.id
+ORDER BY b.name, b.id
+LIMIT $6`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, filter.SubjectID, filter.Tag, filter.Title, afterName, afterID, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanBook)
+}
+
+func (p *postgresRepository) GetTagCounts(
+	ctx context.Context,
+	filter entity.BookFilter,
+	limit int,
+) ([]entity.TagCount, error) {
+	const query = bookSubtree + `
+SELECT t.tag, count(*)
+FROM book b,
+     unnest(b.tags) t(tag)
+WHERE ` + bookFilter + `
+GROUP BY t.tag
+ORDER BY count(*) DESC, t.tag
+LIMIT $4`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, filter.SubjectID, filter.Tag, filter.Title, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.TagCount, error) {
+		var count entity.TagCount
+		err := row.Scan(&count.Tag, &count.Count)
+
+		return count, err
+	})
+}
+
+// lockSubjects keeps the subjects from being deleted until the transaction
+// ends, as a foreign key would. It fails with entity.ErrSubjectNotFound
+// unless all of them exist.
+func lockSubjects(ctx context.Context, tx pgx.Tx, subjectIDs []string) error {
+	const query = `SELECT count(*) FROM (SELECT 1 FROM subject WHERE id = ANY ($1::uuid[]) FOR KEY SHARE) s`
+
+	if len(subjectIDs) == 0 {
+		return nil
+	}
+
+	var found int
+
+	if err := tx.QueryRow(ctx, query, subjectIDs).Scan(&found); err != nil {
+		return err
+	}
+
+	if found != len(subjectIDs) {
+		return entity.ErrSubjectNotFound
+	}
+
+	return nil
+}
+
+func scanSubject(row pgx.Row) (entity.Subject, error) {
+	var subject entity.Subject
+	err := row.Scan(
+		&subject.ID,
+		&subject.Name,
+		&subject.ParentID,
+		&subject.CreatedAt,
+		&subject.UpdatedAt,
+	)
+
+	return subject, err
+}
+
+// subjectWriteError maps the constraint violations of subject inserts and
+// updates to entity errors.
+func subjectWriteError(err error) error {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == uniqueViolationCode:
+		return entity.ErrSubjectNameTaken
+	case pgErr.Code == foreignKeyViolationCode:
+		return entity.ErrSubjectNotFound
+	case pgErr.Code == checkViolationCode && pgErr.ConstraintName == "subject_check":
+		return entity.ErrSubjectCycle
+	default:
+		return err
+	}
+}
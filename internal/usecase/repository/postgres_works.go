@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project/library/internal/entity"
+)
+
+var _ WorksRepository = (*postgresRepository)(nil)
+
+func (p *postgresRepository) CreateWork(ctx context.Context, work entity.Work) (entity.Work, error) {
+	const query = `INSERT INTO work (title) VALUES ($1) RETURNING id, title, created_at`
+
+	var created entity.Work
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, work.Title).Scan(&created.ID, &created.Title, &created.CreatedAt)
+
+	if err != nil {
+		return entity.Work{}, err
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetWork(ctx context.Context, workID string) (entity.Work, error) {
+	const query = `SELECT id, title, created_at FROM work WHERE id = $1`
+
+	var work entity.Work
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, workID).Scan(&work.ID, &work.Title, &work.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Work{}, entity.ErrWorkNotFound
+	}
+
+	if err != nil {
+		return entity.Work{}, err
+	}
+
+	return work, nil
+}
+
+func (p *postgresRepository) CreateSeries(ctx context.Context, series entity.Series) (entity.Series, error) {
+	const query = `INSERT INTO series (name) VALUES ($1) RETURNING id, name, created_at`
+
+	var created entity.Series
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, series.Name).Scan(&created.ID, &created.Name, &created.CreatedAt)
+
+	if err != nil {
+		return entity.Series{}, err
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetSeries(ctx context.Context, seriesID string) (entity.Series, error) {
+	const query = `SELECT id, name, created_at FROM series WHERE id = $1`
+
+	var series entity.Series
+	err := getQuerier(ctx, p.db).QueryRow(ctx, query, seriesID).Scan(&series.ID, &series.Name, &series.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Series{}, entity.ErrSeriesNotFound
+	}
+
+	if err != nil {
+		return entity.Series{}, err
+	}
+
+	return series, nil
+}
+
+func (p *postgresRepository) GetWorkEditions(ctx context.Context, workID string) ([]entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, ` + bookCredits + `
+FROM book b
+         LEFT JOIN author_book ab ON ab.book_id = b.id
+WHERE b.work_id = $1
+GROUP BY b.id
+ORDER BY b.publication_year = 0, b.publication_year, b.name, b.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, workID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanBook)
+}
+
+func (p *postgresRepository) GetSeriesBooks(ctx context.Context, seriesID string) ([]entity.Book, error) {
+	const query = `
+SELECT ` + bookColumns + `, ` + bookCredits + `
+FROM book b
+         LEFT JOIN author_book ab ON ab.book_id = b.id
+WHERE b.series_id = $1
+GROUP BY b.id
+ORDER BY b.series_position = 0, b.series_position, b.name, b.id`
+
+	rows, err := getQuerier(ctx, p.db).Query(ctx, query, seriesID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanBook)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ Transactor = (*transactorImpl)(nil)
+
+type transactorImpl struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactor(db *pgxpool.Pool) *transactorImpl {
+	return &transactorImpl{
+		db: db,
+	}
+}
+
+func (t *transactorImpl) WithTx(ctx context.Context, function func(ctx context.Context) error) (txErr error) {
+	// Nested calls join the outer transaction.
+	if _, err := extractTx(ctx); err == nil {
+		return function(ctx)
+	}
+
+	ctxWithTx, tx, err := injectTx(ctx, t.db)
+
+	if err != nil {
+		return fmt.Errorf("can not inject transaction, error: %w", err)
+	}
+
+	defer func() {
+		if txErr != nil {
+			txErr = errors.Join(txErr, tx.Rollback(ctx))
+			return
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			txErr = fmt.Errorf("commit transaction error: %w", err)
+		}
+	}()
+
+	if err := function(ctxWithTx); err != nil {
+		return fmt.Errorf("function execution error: %w", err)
+	}
+
+	return nil
+}
+
+type txInjector struct{}
+
+var ErrTxNotFound = errors.New("tx not found in context")
+
+func injectTx(ctx context.Context, pool *pgxpool.Pool) (context.Context, pgx.Tx, error) {
+	tx, err := pool.Begin(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return context.WithValue(ctx, txInjector{}, tx), tx, nil
+}
+
+func extractTx(ctx context.Context) (pgx.Tx, error) {
+	tx, ok := ctx.Value(txInjector{}).(pgx.Tx)
+
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+
+	return tx, nil
+}
+
+// querier is the subset of pgx shared by a pool and a transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// getQuerier returns the transaction stored in ctx, or the pool itself when
+// the call is not a part of a bigger transaction.
+func getQuerier(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, err := extractTx(ctx); err == nil {
+		return tx
+	}
+
+	return pool
+}
+
+// runInTx runs function in the transaction stored in ctx, or in a new one
+// committed right after function returns.
+func runInTx(ctx context.Context, pool *pgxpool.Pool, function func(tx pgx.Tx) error) error {
+	if tx, err := extractTx(ctx); err == nil {
+		return function(tx)
+	}
+
+	return pgx.BeginFunc(ctx, pool, function)
+}